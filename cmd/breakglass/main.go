@@ -0,0 +1,97 @@
+// Command breakglass provisions break-glass emergency accounts and their sealed one-time codes.
+//
+//	breakglass create -email ops-emergency@example.com -first-name Emergency
+//	breakglass seal -email ops-emergency@example.com
+//
+// The printed password and code are shown once only; store them sealed (e.g. in a safe) and hand the code to
+// POST /api/break-glass/unseal when the account is needed.
+package main
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/infra/db"
+	"auth-service/internal/repository"
+	"auth-service/internal/service"
+	"auth-service/internal/session"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	cfg := config.LoadConfig()
+	if err := db.Connect(cfg.DatabaseURL); err != nil {
+		fail(err)
+	}
+
+	// Revoking tokens needs the server's session store and denylist, nothing else of the auth service
+	sessionStore, err := session.Open(cfg)
+	if err != nil {
+		fail(err)
+	}
+
+	userRepo := repository.NewUserRepository(db.DB)
+	breakGlassRepo := repository.NewBreakGlassRepository(db.DB)
+	auditService := service.NewAuditService(repository.NewAuditRepository(db.DB), cfg.AlertWebhookURL)
+	tokenRevoker := service.NewTokenRevoker(userRepo, sessionStore, service.NewRevocationService(repository.NewRevocationRepository(db.DB)), auditService)
+	breakGlassService := service.NewBreakGlassService(userRepo, breakGlassRepo, auditService, tokenRevoker, cfg.BreakGlassWindow)
+
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "create":
+		flags := flag.NewFlagSet("create", flag.ExitOnError)
+		email := flags.String("email", "", "email of the break-glass account")
+		firstName := flags.String("first-name", "Break-glass", "first name of the account")
+		lastName := flags.String("last-name", "", "last name of the account")
+		_ = flags.Parse(os.Args[2:])
+		if *email == "" {
+			usage()
+		}
+
+		password, err := breakGlassService.CreateAccount(ctx, *email, *firstName, *lastName)
+		if err != nil {
+			fail(err)
+		}
+		fmt.Printf("Break-glass account %s created (disabled).\nPassword: %s\n", *email, password)
+
+	case "seal":
+		flags := flag.NewFlagSet("seal", flag.ExitOnError)
+		email := flags.String("email", "", "email of the break-glass account")
+		_ = flags.Parse(os.Args[2:])
+		if *email == "" {
+			usage()
+		}
+
+		code, err := breakGlassService.SealCode(ctx, *email)
+		if err != nil {
+			fail(err)
+		}
+		fmt.Printf("Sealed one-time code for %s (previous unused codes are void):\n%s\n", *email, code)
+
+	default:
+		usage()
+	}
+
+	// Alerts go to the webhook asynchronously; wait for them before exiting
+	flushCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	auditService.Flush(flushCtx)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: breakglass create -email <email> [-first-name <name>] [-last-name <name>]")
+	fmt.Fprintln(os.Stderr, "       breakglass seal -email <email>")
+	os.Exit(2)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "error:", err)
+	os.Exit(1)
+}
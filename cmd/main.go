@@ -5,10 +5,15 @@ import (
 	"auth-service/internal/controller"
 	"auth-service/internal/infra/db"
 	"auth-service/internal/infra/redis"
+	"auth-service/internal/job"
 	"auth-service/internal/logger"
 	"auth-service/internal/middleware"
+	"auth-service/internal/nonce"
 	"auth-service/internal/repository"
 	"auth-service/internal/service"
+	"auth-service/internal/session"
+	"auth-service/internal/token"
+	"context"
 	"github.com/gin-gonic/gin"
 	"log/slog"
 	"os"
@@ -30,24 +35,114 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Connect to Redis
-	if err := redis.InitRedis(cfg.RedisAddress, cfg.RedisPassword); err != nil {
-		slog.Error("failed to connect to redis",
+	// Open the session store selected by SESSION_STORE (connects to Redis for the redis backend)
+	sessionStore, err := session.Open(cfg)
+	if err != nil {
+		slog.Error("failed to open session store",
+			"store", cfg.SessionStore,
 			"error", err,
 		)
 		os.Exit(1)
 	}
 
+	// Used request signatures live in the same backend, so every instance refuses a replay
+	nonceStore, err := nonce.Open(cfg)
+	if err != nil {
+		slog.Error("failed to open nonce store",
+			"store", cfg.SessionStore,
+			"error", err,
+		)
+		os.Exit(1)
+	}
+
+	tokenIssuer, err := token.FromConfig(cfg)
+	if err != nil {
+		slog.Error("failed to configure token issuer",
+			"format", cfg.TokenFormat,
+			"error", err,
+		)
+		os.Exit(1)
+	}
+
+	// Without a key, services only get the unsigned X-User header
+	assertionSigner, err := token.AssertionSignerFromConfig(cfg)
+	if err != nil {
+		slog.Error("failed to configure identity assertions",
+			"error", err,
+		)
+		os.Exit(1)
+	}
+	if assertionSigner == nil {
+		slog.Warn("IDENTITY_ASSERTION_KEY is not set, introspection issues no identity assertions")
+	}
+
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db.DB)
 	roleRepo := repository.NewRoleRepository(db.DB)
 	endpointRepo := repository.NewEndpointRepository(db.DB)
+	userRoleRepo := repository.NewUserRoleRepository(db.DB)
+	auditRepo := repository.NewAuditRepository(db.DB)
+	accessRequestRepo := repository.NewAccessRequestRepository(db.DB)
+	roleApproverRepo := repository.NewRoleApproverRepository(db.DB)
+	accessReviewRepo := repository.NewAccessReviewRepository(db.DB)
+	breakGlassRepo := repository.NewBreakGlassRepository(db.DB)
+	revocationRepo := repository.NewRevocationRepository(db.DB)
+	claimMappingRepo := repository.NewClaimMappingRepository(db.DB)
+	attributeRepo := repository.NewAttributeRepository(db.DB)
+	oauthClientRepo := repository.NewOAuthClientRepository(db.DB)
+	deviceAuthorizationRepo := repository.NewDeviceAuthorizationRepository(db.DB)
+	personalAccessTokenRepo := repository.NewPersonalAccessTokenRepository(db.DB)
+	serviceAccountKeyRepo := repository.NewServiceAccountKeyRepository(db.DB)
+	serviceAccountCertificateRepo := repository.NewServiceAccountCertificateRepository(db.DB)
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, roleRepo, endpointRepo)
+	auditService := service.NewAuditService(auditRepo, cfg.AlertWebhookURL)
+	revocationService := service.NewRevocationService(revocationRepo)
+	claimMappingService := service.NewClaimMappingService(userRepo, claimMappingRepo, auditService)
+	attributeService := service.NewAttributeService(userRepo, attributeRepo, auditService)
+	authService := service.NewAuthService(userRepo, roleRepo, endpointRepo, personalAccessTokenRepo, sessionStore, tokenIssuer, revocationService, claimMappingService, attributeService, assertionSigner, auditService)
+	roleGrantService := service.NewRoleGrantService(userRepo, roleRepo, userRoleRepo, auditService, authService)
+	accessRequestService := service.NewAccessRequestService(userRepo, roleRepo, accessRequestRepo, roleApproverRepo, roleGrantService, auditService)
+	accessReviewService := service.NewAccessReviewService(userRepo, userRoleRepo, roleApproverRepo, accessReviewRepo, roleGrantService, auditService)
+	breakGlassService := service.NewBreakGlassService(userRepo, breakGlassRepo, auditService, authService, cfg.BreakGlassWindow)
+	oauthClientService := service.NewOAuthClientService(userRepo, oauthClientRepo, auditService)
+	deviceAuthorizationService := service.NewDeviceAuthorizationService(userRepo, deviceAuthorizationRepo, authService, auditService)
+	personalAccessTokenService := service.NewPersonalAccessTokenService(userRepo, roleRepo, personalAccessTokenRepo, auditService)
+	serviceAccountService := service.NewServiceAccountService(userRepo, roleRepo, serviceAccountKeyRepo, serviceAccountCertificateRepo, auditService, nonceStore)
+
+	// Stateless verification must not start without the denylist
+	ctx := context.Background()
+	if cfg.TokenMode == config.TokenModeStateless {
+		if err := revocationService.Sync(ctx); err != nil {
+			slog.Error("failed to load token denylist",
+				"error", err,
+			)
+			os.Exit(1)
+		}
+	}
+
+	// Start background jobs
+	job.NewRoleGrantJob(roleGrantService, cfg.RoleGrantSyncInterval).Start(ctx)
+	job.NewBreakGlassJob(breakGlassService, cfg.BreakGlassSyncInterval).Start(ctx)
+	job.NewRevocationJob(revocationService, cfg.RevocationSyncInterval).Start(ctx)
+	if cfg.SessionStore == config.SessionStoreRedis {
+		redis.WatchHealth(ctx, cfg.RedisHealthCheckInterval)
+	}
 
 	// Initialize controllers
-	authController := controller.NewAuthController(authService)
+	authController := controller.NewAuthController(authService, serviceAccountService)
+	authV2Controller := controller.NewAuthV2Controller(authService, serviceAccountService)
+	roleController := controller.NewRoleController(roleGrantService)
+	accessRequestController := controller.NewAccessRequestController(accessRequestService)
+	accessReviewController := controller.NewAccessReviewController(accessReviewService)
+	adminController := controller.NewAdminController(authService)
+	breakGlassController := controller.NewBreakGlassController(breakGlassService)
+	claimMappingController := controller.NewClaimMappingController(claimMappingService)
+	attributeController := controller.NewAttributeController(attributeService)
+	oauthController := controller.NewOAuthController(oauthClientService, authService, deviceAuthorizationService)
+	deviceController := controller.NewDeviceController(deviceAuthorizationService)
+	personalAccessTokenController := controller.NewPersonalAccessTokenController(personalAccessTokenService)
+	serviceAccountController := controller.NewServiceAccountController(serviceAccountService)
 
 	// Create Gin instance
 	gin.SetMode(gin.ReleaseMode)
@@ -57,14 +152,37 @@ func main() {
 		middlewares.SlogLogger(),
 		middlewares.ErrorHandler(),
 		middlewares.TransactionIDMiddleware(),
+		middlewares.ClientCertificate(cfg.GatewaySecret),
 	)
 
+	// Auth-service's own protected routes are checked against the endpoints table like any other service
+	authenticate := middlewares.Authenticate(authService, cfg.ServiceName)
+	authorize := middlewares.Authorize(authService, cfg.ServiceName)
+
 	// Register routes
 	api := r.Group("/api")
 	{
-		authController.RegisterRoutes(api)
+		authController.RegisterRoutes(api, authenticate)
+		roleController.RegisterRoutes(api, authorize)
+		accessRequestController.RegisterRoutes(api, authenticate, authorize)
+		accessReviewController.RegisterRoutes(api, authenticate, authorize)
+		adminController.RegisterRoutes(api, authorize)
+		breakGlassController.RegisterRoutes(api, authorize)
+		claimMappingController.RegisterRoutes(api, authorize)
+		attributeController.RegisterRoutes(api, authenticate, authorize)
+		oauthController.RegisterRoutes(api, authorize)
+		personalAccessTokenController.RegisterRoutes(api, authenticate)
+		serviceAccountController.RegisterRoutes(api, authorize)
+	}
+
+	apiV2 := api.Group("/v2")
+	{
+		authV2Controller.RegisterRoutes(apiV2)
 	}
 
+	// Server-rendered pages for browsers
+	deviceController.RegisterRoutes(&r.RouterGroup)
+
 	if err := r.Run(":" + cfg.AppPort); err != nil {
 		slog.Error("failed to start server",
 			"error", err,
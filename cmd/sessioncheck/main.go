@@ -0,0 +1,47 @@
+// Command sessioncheck runs the session store conformance suite (internal/session/sessiontest) against a live backend.
+//
+//	sessioncheck                  # the backend selected by SESSION_STORE
+//	sessioncheck -store memory    # or any of redis, postgres, memory
+//
+// The suite only writes keys and user ids it generates itself, so it is safe against a shared backend.
+package main
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/infra/db"
+	"auth-service/internal/session"
+	"auth-service/internal/session/sessiontest"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	cfg := config.LoadConfig()
+
+	store := flag.String("store", cfg.SessionStore, "session store backend to check (redis, postgres, memory)")
+	flag.Parse()
+	cfg.SessionStore = *store
+
+	if cfg.SessionStore == config.SessionStorePostgres {
+		if err := db.Connect(cfg.DatabaseURL); err != nil {
+			fail(err)
+		}
+	}
+
+	sessionStore, err := session.Open(cfg)
+	if err != nil {
+		fail(err)
+	}
+
+	if err := sessiontest.TestStore(context.Background(), sessionStore); err != nil {
+		fail(err)
+	}
+	fmt.Printf("%s session store: ok\n", cfg.SessionStore)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "error:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,14 @@
+DELETE FROM endpoints
+WHERE service = 'auth-service' AND path = 'api/roles/grants';
+DELETE FROM permissions
+WHERE name = 'MANAGE_ROLE_GRANTS';
+
+DROP TABLE IF EXISTS audit_logs;
+
+DROP INDEX IF EXISTS idx_user_roles_valid_until;
+ALTER TABLE user_roles
+    DROP COLUMN IF EXISTS valid_from,
+    DROP COLUMN IF EXISTS valid_until,
+    DROP COLUMN IF EXISTS activated_at,
+    DROP COLUMN IF EXISTS granted_by,
+    DROP COLUMN IF EXISTS created_at;
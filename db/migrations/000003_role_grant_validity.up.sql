@@ -0,0 +1,37 @@
+ALTER TABLE user_roles
+    ADD COLUMN valid_from TIMESTAMP,
+    ADD COLUMN valid_until TIMESTAMP,
+    ADD COLUMN activated_at TIMESTAMP,
+    ADD COLUMN granted_by INT REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+-- Existing assignments are permanent and already active
+UPDATE user_roles SET activated_at = NOW();
+
+CREATE INDEX idx_user_roles_valid_until ON user_roles (valid_until) WHERE valid_until IS NOT NULL;
+
+CREATE TABLE audit_logs (
+    audit_log_id SERIAL PRIMARY KEY,
+    action VARCHAR(100) NOT NULL,
+    actor_id INT REFERENCES users(id) ON DELETE SET NULL,
+    target_type VARCHAR(50),
+    target_id VARCHAR(100),
+    metadata JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_logs_target ON audit_logs (target_type, target_id);
+
+INSERT INTO permissions (name, description)
+VALUES
+    ('MANAGE_ROLE_GRANTS', 'Permission to grant and revoke user roles');
+
+INSERT INTO endpoints (service, path, http_method, permission_id)
+SELECT 'auth-service', e.path, e.http_method, p.permission_id
+FROM permissions p,
+     (VALUES
+        ('api/roles/grants', 'GET'),
+        ('api/roles/grants', 'POST'),
+        ('api/roles/grants', 'DELETE')
+     ) AS e(path, http_method)
+WHERE p.name = 'MANAGE_ROLE_GRANTS';
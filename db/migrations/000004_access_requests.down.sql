@@ -0,0 +1,6 @@
+DELETE FROM endpoints
+WHERE service = 'auth-service' AND path LIKE 'api/roles/:id/approvers%';
+
+DROP TABLE IF EXISTS access_request_events;
+DROP TABLE IF EXISTS access_requests;
+DROP TABLE IF EXISTS role_approvers;
@@ -0,0 +1,48 @@
+CREATE TABLE role_approvers (
+    role_id INT,
+    user_id INT,
+    PRIMARY KEY (role_id, user_id),
+    FOREIGN KEY (role_id) REFERENCES roles(role_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE access_requests (
+    access_request_id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    role_id INT NOT NULL,
+    justification TEXT NOT NULL,
+    duration_seconds INT,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    decided_by INT,
+    decided_at TIMESTAMP,
+    decision_note TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (role_id) REFERENCES roles(role_id) ON DELETE CASCADE,
+    FOREIGN KEY (decided_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_access_requests_status ON access_requests (status, role_id);
+CREATE UNIQUE INDEX idx_access_requests_pending ON access_requests (user_id, role_id) WHERE status = 'PENDING';
+
+CREATE TABLE access_request_events (
+    access_request_event_id SERIAL PRIMARY KEY,
+    access_request_id INT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    actor_id INT,
+    note TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (access_request_id) REFERENCES access_requests(access_request_id) ON DELETE CASCADE,
+    FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+INSERT INTO endpoints (service, path, http_method, permission_id)
+SELECT 'auth-service', e.path, e.http_method, p.permission_id
+FROM permissions p,
+     (VALUES
+        ('api/roles/:id/approvers', 'GET'),
+        ('api/roles/:id/approvers', 'POST'),
+        ('api/roles/:id/approvers/:userId', 'DELETE')
+     ) AS e(path, http_method)
+WHERE p.name = 'MANAGE_ROLE_GRANTS';
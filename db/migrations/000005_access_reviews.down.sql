@@ -0,0 +1,7 @@
+DELETE FROM endpoints
+WHERE service = 'auth-service' AND path LIKE 'api/access-reviews%';
+DELETE FROM permissions
+WHERE name = 'MANAGE_ACCESS_REVIEWS';
+
+DROP TABLE IF EXISTS access_review_items;
+DROP TABLE IF EXISTS access_review_campaigns;
@@ -0,0 +1,52 @@
+CREATE TABLE access_review_campaigns (
+    campaign_id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN',
+    due_at TIMESTAMP,
+    created_by INT,
+    closed_by INT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    closed_at TIMESTAMP,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL,
+    FOREIGN KEY (closed_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Items snapshot the assignment (including email and role name) so the campaign stays auditable
+-- after users or roles are removed.
+CREATE TABLE access_review_items (
+    item_id SERIAL PRIMARY KEY,
+    campaign_id INT NOT NULL,
+    user_id INT NOT NULL,
+    user_email VARCHAR(100) NOT NULL,
+    role_id INT NOT NULL,
+    role_name VARCHAR(100) NOT NULL,
+    valid_from TIMESTAMP,
+    valid_until TIMESTAMP,
+    reviewer_id INT,
+    decision VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    comment TEXT,
+    decided_at TIMESTAMP,
+    applied_at TIMESTAMP,
+    FOREIGN KEY (campaign_id) REFERENCES access_review_campaigns(campaign_id) ON DELETE CASCADE,
+    FOREIGN KEY (reviewer_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_access_review_items_campaign ON access_review_items (campaign_id);
+CREATE INDEX idx_access_review_items_reviewer ON access_review_items (reviewer_id, decision);
+
+INSERT INTO permissions (name, description)
+VALUES
+    ('MANAGE_ACCESS_REVIEWS', 'Permission to run access review campaigns');
+
+INSERT INTO endpoints (service, path, http_method, permission_id)
+SELECT 'auth-service', e.path, e.http_method, p.permission_id
+FROM permissions p,
+     (VALUES
+        ('api/access-reviews', 'GET'),
+        ('api/access-reviews', 'POST'),
+        ('api/access-reviews/:id', 'GET'),
+        ('api/access-reviews/:id/close', 'POST'),
+        ('api/access-reviews/:id/export', 'GET')
+     ) AS e(path, http_method)
+WHERE p.name = 'MANAGE_ACCESS_REVIEWS';
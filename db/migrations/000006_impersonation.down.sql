@@ -0,0 +1,4 @@
+DELETE FROM endpoints
+WHERE service = 'auth-service' AND path = 'api/admin/impersonate';
+DELETE FROM permissions
+WHERE name = 'IMPERSONATE_USERS';
@@ -0,0 +1,8 @@
+INSERT INTO permissions (name, description)
+VALUES
+    ('IMPERSONATE_USERS', 'Permission to obtain short-lived tokens acting as another user');
+
+INSERT INTO endpoints (service, path, http_method, permission_id)
+SELECT 'auth-service', 'api/admin/impersonate', 'POST', permission_id
+FROM permissions
+WHERE name = 'IMPERSONATE_USERS';
@@ -0,0 +1,11 @@
+DELETE FROM endpoints
+WHERE service = 'auth-service' AND path LIKE 'api/break-glass/%';
+DELETE FROM permissions
+WHERE name = 'MANAGE_BREAK_GLASS';
+
+DROP TABLE IF EXISTS break_glass_activations;
+DROP TABLE IF EXISTS break_glass_codes;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS account_type,
+    DROP COLUMN IF EXISTS enabled_until;
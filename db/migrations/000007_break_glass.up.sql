@@ -0,0 +1,46 @@
+-- Break-glass accounts are disabled unless enabled_until lies in the future
+ALTER TABLE users
+    ADD COLUMN account_type VARCHAR(20) NOT NULL DEFAULT 'STANDARD',
+    ADD COLUMN enabled_until TIMESTAMP;
+
+CREATE TABLE break_glass_codes (
+    code_id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    code_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    used_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE break_glass_activations (
+    activation_id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL,
+    requested_by INT,
+    approved_by INT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    activated_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (requested_by) REFERENCES users(id) ON DELETE SET NULL,
+    FOREIGN KEY (approved_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_break_glass_activations_status ON break_glass_activations (status);
+
+INSERT INTO permissions (name, description)
+VALUES
+    ('MANAGE_BREAK_GLASS', 'Permission to request and approve break-glass account activation');
+
+INSERT INTO endpoints (service, path, http_method, permission_id)
+SELECT 'auth-service', e.path, e.http_method, p.permission_id
+FROM permissions p,
+     (VALUES
+        ('api/break-glass/activations', 'GET'),
+        ('api/break-glass/activations', 'POST'),
+        ('api/break-glass/activations/:id/approve', 'POST'),
+        ('api/break-glass/accounts/:id/disable', 'POST')
+     ) AS e(path, http_method)
+WHERE p.name = 'MANAGE_BREAK_GLASS';
@@ -0,0 +1,7 @@
+DELETE FROM endpoints
+WHERE service = 'auth-service' AND path = 'api/admin/users/:id/force-logout';
+DELETE FROM permissions
+WHERE name = 'FORCE_LOGOUT';
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS token_generation;
@@ -0,0 +1,12 @@
+-- Tokens embed the generation they were issued under; bumping it invalidates all of a user's tokens
+ALTER TABLE users
+    ADD COLUMN token_generation INT NOT NULL DEFAULT 0;
+
+INSERT INTO permissions (name, description)
+VALUES
+    ('FORCE_LOGOUT', 'Permission to end all sessions of any user');
+
+INSERT INTO endpoints (service, path, http_method, permission_id)
+SELECT 'auth-service', 'api/admin/users/:id/force-logout', 'POST', permission_id
+FROM permissions
+WHERE name = 'FORCE_LOGOUT';
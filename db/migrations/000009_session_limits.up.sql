@@ -0,0 +1,3 @@
+-- Overrides SESSION_MAX_CONCURRENT for holders of the role; NULL falls back to the global limit
+ALTER TABLE roles
+    ADD COLUMN max_sessions INT NULL CHECK (max_sessions >= 0);
@@ -0,0 +1,11 @@
+-- Backing table of the postgres session store (SESSION_STORE=postgres); rows are keyed by the SHA-256 of the token
+CREATE TABLE sessions (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    session_id VARCHAR(64) UNIQUE,
+    user_id INT NOT NULL,
+    data JSONB NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX idx_sessions_expires_at ON sessions(expires_at);
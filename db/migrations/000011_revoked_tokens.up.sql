@@ -0,0 +1,15 @@
+-- Revocation denylist consulted by stateless token verification. An entry either names one token (jti)
+-- or cuts off every token of a user issued under an older token generation. Entries are kept until
+-- the tokens they cover have expired.
+CREATE TABLE revoked_tokens (
+    revoked_token_id SERIAL PRIMARY KEY,
+    jti VARCHAR(64),
+    user_id INT,
+    min_generation INT,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CHECK (jti IS NOT NULL OR (user_id IS NOT NULL AND min_generation IS NOT NULL))
+);
+
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
@@ -0,0 +1,6 @@
+DELETE FROM endpoints
+WHERE service = 'auth-service' AND path LIKE 'api/claim-mappings%';
+DELETE FROM permissions
+WHERE name = 'MANAGE_CLAIM_MAPPINGS';
+
+DROP TABLE IF EXISTS claim_mappings;
@@ -0,0 +1,26 @@
+-- Custom claims per audience (a client service, or '*' for every token). source names where the value comes from,
+-- e.g. 'user.email', 'roles' or 'const:acme'.
+CREATE TABLE claim_mappings (
+    claim_mapping_id SERIAL PRIMARY KEY,
+    audience VARCHAR(100) NOT NULL,
+    claim VARCHAR(100) NOT NULL,
+    source VARCHAR(255) NOT NULL,
+    created_by INT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL,
+    UNIQUE (audience, claim)
+);
+
+INSERT INTO permissions (name, description)
+VALUES
+    ('MANAGE_CLAIM_MAPPINGS', 'Permission to manage the custom claims issued per audience');
+
+INSERT INTO endpoints (service, path, http_method, permission_id)
+SELECT 'auth-service', e.path, e.http_method, p.permission_id
+FROM permissions p,
+     (VALUES
+        ('api/claim-mappings', 'GET'),
+        ('api/claim-mappings', 'POST'),
+        ('api/claim-mappings/:id', 'DELETE')
+     ) AS e(path, http_method)
+WHERE p.name = 'MANAGE_CLAIM_MAPPINGS';
@@ -0,0 +1,9 @@
+DELETE FROM endpoints
+WHERE service = 'auth-service' AND (path LIKE 'api/attribute-definitions%' OR path = 'api/admin/users/:id/attributes');
+DELETE FROM permissions
+WHERE name = 'MANAGE_USER_ATTRIBUTES';
+
+DROP TABLE IF EXISTS attribute_definitions;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS attributes;
@@ -0,0 +1,30 @@
+-- Custom profile attributes: admins define them in attribute_definitions, values live in users.attributes
+ALTER TABLE users
+    ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}';
+
+CREATE TABLE attribute_definitions (
+    attribute_definition_id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    type VARCHAR(20) NOT NULL,
+    description VARCHAR(255),
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    user_editable BOOLEAN NOT NULL DEFAULT TRUE,
+    exposed_in_tokens BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO permissions (name, description)
+VALUES
+    ('MANAGE_USER_ATTRIBUTES', 'Permission to define custom user attributes and edit them for any user');
+
+INSERT INTO endpoints (service, path, http_method, permission_id)
+SELECT 'auth-service', e.path, e.http_method, p.permission_id
+FROM permissions p,
+     (VALUES
+        ('api/attribute-definitions', 'GET'),
+        ('api/attribute-definitions', 'POST'),
+        ('api/attribute-definitions/:id', 'DELETE'),
+        ('api/admin/users/:id/attributes', 'GET'),
+        ('api/admin/users/:id/attributes', 'PATCH')
+     ) AS e(path, http_method)
+WHERE p.name = 'MANAGE_USER_ATTRIBUTES';
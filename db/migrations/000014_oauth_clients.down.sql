@@ -0,0 +1,6 @@
+DELETE FROM endpoints
+WHERE service = 'auth-service' AND path LIKE 'api/oauth/clients%';
+DELETE FROM permissions
+WHERE name = 'MANAGE_OAUTH_CLIENTS';
+
+DROP TABLE IF EXISTS oauth_clients;
@@ -0,0 +1,27 @@
+-- OAuth clients are the services that call the token endpoint, e.g. to exchange a user's token for one scoped
+-- to a downstream service. name is the service name: exchanged tokens name it as actor, and the tokens it
+-- exchanges must have been issued for it. Secrets are stored as SHA-256 hashes.
+CREATE TABLE oauth_clients (
+    oauth_client_id SERIAL PRIMARY KEY,
+    client_id VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    secret_hash VARCHAR(64) NOT NULL,
+    allowed_audiences TEXT NOT NULL DEFAULT '',
+    created_by INT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+INSERT INTO permissions (name, description)
+VALUES
+    ('MANAGE_OAUTH_CLIENTS', 'Permission to manage the OAuth clients allowed to exchange tokens');
+
+INSERT INTO endpoints (service, path, http_method, permission_id)
+SELECT 'auth-service', e.path, e.http_method, p.permission_id
+FROM permissions p,
+     (VALUES
+        ('api/oauth/clients', 'GET'),
+        ('api/oauth/clients', 'POST'),
+        ('api/oauth/clients/:id', 'DELETE')
+     ) AS e(path, http_method)
+WHERE p.name = 'MANAGE_OAUTH_CLIENTS';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS device_authorizations;
+
+ALTER TABLE oauth_clients
+    DROP COLUMN IF EXISTS public;
@@ -0,0 +1,23 @@
+-- Public OAuth clients (CLIs) have no secret and may only use the device authorization grant
+ALTER TABLE oauth_clients
+    ADD COLUMN public BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Pending and decided device authorizations (RFC 8628). The device code is stored as a SHA-256 hash;
+-- the user code is what the user types on the approval page. status is PENDING, APPROVED, DENIED or USED.
+CREATE TABLE device_authorizations (
+    device_authorization_id SERIAL PRIMARY KEY,
+    device_code_hash VARCHAR(64) NOT NULL UNIQUE,
+    user_code VARCHAR(9) NOT NULL UNIQUE,
+    oauth_client_id INT NOT NULL,
+    scope TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    user_id INT,
+    poll_interval INT NOT NULL,
+    last_polled_at TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (oauth_client_id) REFERENCES oauth_clients(oauth_client_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_device_authorizations_expires_at ON device_authorizations(expires_at);
@@ -0,0 +1,9 @@
+DELETE FROM endpoints
+WHERE service = 'auth-service' AND path LIKE 'api/service-accounts%';
+DELETE FROM permissions
+WHERE name = 'MANAGE_SERVICE_ACCOUNTS';
+
+DROP TABLE IF EXISTS service_account_keys;
+
+DELETE FROM users
+WHERE account_type = 'SERVICE';
@@ -0,0 +1,34 @@
+-- Service accounts are users with account_type 'SERVICE': no password, roles granted like any user's.
+-- They authenticate with API keys; the key secret is derived from SERVICE_ACCOUNT_KEY_SECRET and the key_id,
+-- so only the key_id is stored.
+CREATE TABLE service_account_keys (
+    service_account_key_id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    key_id VARCHAR(64) NOT NULL UNIQUE,
+    description VARCHAR(255),
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_by INT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_service_account_keys_user_id ON service_account_keys(user_id);
+
+INSERT INTO permissions (name, description)
+VALUES
+    ('MANAGE_SERVICE_ACCOUNTS', 'Permission to manage service accounts and their API keys');
+
+INSERT INTO endpoints (service, path, http_method, permission_id)
+SELECT 'auth-service', e.path, e.http_method, p.permission_id
+FROM permissions p,
+     (VALUES
+        ('api/service-accounts', 'GET'),
+        ('api/service-accounts', 'POST'),
+        ('api/service-accounts/:id', 'DELETE'),
+        ('api/service-accounts/:id/keys', 'GET'),
+        ('api/service-accounts/:id/keys', 'POST'),
+        ('api/service-accounts/:id/keys/:keyId', 'DELETE')
+     ) AS e(path, http_method)
+WHERE p.name = 'MANAGE_SERVICE_ACCOUNTS';
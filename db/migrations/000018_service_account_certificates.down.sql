@@ -0,0 +1,4 @@
+DELETE FROM endpoints
+WHERE service = 'auth-service' AND path LIKE 'api/service-accounts/:id/certificates%';
+
+DROP TABLE IF EXISTS service_account_certificates;
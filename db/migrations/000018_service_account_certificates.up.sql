@@ -0,0 +1,25 @@
+-- Client certificates mapped to service accounts, matched by SHA-256 fingerprint (lowercase hex), URI SAN
+-- (such as a SPIFFE ID) or subject distinguished name (RFC 2253).
+CREATE TABLE service_account_certificates (
+    service_account_certificate_id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    match_type VARCHAR(20) NOT NULL,
+    match_value VARCHAR(1024) NOT NULL,
+    created_by INT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (match_type, match_value),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_service_account_certificates_user_id ON service_account_certificates(user_id);
+
+INSERT INTO endpoints (service, path, http_method, permission_id)
+SELECT 'auth-service', e.path, e.http_method, p.permission_id
+FROM permissions p,
+     (VALUES
+        ('api/service-accounts/:id/certificates', 'GET'),
+        ('api/service-accounts/:id/certificates', 'POST'),
+        ('api/service-accounts/:id/certificates/:certificateId', 'DELETE')
+     ) AS e(path, http_method)
+WHERE p.name = 'MANAGE_SERVICE_ACCOUNTS';
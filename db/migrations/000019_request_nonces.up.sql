@@ -0,0 +1,7 @@
+-- Signatures of signed requests already used, shared by every instance when SESSION_STORE=postgres
+CREATE TABLE request_nonces (
+    nonce VARCHAR(255) PRIMARY KEY,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_request_nonces_expires_at ON request_nonces(expires_at);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS break_glass_unseal_attempts;
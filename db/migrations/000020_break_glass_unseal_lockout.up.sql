@@ -0,0 +1,7 @@
+-- Failed unseal attempts per break-glass account; too many of them lock unsealing for a while
+CREATE TABLE break_glass_unseal_attempts (
+    user_id INT PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
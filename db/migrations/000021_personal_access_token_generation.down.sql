@@ -0,0 +1,2 @@
+ALTER TABLE personal_access_tokens
+    DROP COLUMN IF EXISTS generation;
@@ -0,0 +1,9 @@
+-- Personal access tokens remember the owner's token generation, so bumping it ends them like every other token.
+-- Existing tokens take the owner's current generation and stay valid.
+ALTER TABLE personal_access_tokens
+    ADD COLUMN generation INT NOT NULL DEFAULT 0;
+
+UPDATE personal_access_tokens
+SET generation = users.token_generation
+FROM users
+WHERE users.id = personal_access_tokens.user_id;
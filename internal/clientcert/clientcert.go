@@ -0,0 +1,104 @@
+// Package clientcert carries the client certificate a TLS-terminating gateway verified and forwarded to
+// auth-service, either in full or as its SHA-256 fingerprint.
+package clientcert
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// CertificateHeader carries the URL-escaped PEM certificate, as nginx's $ssl_client_escaped_cert
+	CertificateHeader = "X-Client-Cert"
+	// FingerprintHeader carries the hex-encoded SHA-256 fingerprint of the certificate, for gateways that only forward that
+	FingerprintHeader = "X-Client-Cert-Fingerprint"
+	// GatewaySecretHeader proves the certificate headers were set by the gateway
+	GatewaySecretHeader = "X-Gateway-Secret"
+
+	// ContextKey is the gin context key holding the forwarded Certificate
+	ContextKey = "client_certificate"
+)
+
+// ErrInvalid is returned for forwarded certificates or fingerprints that cannot be parsed
+var ErrInvalid = errors.New("invalid client certificate")
+
+// Certificate is what auth-service knows of a client certificate. Subject and URIs are empty when only the
+// fingerprint was forwarded.
+type Certificate struct {
+	// Fingerprint is the lowercase hex SHA-256 of the DER certificate
+	Fingerprint string
+	// Subject is the distinguished name in RFC 2253 form, e.g. "CN=billing,O=Example"
+	Subject string
+	// URIs are the URI SANs, such as SPIFFE IDs
+	URIs []string
+}
+
+// Thumbprint returns the "x5t#S256" confirmation of the certificate (RFC 8705 section 3.1)
+func (c Certificate) Thumbprint() string {
+	raw, _ := hex.DecodeString(c.Fingerprint)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Parse reads a URL-escaped PEM certificate
+func Parse(escapedPEM string) (Certificate, error) {
+	unescaped, err := url.PathUnescape(escapedPEM)
+	if err != nil {
+		return Certificate{}, ErrInvalid
+	}
+	block, _ := pem.Decode([]byte(unescaped))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return Certificate{}, ErrInvalid
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return Certificate{}, ErrInvalid
+	}
+
+	sum := sha256.Sum256(cert.Raw)
+	uris := make([]string, len(cert.URIs))
+	for i, uri := range cert.URIs {
+		uris[i] = uri.String()
+	}
+	return Certificate{
+		Fingerprint: hex.EncodeToString(sum[:]),
+		Subject:     cert.Subject.String(),
+		URIs:        uris,
+	}, nil
+}
+
+// FromFingerprint builds the certificate known only by its fingerprint
+func FromFingerprint(fingerprint string) (Certificate, error) {
+	normalized, err := NormalizeFingerprint(fingerprint)
+	if err != nil {
+		return Certificate{}, err
+	}
+	return Certificate{Fingerprint: normalized}, nil
+}
+
+// NormalizeFingerprint turns a hex SHA-256 fingerprint, with or without colons, into lowercase hex
+func NormalizeFingerprint(fingerprint string) (string, error) {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(fingerprint), ":", ""))
+	raw, err := hex.DecodeString(normalized)
+	if err != nil || len(raw) != sha256.Size {
+		return "", ErrInvalid
+	}
+	return normalized, nil
+}
+
+// FromContext returns the certificate the gateway forwarded with the request, if any
+func FromContext(c *gin.Context) (Certificate, bool) {
+	value, ok := c.Get(ContextKey)
+	if !ok {
+		return Certificate{}, false
+	}
+	cert, ok := value.(Certificate)
+	return cert, ok
+}
@@ -0,0 +1,95 @@
+package clientcert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newCertificatePEM(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spiffe, _ := url.Parse("spiffe://example.com/billing")
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "billing", Organization: []string{"Example"}},
+		URIs:         []*url.URL{spiffe},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), der
+}
+
+func TestParse(t *testing.T) {
+	certPEM, der := newCertificatePEM(t)
+	sum := sha256.Sum256(der)
+
+	cert, err := Parse(url.PathEscape(string(certPEM)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if cert.Fingerprint != hex.EncodeToString(sum[:]) {
+		t.Errorf("Fingerprint = %q", cert.Fingerprint)
+	}
+	if cert.Subject != "CN=billing,O=Example" {
+		t.Errorf("Subject = %q", cert.Subject)
+	}
+	if !slices.Equal(cert.URIs, []string{"spiffe://example.com/billing"}) {
+		t.Errorf("URIs = %v", cert.URIs)
+	}
+	if cert.Thumbprint() != base64.RawURLEncoding.EncodeToString(sum[:]) {
+		t.Errorf("Thumbprint() = %q", cert.Thumbprint())
+	}
+
+	for _, invalid := range []string{"", "%zz", "not a certificate", url.PathEscape(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})))} {
+		if _, err := Parse(invalid); err != ErrInvalid {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalid", invalid, err)
+		}
+	}
+}
+
+func TestNormalizeFingerprint(t *testing.T) {
+	want := strings.Repeat("abcdef0123456789", 4)
+
+	tests := []struct {
+		name        string
+		fingerprint string
+		want        string
+		valid       bool
+	}{
+		{"lowercase hex", want, want, true},
+		{"uppercase with colons", "AB:CD:EF:01:23:45:67:89:AB:CD:EF:01:23:45:67:89:AB:CD:EF:01:23:45:67:89:AB:CD:EF:01:23:45:67:89", want, true},
+		{"surrounding spaces", " " + want + " ", want, true},
+		{"too short", want[:62], "", false},
+		{"not hex", "zz" + want[2:], "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeFingerprint(tt.fingerprint)
+			if (err == nil) != tt.valid || got != tt.want {
+				t.Errorf("NormalizeFingerprint() = %q, %v; want %q, valid %v", got, err, tt.want, tt.valid)
+			}
+		})
+	}
+}
@@ -4,6 +4,38 @@ import (
 	"github.com/joho/godotenv"
 	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Session limit policies applied when a login would exceed SessionMaxConcurrent
+const (
+	SessionLimitEvictOldest = "evict_oldest"
+	SessionLimitReject      = "reject"
+)
+
+// Session store backends selectable with SESSION_STORE
+const (
+	SessionStoreRedis    = "redis"
+	SessionStorePostgres = "postgres"
+	SessionStoreMemory   = "memory"
+)
+
+// Token modes selectable with TOKEN_MODE
+const (
+	// TokenModeSession verifies every token against its stored session
+	TokenModeSession = "session"
+	// TokenModeStateless trusts a valid token on its own, checking only the replicated revocation denylist
+	TokenModeStateless = "stateless"
+)
+
+// Token formats selectable with TOKEN_FORMAT
+const (
+	TokenFormatJWT          = "jwt"
+	TokenFormatOpaque       = "opaque"
+	TokenFormatPasetoPublic = "paseto.v4.public"
+	TokenFormatPasetoLocal  = "paseto.v4.local"
 )
 
 // Config holds all configuration values
@@ -14,6 +46,101 @@ type Config struct {
 	Environment   string
 	RedisAddress  string
 	RedisPassword string
+	RedisDB       int
+
+	// RedisSentinelMaster switches to a sentinel-managed master found through RedisSentinelAddrs;
+	// RedisClusterAddrs switches to Redis Cluster. Neither set means the single node at RedisAddress.
+	RedisSentinelMaster      string
+	RedisSentinelAddrs       []string
+	RedisSentinelPassword    string
+	RedisClusterAddrs        []string
+	RedisTLS                 bool
+	RedisTLSServerName       string
+	RedisTLSCAFile           string
+	RedisPoolSize            int
+	RedisMinIdleConns        int
+	RedisHealthCheckInterval time.Duration
+
+	// TokenFormat is the format new tokens are issued in; tokens of either format are accepted at any time.
+	// Opaque tokens are always verified against their session, whatever the token mode.
+	TokenFormat string
+	// PASETO keys, hex-encoded: the 32-byte v4.local key, the Ed25519 v4.public secret key (32-byte seed or
+	// 64-byte key) and, for instances that only verify, the 32-byte v4.public key. Every configured key is accepted.
+	PasetoLocalKey  string
+	PasetoSecretKey string
+	PasetoPublicKey string
+	// TokenIssuer is the "iss" of issued tokens. TokenAudiences, when set, are the "aud" of issued tokens and the
+	// audiences tokens are validated against; TokenClockSkew is the leeway allowed on exp, nbf and iat.
+	// TokenStrictClaims rejects tokens of the old format, which carry none of the registered claims but exp.
+	TokenIssuer       string
+	TokenAudiences    []string
+	TokenClockSkew    time.Duration
+	TokenStrictClaims bool
+	// TokenMode is TokenModeSession or TokenModeStateless. Stateless tokens live at most AccessTokenTTL;
+	// the denylist they are checked against is refreshed every RevocationSyncInterval.
+	TokenMode              string
+	AccessTokenTTL         time.Duration
+	RevocationSyncInterval time.Duration
+
+	// SessionStore selects where sessions live; SessionKeyPrefix namespaces every session key written to Redis
+	SessionStore     string
+	SessionKeyPrefix string
+	// SessionMaxLifetime is the absolute lifetime of a session; SessionIdleTimeout ends it earlier after inactivity (0 disables).
+	// Activity is written back at most once per SessionTouchInterval.
+	SessionMaxLifetime   time.Duration
+	SessionIdleTimeout   time.Duration
+	SessionTouchInterval time.Duration
+	// SessionMaxConcurrent caps the active sessions per user (0 = unlimited), overridable per role.
+	// SessionLimitPolicy is either SessionLimitEvictOldest or SessionLimitReject.
+	SessionMaxConcurrent int
+	SessionLimitPolicy   string
+
+	// ClaimMappingRefreshInterval is how long custom claim mappings are cached before they are reloaded
+	ClaimMappingRefreshInterval time.Duration
+
+	// IdentityAssertionKey is the hex-encoded Ed25519 key (32-byte seed or 64-byte key) introspection signs
+	// X-User-Assertion with; assertions are not issued without it. They live IdentityAssertionTTL.
+	IdentityAssertionKey   string
+	IdentityAssertionKeyID string
+	IdentityAssertionTTL   time.Duration
+
+	// TokenExchangeTTL is the lifetime of tokens issued by token exchange; they never outlive the exchanged token
+	TokenExchangeTTL time.Duration
+
+	// DeviceCodeTTL is how long a device authorization waits for the user; devices poll at most every DevicePollInterval.
+	// DeviceVerificationURI is the approval page users are sent to, by default /device on the host the device called.
+	DeviceCodeTTL         time.Duration
+	DevicePollInterval    time.Duration
+	DeviceVerificationURI string
+
+	// PersonalAccessTokenMaxLifetime caps the expiry users can choose for their personal access tokens
+	PersonalAccessTokenMaxLifetime time.Duration
+
+	// ServiceAccountKeySecret is the hex-encoded master secret service account API key secrets are derived from;
+	// API keys are disabled without it. Signed requests are accepted RequestSignatureWindow either side of their timestamp.
+	ServiceAccountKeySecret string
+	RequestSignatureWindow  time.Duration
+
+	// GatewaySecret authenticates the gateway forwarding verified client certificates; without it they are ignored.
+	// CertificateBoundTokens binds tokens issued to requests with a client certificate to it (RFC 8705).
+	GatewaySecret          string
+	CertificateBoundTokens bool
+
+	// ServiceName is the name auth-service's own endpoints are registered under in the endpoints table
+	ServiceName           string
+	RoleGrantSyncInterval time.Duration
+
+	ImpersonationTokenTTL time.Duration
+	// ImpersonationProtectedRoles are roles whose holders can never be impersonated
+	ImpersonationProtectedRoles []string
+
+	// BreakGlassWindow is how long a break-glass account stays enabled after activation
+	BreakGlassWindow       time.Duration
+	BreakGlassSyncInterval time.Duration
+	// BreakGlassUnsealMaxAttempts failed unseal attempts lock unsealing the account for BreakGlassUnsealLockout
+	BreakGlassUnsealMaxAttempts int
+	BreakGlassUnsealLockout     time.Duration
+	AlertWebhookURL             string
 }
 
 // LoadConfig loads variables from .env into Config struct
@@ -30,6 +157,70 @@ func LoadConfig() Config {
 		Environment:   getEnv("ENVIRONMENT", "development"),
 		RedisAddress:  getEnv("REDIS_ADDR", "localhost:6379"),
 		RedisPassword: getEnv("REDIS_PASS", ""),
+		RedisDB:       getEnvInt("REDIS_DB", 0),
+
+		RedisSentinelMaster:      getEnv("REDIS_SENTINEL_MASTER", ""),
+		RedisSentinelAddrs:       getEnvList("REDIS_SENTINEL_ADDRS", nil),
+		RedisSentinelPassword:    getEnv("REDIS_SENTINEL_PASS", ""),
+		RedisClusterAddrs:        getEnvList("REDIS_CLUSTER_ADDRS", nil),
+		RedisTLS:                 getEnvBool("REDIS_TLS", false),
+		RedisTLSServerName:       getEnv("REDIS_TLS_SERVER_NAME", ""),
+		RedisTLSCAFile:           getEnv("REDIS_TLS_CA_FILE", ""),
+		RedisPoolSize:            getEnvInt("REDIS_POOL_SIZE", 0),
+		RedisMinIdleConns:        getEnvInt("REDIS_MIN_IDLE_CONNS", 0),
+		RedisHealthCheckInterval: getEnvDuration("REDIS_HEALTH_CHECK_INTERVAL", 30*time.Second),
+
+		TokenFormat:            getEnv("TOKEN_FORMAT", TokenFormatJWT),
+		PasetoLocalKey:         getEnv("PASETO_LOCAL_KEY", ""),
+		PasetoSecretKey:        getEnv("PASETO_SECRET_KEY", ""),
+		PasetoPublicKey:        getEnv("PASETO_PUBLIC_KEY", ""),
+		TokenIssuer:            getEnv("TOKEN_ISSUER", "auth-service"),
+		TokenAudiences:         getEnvList("TOKEN_AUDIENCES", nil),
+		TokenClockSkew:         getEnvDuration("TOKEN_CLOCK_SKEW", 30*time.Second),
+		TokenStrictClaims:      getEnvBool("TOKEN_STRICT_CLAIMS", false),
+		TokenMode:              getEnv("TOKEN_MODE", TokenModeSession),
+		AccessTokenTTL:         getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RevocationSyncInterval: getEnvDuration("REVOCATION_SYNC_INTERVAL", 5*time.Second),
+
+		SessionStore:         getEnv("SESSION_STORE", SessionStoreRedis),
+		SessionKeyPrefix:     getEnv("SESSION_KEY_PREFIX", "auth:sess:"),
+		SessionMaxLifetime:   getEnvDuration("SESSION_MAX_LIFETIME", 12*time.Hour),
+		SessionIdleTimeout:   getEnvDuration("SESSION_IDLE_TIMEOUT", 0),
+		SessionTouchInterval: getEnvDuration("SESSION_TOUCH_INTERVAL", time.Minute),
+		SessionMaxConcurrent: getEnvInt("SESSION_MAX_CONCURRENT", 0),
+		SessionLimitPolicy:   getEnv("SESSION_LIMIT_POLICY", SessionLimitEvictOldest),
+
+		ClaimMappingRefreshInterval: getEnvDuration("CLAIM_MAPPING_REFRESH_INTERVAL", 30*time.Second),
+
+		IdentityAssertionKey:   getEnv("IDENTITY_ASSERTION_KEY", ""),
+		IdentityAssertionKeyID: getEnv("IDENTITY_ASSERTION_KEY_ID", ""),
+		IdentityAssertionTTL:   getEnvDuration("IDENTITY_ASSERTION_TTL", time.Minute),
+
+		TokenExchangeTTL: getEnvDuration("TOKEN_EXCHANGE_TTL", 5*time.Minute),
+
+		DeviceCodeTTL:         getEnvDuration("DEVICE_CODE_TTL", 10*time.Minute),
+		DevicePollInterval:    getEnvDuration("DEVICE_POLL_INTERVAL", 5*time.Second),
+		DeviceVerificationURI: getEnv("DEVICE_VERIFICATION_URI", ""),
+
+		PersonalAccessTokenMaxLifetime: getEnvDuration("PERSONAL_ACCESS_TOKEN_MAX_LIFETIME", 365*24*time.Hour),
+
+		ServiceAccountKeySecret: getEnv("SERVICE_ACCOUNT_KEY_SECRET", ""),
+		RequestSignatureWindow:  getEnvDuration("REQUEST_SIGNATURE_WINDOW", 5*time.Minute),
+
+		GatewaySecret:          getEnv("GATEWAY_SECRET", ""),
+		CertificateBoundTokens: getEnvBool("CERTIFICATE_BOUND_TOKENS", false),
+
+		ServiceName:           getEnv("SERVICE_NAME", "auth-service"),
+		RoleGrantSyncInterval: getEnvDuration("ROLE_GRANT_SYNC_INTERVAL", time.Minute),
+
+		ImpersonationTokenTTL:       getEnvDuration("IMPERSONATION_TOKEN_TTL", 15*time.Minute),
+		ImpersonationProtectedRoles: getEnvList("IMPERSONATION_PROTECTED_ROLES", []string{"SUPERADMIN", "ADMIN"}),
+
+		BreakGlassWindow:            getEnvDuration("BREAK_GLASS_WINDOW", time.Hour),
+		BreakGlassSyncInterval:      getEnvDuration("BREAK_GLASS_SYNC_INTERVAL", time.Minute),
+		BreakGlassUnsealMaxAttempts: getEnvInt("BREAK_GLASS_UNSEAL_MAX_ATTEMPTS", 5),
+		BreakGlassUnsealLockout:     getEnvDuration("BREAK_GLASS_UNSEAL_LOCKOUT", 15*time.Minute),
+		AlertWebhookURL:             getEnv("ALERT_WEBHOOK_URL", ""),
 	}
 
 	return config
@@ -41,4 +232,70 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// getEnvList splits a comma-separated variable, dropping empty items
+func getEnvList(key string, defaultValue []string) []string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getEnvInt parses an integer, falling back to the default when unset or invalid
+func getEnvInt(key string, defaultValue int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	number, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		slog.Error("invalid integer in environment, using default",
+			"key", key,
+			"value", value,
+		)
+		return defaultValue
+	}
+	return number
+}
+
+// getEnvBool parses a boolean such as "true" or "1", falling back to the default when unset or invalid
+func getEnvBool(key string, defaultValue bool) bool {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		slog.Error("invalid boolean in environment, using default",
+			"key", key,
+			"value", value,
+		)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvDuration parses a duration such as "90s" or "2h", falling back to the default when unset or invalid
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		slog.Error("invalid duration in environment, using default",
+			"key", key,
+			"value", value,
+		)
+		return defaultValue
+	}
+	return duration
+}
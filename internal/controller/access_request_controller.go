@@ -0,0 +1,251 @@
+package controller
+
+import (
+	middlewares "auth-service/internal/middleware"
+	"auth-service/internal/model"
+	requestDto "auth-service/internal/model/dto/request"
+	responseDto "auth-service/internal/model/dto/response"
+	"auth-service/internal/service"
+	"auth-service/pkg/utils/exception"
+	"auth-service/pkg/utils/response"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccessRequestController struct {
+	accessRequestService service.AccessRequestService
+}
+
+func NewAccessRequestController(accessRequestService service.AccessRequestService) *AccessRequestController {
+	return &AccessRequestController{accessRequestService}
+}
+
+func (arc *AccessRequestController) RegisterRoutes(r *gin.RouterGroup, authenticate gin.HandlerFunc, authorize gin.HandlerFunc) {
+	accessRequestGroup := r.Group("/access-requests", authenticate)
+	{
+		accessRequestGroup.POST("", arc.Create)
+		accessRequestGroup.GET("", arc.ListForApprover)
+		accessRequestGroup.GET("/mine", arc.ListMine)
+		accessRequestGroup.GET("/:id", arc.Get)
+		accessRequestGroup.POST("/:id/approve", arc.Approve)
+		accessRequestGroup.POST("/:id/reject", arc.Reject)
+		accessRequestGroup.POST("/:id/cancel", arc.Cancel)
+	}
+
+	approverGroup := r.Group("/roles/:id/approvers", authorize)
+	{
+		approverGroup.GET("", arc.ListApprovers)
+		approverGroup.POST("", arc.AddApprover)
+		approverGroup.DELETE("/:userId", arc.RemoveApprover)
+	}
+}
+
+func (arc *AccessRequestController) Create(c *gin.Context) {
+	var req requestDto.CreateAccessRequestRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	accessRequest, err := arc.accessRequestService.CreateRequest(c, middlewares.AuthUser(c).Email, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, gin.H{"access_request": toAccessRequestResponse(accessRequest)}, "Access request submitted")
+}
+
+func (arc *AccessRequestController) ListForApprover(c *gin.Context) {
+	accessRequests, err := arc.accessRequestService.ListForApprover(c, middlewares.AuthUser(c).Email, c.Query("status"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"access_requests": toAccessRequestResponses(accessRequests)})
+}
+
+func (arc *AccessRequestController) ListMine(c *gin.Context) {
+	accessRequests, err := arc.accessRequestService.ListMine(c, middlewares.AuthUser(c).Email)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"access_requests": toAccessRequestResponses(accessRequests)})
+}
+
+func (arc *AccessRequestController) Get(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	accessRequest, err := arc.accessRequestService.Get(c, middlewares.AuthUser(c).Email, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"access_request": toAccessRequestResponse(accessRequest)})
+}
+
+func (arc *AccessRequestController) Approve(c *gin.Context) {
+	arc.decide(c, arc.accessRequestService.Approve, "Access request approved")
+}
+
+func (arc *AccessRequestController) Reject(c *gin.Context) {
+	arc.decide(c, arc.accessRequestService.Reject, "Access request rejected")
+}
+
+func (arc *AccessRequestController) Cancel(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	accessRequest, err := arc.accessRequestService.Cancel(c, middlewares.AuthUser(c).Email, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"access_request": toAccessRequestResponse(accessRequest)}, "Access request cancelled")
+}
+
+func (arc *AccessRequestController) ListApprovers(c *gin.Context) {
+	roleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	approvers, err := arc.accessRequestService.ListApprovers(c, roleID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	approverResponses := make([]responseDto.RoleApproverResponse, len(approvers))
+	for i, approver := range approvers {
+		approverResponses[i] = responseDto.RoleApproverResponse{
+			UserID:    approver.UserID,
+			Email:     approver.User.Email,
+			FirstName: approver.User.FirstName,
+			LastName:  approver.User.LastName,
+		}
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"approvers": approverResponses})
+}
+
+func (arc *AccessRequestController) AddApprover(c *gin.Context) {
+	roleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req requestDto.AddRoleApproverRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	if err := arc.accessRequestService.AddApprover(c, middlewares.AuthUser(c).Email, roleID, req.UserID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, nil, "Approver added")
+}
+
+func (arc *AccessRequestController) RemoveApprover(c *gin.Context) {
+	roleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := paramID(c, "userId")
+	if !ok {
+		return
+	}
+
+	if err := arc.accessRequestService.RemoveApprover(c, middlewares.AuthUser(c).Email, roleID, userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil, "Approver removed")
+}
+
+func (arc *AccessRequestController) decide(c *gin.Context, decideFunc func(*gin.Context, string, uint, requestDto.DecideAccessRequestRequest) (model.AccessRequest, error), message string) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req requestDto.DecideAccessRequestRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(exception.ErrBadRequest)
+			return
+		}
+	}
+
+	accessRequest, err := decideFunc(c, middlewares.AuthUser(c).Email, id, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"access_request": toAccessRequestResponse(accessRequest)}, message)
+}
+
+// paramID parses a numeric path parameter, reporting a bad request when it is malformed
+func paramID(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil {
+		c.Error(exception.NewBadRequest("Invalid " + name))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func toAccessRequestResponses(accessRequests []model.AccessRequest) []responseDto.AccessRequestResponse {
+	accessRequestResponses := make([]responseDto.AccessRequestResponse, len(accessRequests))
+	for i, accessRequest := range accessRequests {
+		accessRequestResponses[i] = toAccessRequestResponse(accessRequest)
+	}
+	return accessRequestResponses
+}
+
+func toAccessRequestResponse(accessRequest model.AccessRequest) responseDto.AccessRequestResponse {
+	accessRequestResponse := responseDto.AccessRequestResponse{
+		ID:            accessRequest.AccessRequestID,
+		UserID:        accessRequest.UserID,
+		UserEmail:     accessRequest.User.Email,
+		RoleID:        accessRequest.RoleID,
+		RoleName:      accessRequest.Role.Name,
+		Justification: accessRequest.Justification,
+		Status:        accessRequest.Status,
+		DecidedBy:     accessRequest.DecidedBy,
+		DecidedAt:     accessRequest.DecidedAt,
+		DecisionNote:  accessRequest.DecisionNote,
+		CreatedAt:     accessRequest.CreatedAt,
+	}
+	if accessRequest.DurationSeconds != nil {
+		accessRequestResponse.Duration = (time.Duration(*accessRequest.DurationSeconds) * time.Second).String()
+	}
+	for _, event := range accessRequest.Events {
+		accessRequestResponse.Events = append(accessRequestResponse.Events, responseDto.AccessRequestEventResponse{
+			Status:    event.Status,
+			ActorID:   event.ActorID,
+			Note:      event.Note,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+	return accessRequestResponse
+}
@@ -0,0 +1,275 @@
+package controller
+
+import (
+	middlewares "auth-service/internal/middleware"
+	"auth-service/internal/model"
+	requestDto "auth-service/internal/model/dto/request"
+	responseDto "auth-service/internal/model/dto/response"
+	"auth-service/internal/service"
+	"auth-service/pkg/utils/exception"
+	"auth-service/pkg/utils/response"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccessReviewController struct {
+	accessReviewService service.AccessReviewService
+}
+
+func NewAccessReviewController(accessReviewService service.AccessReviewService) *AccessReviewController {
+	return &AccessReviewController{accessReviewService}
+}
+
+func (arc *AccessReviewController) RegisterRoutes(r *gin.RouterGroup, authenticate gin.HandlerFunc, authorize gin.HandlerFunc) {
+	accessReviewGroup := r.Group("/access-reviews")
+	{
+		// Campaign administration
+		accessReviewGroup.POST("", authorize, arc.StartCampaign)
+		accessReviewGroup.GET("", authorize, arc.ListCampaigns)
+		accessReviewGroup.GET("/:id", authorize, arc.GetCampaign)
+		accessReviewGroup.POST("/:id/close", authorize, arc.CloseCampaign)
+		accessReviewGroup.GET("/:id/export", authorize, arc.ExportCampaign)
+
+		// Reviewers
+		accessReviewGroup.GET("/assigned", authenticate, arc.ListAssigned)
+		accessReviewGroup.POST("/items/:itemId/decision", authenticate, arc.DecideItem)
+	}
+}
+
+func (arc *AccessReviewController) StartCampaign(c *gin.Context) {
+	var req requestDto.StartAccessReviewRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	campaign, err := arc.accessReviewService.StartCampaign(c, middlewares.AuthUser(c).Email, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, gin.H{"campaign": toAccessReviewCampaignResponse(campaign)}, "Access review started")
+}
+
+func (arc *AccessReviewController) ListCampaigns(c *gin.Context) {
+	campaigns, err := arc.accessReviewService.ListCampaigns(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	campaignResponses := make([]responseDto.AccessReviewCampaignResponse, len(campaigns))
+	for i, campaign := range campaigns {
+		campaignResponses[i] = toAccessReviewCampaignResponse(campaign)
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"campaigns": campaignResponses})
+}
+
+func (arc *AccessReviewController) GetCampaign(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	campaign, err := arc.accessReviewService.GetCampaign(c, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"campaign": toAccessReviewCampaignResponse(campaign)})
+}
+
+func (arc *AccessReviewController) CloseCampaign(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	campaign, err := arc.accessReviewService.CloseCampaign(c, middlewares.AuthUser(c).Email, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"campaign": toAccessReviewCampaignResponse(campaign)}, "Access review closed")
+}
+
+// ExportCampaign downloads the campaign with all decisions as CSV (?format=csv, default) or JSON (?format=json)
+func (arc *AccessReviewController) ExportCampaign(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	campaign, err := arc.accessReviewService.GetCampaign(c, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	campaignResponse := toAccessReviewCampaignResponse(campaign)
+	filename := fmt.Sprintf("access-review-%d", campaign.CampaignID)
+
+	switch strings.ToLower(c.DefaultQuery("format", "csv")) {
+	case "json":
+		data, err := json.MarshalIndent(campaignResponse, "", "  ")
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.Header("Content-Disposition", "attachment; filename="+filename+".json")
+		c.Data(http.StatusOK, "application/json", data)
+	case "csv":
+		data, err := accessReviewCSV(campaignResponse)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.Header("Content-Disposition", "attachment; filename="+filename+".csv")
+		c.Data(http.StatusOK, "text/csv", data)
+	default:
+		c.Error(exception.NewBadRequest("Unsupported export format"))
+	}
+}
+
+func (arc *AccessReviewController) ListAssigned(c *gin.Context) {
+	items, err := arc.accessReviewService.ListAssigned(c, middlewares.AuthUser(c).Email)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	itemResponses := make([]responseDto.AccessReviewItemResponse, len(items))
+	for i, item := range items {
+		itemResponses[i] = toAccessReviewItemResponse(item)
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"items": itemResponses})
+}
+
+func (arc *AccessReviewController) DecideItem(c *gin.Context) {
+	itemID, ok := paramID(c, "itemId")
+	if !ok {
+		return
+	}
+
+	var req requestDto.AccessReviewDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	item, err := arc.accessReviewService.DecideItem(c, middlewares.AuthUser(c).Email, itemID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"item": toAccessReviewItemResponse(item)}, "Decision recorded")
+}
+
+func accessReviewCSV(campaign responseDto.AccessReviewCampaignResponse) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	header := []string{
+		"campaign_id", "campaign_name", "campaign_status", "item_id", "user_id", "user_email", "role_id", "role_name",
+		"valid_from", "valid_until", "reviewer_id", "reviewer_email", "decision", "comment", "decided_at", "applied_at",
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, item := range campaign.Items {
+		record := []string{
+			strconv.FormatUint(uint64(campaign.ID), 10),
+			campaign.Name,
+			campaign.Status,
+			strconv.FormatUint(uint64(item.ID), 10),
+			strconv.FormatUint(uint64(item.UserID), 10),
+			item.UserEmail,
+			strconv.FormatUint(uint64(item.RoleID), 10),
+			item.RoleName,
+			formatOptionalTime(item.ValidFrom),
+			formatOptionalTime(item.ValidUntil),
+			formatOptionalID(item.ReviewerID),
+			item.ReviewerEmail,
+			item.Decision,
+			item.Comment,
+			formatOptionalTime(item.DecidedAt),
+			formatOptionalTime(item.AppliedAt),
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func formatOptionalID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+func toAccessReviewCampaignResponse(campaign model.AccessReviewCampaign) responseDto.AccessReviewCampaignResponse {
+	campaignResponse := responseDto.AccessReviewCampaignResponse{
+		ID:          campaign.CampaignID,
+		Name:        campaign.Name,
+		Description: campaign.Description,
+		Status:      campaign.Status,
+		DueAt:       campaign.DueAt,
+		CreatedBy:   campaign.CreatedBy,
+		CreatedAt:   campaign.CreatedAt,
+		ClosedBy:    campaign.ClosedBy,
+		ClosedAt:    campaign.ClosedAt,
+	}
+	for _, item := range campaign.Items {
+		campaignResponse.Items = append(campaignResponse.Items, toAccessReviewItemResponse(item))
+	}
+	return campaignResponse
+}
+
+func toAccessReviewItemResponse(item model.AccessReviewItem) responseDto.AccessReviewItemResponse {
+	itemResponse := responseDto.AccessReviewItemResponse{
+		ID:         item.ItemID,
+		CampaignID: item.CampaignID,
+		UserID:     item.UserID,
+		UserEmail:  item.UserEmail,
+		RoleID:     item.RoleID,
+		RoleName:   item.RoleName,
+		ValidFrom:  item.ValidFrom,
+		ValidUntil: item.ValidUntil,
+		ReviewerID: item.ReviewerID,
+		Decision:   item.Decision,
+		Comment:    item.Comment,
+		DecidedAt:  item.DecidedAt,
+		AppliedAt:  item.AppliedAt,
+	}
+	if item.Reviewer != nil {
+		itemResponse.ReviewerEmail = item.Reviewer.Email
+	}
+	return itemResponse
+}
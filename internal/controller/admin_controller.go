@@ -0,0 +1,73 @@
+package controller
+
+import (
+	middlewares "auth-service/internal/middleware"
+	requestDto "auth-service/internal/model/dto/request"
+	"auth-service/internal/service"
+	"auth-service/pkg/utils/exception"
+	"auth-service/pkg/utils/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminController struct {
+	authService service.AuthService
+}
+
+func NewAdminController(authService service.AuthService) *AdminController {
+	return &AdminController{authService}
+}
+
+func (adc *AdminController) RegisterRoutes(r *gin.RouterGroup, authorize gin.HandlerFunc) {
+	adminGroup := r.Group("/admin", authorize)
+	{
+		adminGroup.POST("/impersonate", adc.Impersonate)
+		adminGroup.POST("/users/:id/force-logout", adc.ForceLogout)
+	}
+}
+
+func (adc *AdminController) Impersonate(c *gin.Context) {
+	var req requestDto.ImpersonateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	// An impersonation token must not be used to start another impersonation
+	if middlewares.AuthUser(c).Actor != nil {
+		c.Error(exception.New(http.StatusForbidden, "IMPERSONATION_FORBIDDEN", "Impersonation cannot be nested"))
+		return
+	}
+
+	token, err := adc.authService.Impersonate(c, middlewares.AuthUser(c).Email, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"auth_token": token}, "Impersonation token issued")
+}
+
+func (adc *AdminController) ForceLogout(c *gin.Context) {
+	userID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req requestDto.ForceLogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(exception.ErrBadRequest)
+			return
+		}
+	}
+
+	if err := adc.authService.ForceLogout(c, middlewares.AuthUser(c).Email, userID, req.Reason); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil, "User logged out from all devices")
+}
@@ -0,0 +1,165 @@
+package controller
+
+import (
+	middlewares "auth-service/internal/middleware"
+	"auth-service/internal/model"
+	requestDto "auth-service/internal/model/dto/request"
+	responseDto "auth-service/internal/model/dto/response"
+	"auth-service/internal/service"
+	"auth-service/pkg/utils/exception"
+	"auth-service/pkg/utils/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttributeController struct {
+	attributeService service.AttributeService
+}
+
+func NewAttributeController(attributeService service.AttributeService) *AttributeController {
+	return &AttributeController{attributeService}
+}
+
+func (atc *AttributeController) RegisterRoutes(r *gin.RouterGroup, authenticate gin.HandlerFunc, authorize gin.HandlerFunc) {
+	definitionGroup := r.Group("/attribute-definitions", authorize)
+	{
+		definitionGroup.GET("", atc.ListDefinitions)
+		definitionGroup.POST("", atc.CreateDefinition)
+		definitionGroup.DELETE("/:id", atc.DeleteDefinition)
+	}
+
+	profileGroup := r.Group("/profile", authenticate)
+	{
+		profileGroup.GET("/attributes", atc.GetOwnAttributes)
+		profileGroup.PATCH("/attributes", atc.UpdateOwnAttributes)
+	}
+
+	adminGroup := r.Group("/admin", authorize)
+	{
+		adminGroup.GET("/users/:id/attributes", atc.GetUserAttributes)
+		adminGroup.PATCH("/users/:id/attributes", atc.UpdateUserAttributes)
+	}
+}
+
+func (atc *AttributeController) ListDefinitions(c *gin.Context) {
+	definitions, err := atc.attributeService.ListDefinitions(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	definitionResponses := make([]responseDto.AttributeDefinitionResponse, len(definitions))
+	for i, definition := range definitions {
+		definitionResponses[i] = toAttributeDefinitionResponse(definition)
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"attribute_definitions": definitionResponses})
+}
+
+func (atc *AttributeController) CreateDefinition(c *gin.Context) {
+	var req requestDto.CreateAttributeDefinitionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	definition, err := atc.attributeService.CreateDefinition(c, middlewares.AuthUser(c).Email, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, gin.H{"attribute_definition": toAttributeDefinitionResponse(definition)}, "Attribute defined")
+}
+
+func (atc *AttributeController) DeleteDefinition(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := atc.attributeService.DeleteDefinition(c, middlewares.AuthUser(c).Email, id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil, "Attribute definition deleted")
+}
+
+func (atc *AttributeController) GetOwnAttributes(c *gin.Context) {
+	attributes, err := atc.attributeService.GetOwnAttributes(c, middlewares.AuthUser(c).Email)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"attributes": attributes})
+}
+
+func (atc *AttributeController) UpdateOwnAttributes(c *gin.Context) {
+	var req requestDto.UpdateAttributesRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	attributes, err := atc.attributeService.UpdateOwnAttributes(c, middlewares.AuthUser(c).Email, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"attributes": attributes}, "Attributes updated")
+}
+
+func (atc *AttributeController) GetUserAttributes(c *gin.Context) {
+	userID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	attributes, err := atc.attributeService.GetAttributes(c, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"attributes": attributes})
+}
+
+func (atc *AttributeController) UpdateUserAttributes(c *gin.Context) {
+	userID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req requestDto.UpdateAttributesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	attributes, err := atc.attributeService.UpdateUserAttributes(c, middlewares.AuthUser(c).Email, userID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"attributes": attributes}, "Attributes updated")
+}
+
+func toAttributeDefinitionResponse(definition model.AttributeDefinition) responseDto.AttributeDefinitionResponse {
+	return responseDto.AttributeDefinitionResponse{
+		ID:              definition.AttributeDefinitionID,
+		Name:            definition.Name,
+		Type:            definition.Type,
+		Description:     definition.Description,
+		Required:        definition.Required,
+		UserEditable:    definition.UserEditable,
+		ExposedInTokens: definition.ExposedInTokens,
+		CreatedAt:       definition.CreatedAt,
+	}
+}
@@ -1,9 +1,13 @@
 package controller
 
 import (
+	"auth-service/internal/clientcert"
+	middlewares "auth-service/internal/middleware"
 	requestDto "auth-service/internal/model/dto/request"
 	responseDto "auth-service/internal/model/dto/response"
 	"auth-service/internal/service"
+	"auth-service/pkg/apikey"
+	"auth-service/pkg/identity"
 	"auth-service/pkg/utils"
 	"auth-service/pkg/utils/exception"
 	"auth-service/pkg/utils/response"
@@ -16,25 +20,31 @@ import (
 )
 
 type AuthController struct {
-	authService service.AuthService
+	authService           service.AuthService
+	serviceAccountService service.ServiceAccountService
 }
 
 type ResponseWrapper struct {
 	User any `json:"user"`
 }
 
-func NewAuthController(authService service.AuthService) *AuthController {
-	return &AuthController{authService}
+func NewAuthController(authService service.AuthService, serviceAccountService service.ServiceAccountService) *AuthController {
+	return &AuthController{authService, serviceAccountService}
 }
 
-func (ac *AuthController) RegisterRoutes(r *gin.RouterGroup) {
+func (ac *AuthController) RegisterRoutes(r *gin.RouterGroup, authenticate gin.HandlerFunc) {
 	authGroup := r.Group("/auth")
 	{
 		authGroup.POST("/register", ac.Register)
 		authGroup.POST("/login", ac.Login)
 		authGroup.GET("/verify", ac.Verify)
 		authGroup.POST("/introspect", ac.Introspect)
+		authGroup.GET("/assertion-keys", ac.AssertionKeys)
 		authGroup.POST("/logout", ac.Logout)
+		authGroup.GET("/sessions", authenticate, ac.ListSessions)
+		authGroup.DELETE("/sessions/:id", authenticate, ac.RevokeSession)
+		authGroup.POST("/logout-all", authenticate, ac.LogoutAll)
+		authGroup.POST("/password", authenticate, ac.ChangePassword)
 	}
 }
 
@@ -89,7 +99,8 @@ func (ac *AuthController) Verify(c *gin.Context) {
 	}
 	token := parts[1]
 
-	data, err := ac.authService.Verify(c, token)
+	// Callers name themselves as audience to reject tokens issued for other services
+	data, err := ac.authService.Verify(c, token, c.Query("audience"))
 	if err != nil {
 		c.Error(err)
 		return
@@ -101,56 +112,56 @@ func (ac *AuthController) Verify(c *gin.Context) {
 		return
 	}
 
+	ac.authService.RecordImpersonatedRequest(c, userResponse, "", strings.TrimPrefix(c.FullPath(), "/"), c.Request.Method)
+
 	response.Success(c, http.StatusOK, gin.H{"user": userResponse}, "Token is valid")
 }
 
 func (ac *AuthController) Introspect(c *gin.Context) {
-	authHeader := c.GetHeader("Authorization")
-	var req struct {
-		Service  string `json:"service" binding:"required"`
-		Endpoint string `json:"endpoint" binding:"required"`
-		Method   string `json:"method" binding:"required"`
-	}
-
-	if authHeader == "" {
-		c.Error(exception.ErrBadRequest)
-		return
-	}
+	var req introspectRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.Error(exception.ErrBadRequest)
 		return
 	}
 
-	parts := strings.SplitN(authHeader, " ", 2)
-	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-		c.Error(exception.NewUnauthorizedBusinessException("Invalid authorization header format"))
+	// v1 stays audience-agnostic, as it was before tokens carried "aud"; v2 checks the token against the service
+	data, err := authenticateIntrospection(c, ac.authService, ac.serviceAccountService, req, "")
+	if err != nil {
+		c.Error(err)
 		return
 	}
-	token := parts[1]
 
-	data, err := ac.authService.Verify(c, token)
+	userResponse, err := utils.UnmarshalDynamic[responseDto.UserResponse]([]byte(data), "user")
 	if err != nil {
 		c.Error(err)
 		return
 	}
 
-	userResponse, err := utils.UnmarshalDynamic[responseDto.UserResponse]([]byte(data), "user")
+	/*
+		Create user role_permission check
+	*/
+	err = ac.authService.EnforceAuthorization(c, userResponse.Email, userResponse.Scope, req.Service, req.Endpoint, req.Method)
 	if err != nil {
 		c.Error(err)
 		return
 	}
 
 	/*
-		Create user role_permission check
+		Custom claims mapped for the service travel with the user
 	*/
-	err = ac.authService.EnforceAuthorization(c, userResponse.Email, req.Service, req.Endpoint, req.Method)
+	verifiedUser, err := ac.authService.VerifiedUser(c, data, false)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	claims, err := ac.authService.CustomClaims(c, verifiedUser, req.Service)
 	if err != nil {
 		c.Error(err)
 		return
 	}
 
-	userResponseJSON, err := json.Marshal(userResponse)
+	userResponseJSON, err := xUserHeader(userResponse, claims)
 	if err != nil {
 		c.Error(err)
 		return
@@ -163,9 +174,22 @@ func (ac *AuthController) Introspect(c *gin.Context) {
 	})
 
 	/*
-		Attach user info into X-User headers
+		Attach user info into X-User headers, and the signed assertion services should trust instead
+	*/
+	c.Header("X-User", userResponseJSON)
+	if err := setIdentityAssertion(c, ac.authService, verifiedUser, claims, req.Service); err != nil {
+		c.Error(err)
+		return
+	}
+
+	/*
+		Impersonated requests are audited and expose the real admin to the caller
 	*/
-	c.Header("X-User", string(userResponseJSON))
+	if userResponse.Actor != nil {
+		ac.authService.RecordImpersonatedRequest(c, userResponse, req.Service, req.Endpoint, req.Method)
+		response.Success(c, http.StatusOK, gin.H{"act": userResponse.Actor}, "Access granted")
+		return
+	}
 
 	response.Success(c, http.StatusOK, nil, "Access granted")
 }
@@ -193,3 +217,135 @@ func (ac *AuthController) Logout(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, nil, "Logout success")
 }
+
+func (ac *AuthController) ListSessions(c *gin.Context) {
+	sessions, err := ac.authService.ListSessions(c, middlewares.AuthUser(c).Email)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	currentSessionID := middlewares.AuthSession(c).ID
+	sessionResponses := make([]responseDto.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		sessionResponses[i] = responseDto.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			Current:    session.ID != "" && session.ID == currentSessionID,
+		}
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"sessions": sessionResponses})
+}
+
+func (ac *AuthController) RevokeSession(c *gin.Context) {
+	if err := ac.authService.RevokeSession(c, middlewares.AuthUser(c).Email, c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil, "Session revoked")
+}
+
+func (ac *AuthController) LogoutAll(c *gin.Context) {
+	if err := ac.authService.LogoutAll(c, middlewares.AuthUser(c).Email); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil, "Logged out from all devices")
+}
+
+func (ac *AuthController) ChangePassword(c *gin.Context) {
+	var req requestDto.ChangePasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	token, err := ac.authService.ChangePassword(c, middlewares.AuthUser(c).Email, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"auth_token": token}, "Password changed, all other sessions were signed out")
+}
+
+// AssertionKeys serves the JSON Web Key Set services verify identity assertions with
+func (ac *AuthController) AssertionKeys(c *gin.Context) {
+	c.JSON(http.StatusOK, ac.authService.AssertionKeys())
+}
+
+// introspectRequest is the request the gateway introspects, shared by the v1 and v2 introspect endpoints
+type introspectRequest struct {
+	Service  string `json:"service" binding:"required"`
+	Endpoint string `json:"endpoint" binding:"required"`
+	Method   string `json:"method" binding:"required"`
+	// Set by the gateway for requests signed with a service account API key
+	RequestURI string `json:"request_uri"`
+	Timestamp  string `json:"timestamp"`
+	BodySHA256 string `json:"body_sha256"`
+}
+
+// authenticateIntrospection authenticates the caller of an introspected request and returns its session record.
+// A client certificate forwarded without other credentials identifies a service account, as does a request signed
+// with an API key; anything else must be a bearer token, issued for audience unless audience is empty.
+func authenticateIntrospection(c *gin.Context, authService service.AuthService, serviceAccountService service.ServiceAccountService, req introspectRequest, audience string) (string, error) {
+	authHeader := c.GetHeader("Authorization")
+
+	if cert, ok := clientcert.FromContext(c); ok && authHeader == "" {
+		return serviceAccountService.AuthenticateCertificate(c, cert)
+	}
+
+	if apikey.IsSigned(authHeader) {
+		return serviceAccountService.Authenticate(c, service.SignedRequest{
+			Authorization: authHeader,
+			Method:        req.Method,
+			Service:       req.Service,
+			Endpoint:      req.Endpoint,
+			RequestURI:    req.RequestURI,
+			Timestamp:     req.Timestamp,
+			BodySHA256:    req.BodySHA256,
+		})
+	}
+
+	token, err := utils.ExtractBearerToken(authHeader)
+	if err != nil {
+		return "", err
+	}
+	return authService.Verify(c, token, audience)
+}
+
+// setIdentityAssertion attaches the identity assertion for the service, when assertions are enabled
+func setIdentityAssertion(c *gin.Context, authService service.AuthService, user responseDto.UserResponseV2, claims map[string]any, audience string) error {
+	assertion, err := authService.IdentityAssertion(c, user, claims, audience)
+	if err != nil {
+		return err
+	}
+	if assertion != "" {
+		c.Header(identity.Header, assertion)
+	}
+	return nil
+}
+
+// xUserHeader is the X-User header value: the user as JSON, plus the custom claims mapped for the service under "claims"
+func xUserHeader(user any, claims map[string]any) (string, error) {
+	data, err := json.Marshal(user)
+	if err != nil || len(claims) == 0 {
+		return string(data), err
+	}
+
+	var header map[string]any
+	if err := json.Unmarshal(data, &header); err != nil {
+		return "", err
+	}
+	header["claims"] = claims
+
+	data, err = json.Marshal(header)
+	return string(data), err
+}
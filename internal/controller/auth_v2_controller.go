@@ -0,0 +1,139 @@
+package controller
+
+import (
+	responseDto "auth-service/internal/model/dto/response"
+	"auth-service/internal/service"
+	"auth-service/pkg/utils"
+	"auth-service/pkg/utils/exception"
+	"auth-service/pkg/utils/response"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthV2Controller serves the /api/v2 token endpoints, which return the user ID, roles as a list and,
+// on request, the user's effective permissions. The v1 endpoints keep their original contract.
+type AuthV2Controller struct {
+	authService           service.AuthService
+	serviceAccountService service.ServiceAccountService
+}
+
+func NewAuthV2Controller(authService service.AuthService, serviceAccountService service.ServiceAccountService) *AuthV2Controller {
+	return &AuthV2Controller{authService, serviceAccountService}
+}
+
+func (ac *AuthV2Controller) RegisterRoutes(r *gin.RouterGroup) {
+	authGroup := r.Group("/auth")
+	{
+		authGroup.GET("/verify", ac.Verify)
+		authGroup.POST("/introspect", ac.Introspect)
+	}
+}
+
+func (ac *AuthV2Controller) Verify(c *gin.Context) {
+	token, err := utils.ExtractBearerToken(c.GetHeader("Authorization"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	data, err := ac.authService.Verify(c, token, c.Query("audience"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// ?include=permissions adds the user's effective permissions
+	user, err := ac.authService.VerifiedUser(c, data, includes(c, "permissions"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ac.authService.RecordImpersonatedRequest(c, legacyUser(user), "", strings.TrimPrefix(c.FullPath(), "/"), c.Request.Method)
+
+	response.Success(c, http.StatusOK, gin.H{"user": user}, "Token is valid")
+}
+
+func (ac *AuthV2Controller) Introspect(c *gin.Context) {
+	var req struct {
+		introspectRequest
+		IncludePermissions bool `json:"include_permissions"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	data, err := authenticateIntrospection(c, ac.authService, ac.serviceAccountService, req.introspectRequest, req.Service)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	user, err := ac.authService.VerifiedUser(c, data, req.IncludePermissions)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := ac.authService.EnforceAuthorization(c, user.Email, user.Scope, req.Service, req.Endpoint, req.Method); err != nil {
+		c.Error(err)
+		return
+	}
+
+	claims, err := ac.authService.CustomClaims(c, user, req.Service)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	userJSON, err := xUserHeader(user, claims)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "authorized to access", "endpoint", gin.H{
+		"method":  req.Method,
+		"service": req.Service,
+		"path":    req.Endpoint,
+	})
+
+	c.Header("X-User", userJSON)
+	if err := setIdentityAssertion(c, ac.authService, user, claims, req.Service); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if user.Actor != nil {
+		ac.authService.RecordImpersonatedRequest(c, legacyUser(user), req.Service, req.Endpoint, req.Method)
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"user": user}, "Access granted")
+}
+
+// includes reports whether the comma-separated "include" query parameter names the field
+func includes(c *gin.Context, field string) bool {
+	for _, item := range strings.Split(c.Query("include"), ",") {
+		if strings.TrimSpace(item) == field {
+			return true
+		}
+	}
+	return false
+}
+
+// legacyUser converts back to the v1 user, for the parts of the service shared by both versions
+func legacyUser(user responseDto.UserResponseV2) responseDto.UserResponse {
+	return responseDto.UserResponse{
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Email:     user.Email,
+		Roles:     strings.Join(user.Roles, "|"),
+		Scope:     user.Scope,
+		Actor:     user.Actor,
+	}
+}
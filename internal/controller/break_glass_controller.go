@@ -0,0 +1,130 @@
+package controller
+
+import (
+	middlewares "auth-service/internal/middleware"
+	"auth-service/internal/model"
+	requestDto "auth-service/internal/model/dto/request"
+	responseDto "auth-service/internal/model/dto/response"
+	"auth-service/internal/service"
+	"auth-service/pkg/utils/exception"
+	"auth-service/pkg/utils/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BreakGlassController struct {
+	breakGlassService service.BreakGlassService
+}
+
+func NewBreakGlassController(breakGlassService service.BreakGlassService) *BreakGlassController {
+	return &BreakGlassController{breakGlassService}
+}
+
+func (bgc *BreakGlassController) RegisterRoutes(r *gin.RouterGroup, authorize gin.HandlerFunc) {
+	breakGlassGroup := r.Group("/break-glass")
+	{
+		// Sealed-code path, usable while regular sign-in is broken
+		breakGlassGroup.POST("/unseal", bgc.Unseal)
+
+		// Two-person path
+		breakGlassGroup.GET("/activations", authorize, bgc.ListActivations)
+		breakGlassGroup.POST("/activations", authorize, bgc.RequestActivation)
+		breakGlassGroup.POST("/activations/:id/approve", authorize, bgc.ApproveActivation)
+		breakGlassGroup.POST("/accounts/:id/disable", authorize, bgc.Disable)
+	}
+}
+
+func (bgc *BreakGlassController) Unseal(c *gin.Context) {
+	var req requestDto.UnsealBreakGlassRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	activation, err := bgc.breakGlassService.Unseal(c, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"activation": toBreakGlassActivationResponse(activation)}, "Break-glass account enabled")
+}
+
+func (bgc *BreakGlassController) ListActivations(c *gin.Context) {
+	activations, err := bgc.breakGlassService.ListActivations(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	activationResponses := make([]responseDto.BreakGlassActivationResponse, len(activations))
+	for i, activation := range activations {
+		activationResponses[i] = toBreakGlassActivationResponse(activation)
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"activations": activationResponses})
+}
+
+func (bgc *BreakGlassController) RequestActivation(c *gin.Context) {
+	var req requestDto.BreakGlassActivationRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	activation, err := bgc.breakGlassService.RequestActivation(c, middlewares.AuthUser(c).Email, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, gin.H{"activation": toBreakGlassActivationResponse(activation)}, "Break-glass activation requested, awaiting a second approver")
+}
+
+func (bgc *BreakGlassController) ApproveActivation(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	activation, err := bgc.breakGlassService.ApproveActivation(c, middlewares.AuthUser(c).Email, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"activation": toBreakGlassActivationResponse(activation)}, "Break-glass account enabled")
+}
+
+func (bgc *BreakGlassController) Disable(c *gin.Context) {
+	userID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := bgc.breakGlassService.Disable(c, middlewares.AuthUser(c).Email, userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil, "Break-glass account disabled")
+}
+
+func toBreakGlassActivationResponse(activation model.BreakGlassActivation) responseDto.BreakGlassActivationResponse {
+	return responseDto.BreakGlassActivationResponse{
+		ID:          activation.ActivationID,
+		UserID:      activation.UserID,
+		UserEmail:   activation.User.Email,
+		Method:      activation.Method,
+		Status:      activation.Status,
+		Reason:      activation.Reason,
+		RequestedBy: activation.RequestedBy,
+		ApprovedBy:  activation.ApprovedBy,
+		CreatedAt:   activation.CreatedAt,
+		ActivatedAt: activation.ActivatedAt,
+		ExpiresAt:   activation.ExpiresAt,
+	}
+}
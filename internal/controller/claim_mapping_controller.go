@@ -0,0 +1,87 @@
+package controller
+
+import (
+	middlewares "auth-service/internal/middleware"
+	"auth-service/internal/model"
+	requestDto "auth-service/internal/model/dto/request"
+	responseDto "auth-service/internal/model/dto/response"
+	"auth-service/internal/service"
+	"auth-service/pkg/utils/exception"
+	"auth-service/pkg/utils/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClaimMappingController struct {
+	claimMappingService service.ClaimMappingService
+}
+
+func NewClaimMappingController(claimMappingService service.ClaimMappingService) *ClaimMappingController {
+	return &ClaimMappingController{claimMappingService}
+}
+
+func (cmc *ClaimMappingController) RegisterRoutes(r *gin.RouterGroup, authorize gin.HandlerFunc) {
+	claimMappingGroup := r.Group("/claim-mappings", authorize)
+	{
+		claimMappingGroup.GET("", cmc.List)
+		claimMappingGroup.POST("", cmc.Create)
+		claimMappingGroup.DELETE("/:id", cmc.Delete)
+	}
+}
+
+func (cmc *ClaimMappingController) List(c *gin.Context) {
+	mappings, err := cmc.claimMappingService.List(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	mappingResponses := make([]responseDto.ClaimMappingResponse, len(mappings))
+	for i, mapping := range mappings {
+		mappingResponses[i] = toClaimMappingResponse(mapping)
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"claim_mappings": mappingResponses})
+}
+
+func (cmc *ClaimMappingController) Create(c *gin.Context) {
+	var req requestDto.CreateClaimMappingRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	mapping, err := cmc.claimMappingService.Create(c, middlewares.AuthUser(c).Email, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, gin.H{"claim_mapping": toClaimMappingResponse(mapping)}, "Claim mapping created")
+}
+
+func (cmc *ClaimMappingController) Delete(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := cmc.claimMappingService.Delete(c, middlewares.AuthUser(c).Email, id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil, "Claim mapping deleted")
+}
+
+func toClaimMappingResponse(mapping model.ClaimMapping) responseDto.ClaimMappingResponse {
+	return responseDto.ClaimMappingResponse{
+		ID:        mapping.ClaimMappingID,
+		Audience:  mapping.Audience,
+		Claim:     mapping.Claim,
+		Source:    mapping.Source,
+		CreatedAt: mapping.CreatedAt,
+	}
+}
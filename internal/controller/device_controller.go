@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"auth-service/internal/model"
+	requestDto "auth-service/internal/model/dto/request"
+	"auth-service/internal/service"
+	"auth-service/pkg/utils/exception"
+	_ "embed"
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed templates/device.html
+var devicePageHTML string
+
+var devicePage = template.Must(template.New("device").Parse(devicePageHTML))
+
+// devicePageData fills the device page: the code form when Authorization is nil, the approval form otherwise,
+// or only the Done message once the user decided
+type devicePageData struct {
+	Authorization *model.DeviceAuthorization
+	Scope         []string
+	Error         string
+	Done          string
+}
+
+// DeviceController serves the server-rendered page where users approve the devices of the device authorization grant
+type DeviceController struct {
+	deviceAuthorizationService service.DeviceAuthorizationService
+}
+
+func NewDeviceController(deviceAuthorizationService service.DeviceAuthorizationService) *DeviceController {
+	return &DeviceController{deviceAuthorizationService}
+}
+
+func (dc *DeviceController) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/device", dc.Show)
+	r.POST("/device", dc.Decide)
+}
+
+func (dc *DeviceController) Show(c *gin.Context) {
+	userCode := c.Query("user_code")
+	if userCode == "" {
+		renderDevicePage(c, http.StatusOK, devicePageData{})
+		return
+	}
+
+	authorization, err := dc.deviceAuthorizationService.Pending(c, userCode)
+	if err != nil {
+		renderDevicePage(c, http.StatusNotFound, devicePageData{Error: pageError(err)})
+		return
+	}
+
+	renderDevicePage(c, http.StatusOK, devicePageData{Authorization: &authorization, Scope: strings.Fields(authorization.Scope)})
+}
+
+func (dc *DeviceController) Decide(c *gin.Context) {
+	var req requestDto.DeviceDecisionRequest
+
+	if err := c.ShouldBind(&req); err != nil {
+		authorization, err := dc.deviceAuthorizationService.Pending(c, c.PostForm("user_code"))
+		if err != nil {
+			renderDevicePage(c, http.StatusBadRequest, devicePageData{Error: pageError(err)})
+			return
+		}
+		renderDevicePage(c, http.StatusBadRequest, devicePageData{Authorization: &authorization, Scope: strings.Fields(authorization.Scope), Error: "Please fill in every field"})
+		return
+	}
+
+	authorization, err := dc.deviceAuthorizationService.Decide(c, req.UserCode, req.Email, req.Password, req.Action == "approve")
+	if err != nil {
+		data := devicePageData{Error: pageError(err)}
+		if pending, err := dc.deviceAuthorizationService.Pending(c, req.UserCode); err == nil {
+			data.Authorization, data.Scope = &pending, strings.Fields(pending.Scope)
+		}
+		renderDevicePage(c, statusOf(err), data)
+		return
+	}
+
+	if authorization.Status == model.DeviceAuthorizationApproved {
+		renderDevicePage(c, http.StatusOK, devicePageData{Done: "Device approved. You can return to " + authorization.OAuthClient.Name + "."})
+		return
+	}
+	renderDevicePage(c, http.StatusOK, devicePageData{Done: "Device denied. " + authorization.OAuthClient.Name + " will not be signed in."})
+}
+
+func renderDevicePage(c *gin.Context, status int, data devicePageData) {
+	// Sign-in pages must not be framed by other sites, nor cached
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; form-action 'self'; frame-ancestors 'none'")
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+
+	if err := devicePage.Execute(c.Writer, data); err != nil {
+		slog.ErrorContext(c.Request.Context(), "failed to render device page", "error", err)
+	}
+}
+
+// pageError is the message shown for an error; internal errors are not detailed
+func pageError(err error) string {
+	var appErr *exception.AppError
+	if !errors.As(err, &appErr) || appErr.StatusCode >= http.StatusInternalServerError {
+		return "Something went wrong, please try again"
+	}
+	return appErr.Message
+}
+
+func statusOf(err error) int {
+	var appErr *exception.AppError
+	if !errors.As(err, &appErr) {
+		return http.StatusInternalServerError
+	}
+	return appErr.StatusCode
+}
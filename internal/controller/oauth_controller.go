@@ -0,0 +1,194 @@
+package controller
+
+import (
+	middlewares "auth-service/internal/middleware"
+	"auth-service/internal/model"
+	requestDto "auth-service/internal/model/dto/request"
+	responseDto "auth-service/internal/model/dto/response"
+	"auth-service/internal/service"
+	"auth-service/pkg/utils/exception"
+	"auth-service/pkg/utils/response"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OAuthController serves the OAuth token and device authorization endpoints and the management of the clients
+// allowed to call them. Both endpoints answer in the OAuth format rather than the service's usual envelope.
+type OAuthController struct {
+	oauthClientService         service.OAuthClientService
+	authService                service.AuthService
+	deviceAuthorizationService service.DeviceAuthorizationService
+}
+
+func NewOAuthController(oauthClientService service.OAuthClientService, authService service.AuthService, deviceAuthorizationService service.DeviceAuthorizationService) *OAuthController {
+	return &OAuthController{oauthClientService, authService, deviceAuthorizationService}
+}
+
+func (oc *OAuthController) RegisterRoutes(r *gin.RouterGroup, authorize gin.HandlerFunc) {
+	oauthGroup := r.Group("/oauth")
+	{
+		oauthGroup.POST("/token", oc.Token)
+		oauthGroup.POST("/device_authorization", oc.DeviceAuthorization)
+	}
+
+	clientGroup := oauthGroup.Group("/clients", authorize)
+	{
+		clientGroup.GET("", oc.ListClients)
+		clientGroup.POST("", oc.CreateClient)
+		clientGroup.DELETE("/:id", oc.DeleteClient)
+	}
+}
+
+func (oc *OAuthController) Token(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	var req requestDto.TokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		oauthErrorResponse(c, exception.New(http.StatusBadRequest, service.OAuthInvalidRequest, "grant_type is required"))
+		return
+	}
+
+	client, ok := oc.authenticateClient(c, req.ClientID, req.ClientSecret)
+	if !ok {
+		return
+	}
+
+	var tokenResponse responseDto.TokenResponse
+	var err error
+	switch req.GrantType {
+	case service.GrantTypeTokenExchange:
+		tokenResponse, err = oc.authService.ExchangeToken(c, client, req)
+	case service.GrantTypeDeviceCode:
+		tokenResponse, err = oc.deviceAuthorizationService.Token(c, client, req.DeviceCode)
+	default:
+		err = exception.New(http.StatusBadRequest, service.OAuthUnsupportedGrantType, "Unsupported grant_type")
+	}
+	if err != nil {
+		oauthErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokenResponse)
+}
+
+func (oc *OAuthController) DeviceAuthorization(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	var req requestDto.DeviceAuthorizationRequest
+	if err := c.ShouldBind(&req); err != nil {
+		oauthErrorResponse(c, exception.New(http.StatusBadRequest, service.OAuthInvalidRequest, "Invalid request"))
+		return
+	}
+
+	client, ok := oc.authenticateClient(c, req.ClientID, req.ClientSecret)
+	if !ok {
+		return
+	}
+
+	deviceAuthorization, err := oc.deviceAuthorizationService.Authorize(c, client, req.Scope)
+	if err != nil {
+		oauthErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deviceAuthorization)
+}
+
+// authenticateClient authenticates the client with HTTP Basic, or else with the client_id and client_secret of the
+// form, and writes the OAuth error when that fails
+func (oc *OAuthController) authenticateClient(c *gin.Context, formClientID string, formClientSecret string) (model.OAuthClient, bool) {
+	clientID, clientSecret, basic := c.Request.BasicAuth()
+	if !basic {
+		clientID, clientSecret = formClientID, formClientSecret
+	}
+
+	client, err := oc.oauthClientService.Authenticate(c, clientID, clientSecret)
+	if err != nil {
+		if basic {
+			c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+		}
+		oauthErrorResponse(c, err)
+		return model.OAuthClient{}, false
+	}
+	return client, true
+}
+
+func (oc *OAuthController) ListClients(c *gin.Context) {
+	clients, err := oc.oauthClientService.List(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	clientResponses := make([]responseDto.OAuthClientResponse, len(clients))
+	for i, client := range clients {
+		clientResponses[i] = toOAuthClientResponse(client)
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"clients": clientResponses})
+}
+
+func (oc *OAuthController) CreateClient(c *gin.Context) {
+	var req requestDto.CreateOAuthClientRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	client, secret, err := oc.oauthClientService.Create(c, middlewares.AuthUser(c).Email, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if client.Public {
+		response.Success(c, http.StatusCreated, gin.H{"client": toOAuthClientResponse(client)}, "OAuth client created")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, gin.H{
+		"client":        toOAuthClientResponse(client),
+		"client_secret": secret,
+	}, "OAuth client created, store the secret now as it will not be shown again")
+}
+
+func (oc *OAuthController) DeleteClient(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := oc.oauthClientService.Delete(c, middlewares.AuthUser(c).Email, id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil, "OAuth client deleted")
+}
+
+// oauthErrorResponse writes an error in the OAuth format (RFC 6749 section 5.2). Errors without an OAuth
+// code of their own are server errors.
+func oauthErrorResponse(c *gin.Context, err error) {
+	var appErr *exception.AppError
+	if !errors.As(err, &appErr) || appErr.StatusCode >= http.StatusInternalServerError {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "server_error", "error_description": "Internal server error"})
+		return
+	}
+	c.JSON(appErr.StatusCode, gin.H{"error": appErr.Code, "error_description": appErr.Message})
+}
+
+func toOAuthClientResponse(client model.OAuthClient) responseDto.OAuthClientResponse {
+	return responseDto.OAuthClientResponse{
+		ID:               client.OAuthClientID,
+		ClientID:         client.ClientID,
+		Name:             client.Name,
+		AllowedAudiences: client.Audiences(),
+		Public:           client.Public,
+		CreatedAt:        client.CreatedAt,
+	}
+}
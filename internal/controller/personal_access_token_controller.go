@@ -0,0 +1,96 @@
+package controller
+
+import (
+	middlewares "auth-service/internal/middleware"
+	"auth-service/internal/model"
+	requestDto "auth-service/internal/model/dto/request"
+	responseDto "auth-service/internal/model/dto/response"
+	"auth-service/internal/service"
+	"auth-service/pkg/utils/exception"
+	"auth-service/pkg/utils/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PersonalAccessTokenController struct {
+	personalAccessTokenService service.PersonalAccessTokenService
+}
+
+func NewPersonalAccessTokenController(personalAccessTokenService service.PersonalAccessTokenService) *PersonalAccessTokenController {
+	return &PersonalAccessTokenController{personalAccessTokenService}
+}
+
+func (pc *PersonalAccessTokenController) RegisterRoutes(r *gin.RouterGroup, authenticate gin.HandlerFunc) {
+	tokenGroup := r.Group("/profile/tokens", authenticate)
+	{
+		tokenGroup.GET("", pc.List)
+		tokenGroup.POST("", pc.Create)
+		tokenGroup.DELETE("/:id", pc.Revoke)
+	}
+}
+
+func (pc *PersonalAccessTokenController) List(c *gin.Context) {
+	authUser := middlewares.AuthUser(c)
+	tokens, err := pc.personalAccessTokenService.List(c, authUser.Email, authUser.Actor)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	tokenResponses := make([]responseDto.PersonalAccessTokenResponse, len(tokens))
+	for i, token := range tokens {
+		tokenResponses[i] = toPersonalAccessTokenResponse(token)
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"tokens": tokenResponses})
+}
+
+func (pc *PersonalAccessTokenController) Create(c *gin.Context) {
+	var req requestDto.CreatePersonalAccessTokenRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	authUser := middlewares.AuthUser(c)
+	token, plain, err := pc.personalAccessTokenService.Create(c, authUser.Email, authUser.Actor, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, gin.H{
+		"token":        toPersonalAccessTokenResponse(token),
+		"access_token": plain,
+	}, "Personal access token created, store it now as it will not be shown again")
+}
+
+func (pc *PersonalAccessTokenController) Revoke(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	authUser := middlewares.AuthUser(c)
+	if err := pc.personalAccessTokenService.Revoke(c, authUser.Email, authUser.Actor, id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil, "Personal access token revoked")
+}
+
+func toPersonalAccessTokenResponse(token model.PersonalAccessToken) responseDto.PersonalAccessTokenResponse {
+	return responseDto.PersonalAccessTokenResponse{
+		ID:          token.PersonalAccessTokenID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		Scope:       token.Permissions(),
+		ExpiresAt:   token.ExpiresAt,
+		LastUsedAt:  token.LastUsedAt,
+		RevokedAt:   token.RevokedAt,
+		CreatedAt:   token.CreatedAt,
+	}
+}
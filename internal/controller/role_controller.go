@@ -0,0 +1,100 @@
+package controller
+
+import (
+	middlewares "auth-service/internal/middleware"
+	"auth-service/internal/model"
+	requestDto "auth-service/internal/model/dto/request"
+	responseDto "auth-service/internal/model/dto/response"
+	"auth-service/internal/service"
+	"auth-service/pkg/utils/exception"
+	"auth-service/pkg/utils/response"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleController struct {
+	roleGrantService service.RoleGrantService
+}
+
+func NewRoleController(roleGrantService service.RoleGrantService) *RoleController {
+	return &RoleController{roleGrantService}
+}
+
+func (rc *RoleController) RegisterRoutes(r *gin.RouterGroup, authorize gin.HandlerFunc) {
+	roleGroup := r.Group("/roles", authorize)
+	{
+		roleGroup.GET("/grants", rc.ListGrants)
+		roleGroup.POST("/grants", rc.GrantRole)
+		roleGroup.DELETE("/grants", rc.RevokeRole)
+	}
+}
+
+func (rc *RoleController) ListGrants(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Query("user_id"), 10, 64)
+	if err != nil {
+		c.Error(exception.NewBadRequest("user_id query parameter is required"))
+		return
+	}
+
+	grants, err := rc.roleGrantService.ListGrants(c, uint(userID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	now := time.Now()
+	grantResponses := make([]responseDto.RoleGrantResponse, len(grants))
+	for i, grant := range grants {
+		grantResponses[i] = toRoleGrantResponse(grant, now)
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"grants": grantResponses})
+}
+
+func (rc *RoleController) GrantRole(c *gin.Context) {
+	var req requestDto.GrantRoleRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	grant, err := rc.roleGrantService.GrantRole(c, middlewares.AuthUser(c).Email, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, gin.H{"grant": toRoleGrantResponse(grant, time.Now())}, "Role granted")
+}
+
+func (rc *RoleController) RevokeRole(c *gin.Context) {
+	var req requestDto.RevokeRoleRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	if err := rc.roleGrantService.RevokeRole(c, middlewares.AuthUser(c).Email, req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil, "Role revoked")
+}
+
+func toRoleGrantResponse(grant model.UserRole, now time.Time) responseDto.RoleGrantResponse {
+	return responseDto.RoleGrantResponse{
+		UserID:      grant.UserID,
+		RoleID:      grant.RoleID,
+		RoleName:    grant.Role.Name,
+		ValidFrom:   grant.ValidFrom,
+		ValidUntil:  grant.ValidUntil,
+		ActivatedAt: grant.ActivatedAt,
+		Active:      grant.IsActiveAt(now),
+	}
+}
@@ -0,0 +1,244 @@
+package controller
+
+import (
+	middlewares "auth-service/internal/middleware"
+	"auth-service/internal/model"
+	requestDto "auth-service/internal/model/dto/request"
+	responseDto "auth-service/internal/model/dto/response"
+	"auth-service/internal/service"
+	"auth-service/pkg/utils/exception"
+	"auth-service/pkg/utils/response"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ServiceAccountController struct {
+	serviceAccountService service.ServiceAccountService
+}
+
+func NewServiceAccountController(serviceAccountService service.ServiceAccountService) *ServiceAccountController {
+	return &ServiceAccountController{serviceAccountService}
+}
+
+func (sc *ServiceAccountController) RegisterRoutes(r *gin.RouterGroup, authorize gin.HandlerFunc) {
+	serviceAccountGroup := r.Group("/service-accounts", authorize)
+	{
+		serviceAccountGroup.GET("", sc.List)
+		serviceAccountGroup.POST("", sc.Create)
+		serviceAccountGroup.DELETE("/:id", sc.Delete)
+		serviceAccountGroup.GET("/:id/keys", sc.ListKeys)
+		serviceAccountGroup.POST("/:id/keys", sc.CreateKey)
+		serviceAccountGroup.DELETE("/:id/keys/:keyId", sc.RevokeKey)
+		serviceAccountGroup.GET("/:id/certificates", sc.ListCertificates)
+		serviceAccountGroup.POST("/:id/certificates", sc.AddCertificate)
+		serviceAccountGroup.DELETE("/:id/certificates/:certificateId", sc.RemoveCertificate)
+	}
+}
+
+func (sc *ServiceAccountController) List(c *gin.Context) {
+	accounts, err := sc.serviceAccountService.List(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	accountResponses := make([]responseDto.ServiceAccountResponse, len(accounts))
+	for i, account := range accounts {
+		accountResponses[i] = toServiceAccountResponse(account)
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"service_accounts": accountResponses})
+}
+
+func (sc *ServiceAccountController) Create(c *gin.Context) {
+	var req requestDto.CreateServiceAccountRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	account, err := sc.serviceAccountService.Create(c, middlewares.AuthUser(c).Email, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, gin.H{"service_account": toServiceAccountResponse(account)}, "Service account created")
+}
+
+func (sc *ServiceAccountController) Delete(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := sc.serviceAccountService.Delete(c, middlewares.AuthUser(c).Email, id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil, "Service account deleted")
+}
+
+func (sc *ServiceAccountController) ListKeys(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	keys, err := sc.serviceAccountService.ListKeys(c, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	keyResponses := make([]responseDto.ServiceAccountKeyResponse, len(keys))
+	for i, key := range keys {
+		keyResponses[i] = toServiceAccountKeyResponse(key)
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"keys": keyResponses})
+}
+
+func (sc *ServiceAccountController) CreateKey(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req requestDto.CreateServiceAccountKeyRequest
+
+	// The body is optional
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	key, secret, err := sc.serviceAccountService.CreateKey(c, middlewares.AuthUser(c).Email, id, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, gin.H{
+		"key":    toServiceAccountKeyResponse(key),
+		"secret": secret,
+	}, "API key created, store the secret now as it will not be shown again")
+}
+
+func (sc *ServiceAccountController) RevokeKey(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	keyID, ok := paramID(c, "keyId")
+	if !ok {
+		return
+	}
+
+	if err := sc.serviceAccountService.RevokeKey(c, middlewares.AuthUser(c).Email, id, keyID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil, "API key revoked")
+}
+
+func (sc *ServiceAccountController) ListCertificates(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	certificates, err := sc.serviceAccountService.ListCertificates(c, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	certificateResponses := make([]responseDto.ServiceAccountCertificateResponse, len(certificates))
+	for i, certificate := range certificates {
+		certificateResponses[i] = toServiceAccountCertificateResponse(certificate)
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"certificates": certificateResponses})
+}
+
+func (sc *ServiceAccountController) AddCertificate(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req requestDto.AddServiceAccountCertificateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(exception.ErrBadRequest)
+		return
+	}
+
+	certificate, err := sc.serviceAccountService.AddCertificate(c, middlewares.AuthUser(c).Email, id, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, gin.H{"certificate": toServiceAccountCertificateResponse(certificate)}, "Certificate mapped")
+}
+
+func (sc *ServiceAccountController) RemoveCertificate(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	certificateID, ok := paramID(c, "certificateId")
+	if !ok {
+		return
+	}
+
+	if err := sc.serviceAccountService.RemoveCertificate(c, middlewares.AuthUser(c).Email, id, certificateID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil, "Certificate mapping removed")
+}
+
+func toServiceAccountResponse(account model.User) responseDto.ServiceAccountResponse {
+	roles := make([]string, len(account.Roles))
+	for i, role := range account.Roles {
+		roles[i] = role.Name
+	}
+
+	return responseDto.ServiceAccountResponse{
+		ID:        account.ID,
+		Name:      account.FirstName,
+		Email:     account.Email,
+		Roles:     roles,
+		CreatedAt: account.CreatedAt,
+	}
+}
+
+func toServiceAccountKeyResponse(key model.ServiceAccountKey) responseDto.ServiceAccountKeyResponse {
+	return responseDto.ServiceAccountKeyResponse{
+		ID:          key.ServiceAccountKeyID,
+		KeyID:       key.KeyID,
+		Description: key.Description,
+		LastUsedAt:  key.LastUsedAt,
+		RevokedAt:   key.RevokedAt,
+		CreatedAt:   key.CreatedAt,
+	}
+}
+
+func toServiceAccountCertificateResponse(certificate model.ServiceAccountCertificate) responseDto.ServiceAccountCertificateResponse {
+	return responseDto.ServiceAccountCertificateResponse{
+		ID:        certificate.ServiceAccountCertificateID,
+		MatchType: certificate.MatchType,
+		Value:     certificate.MatchValue,
+		CreatedAt: certificate.CreatedAt,
+	}
+}
@@ -0,0 +1,42 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"log/slog"
+	"net"
+)
+
+// slogLogger forwards go-redis' internal messages to slog
+type slogLogger struct{}
+
+func (slogLogger) Printf(ctx context.Context, format string, v ...interface{}) {
+	slog.WarnContext(ctx, "redis: "+fmt.Sprintf(format, v...))
+}
+
+// connectionHook logs every new connection and every failed dial, which makes reconnects visible
+type connectionHook struct{}
+
+func (connectionHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := next(ctx, network, addr)
+		if err != nil {
+			slog.WarnContext(ctx, "redis dial failed",
+				"addr", addr,
+				"error", err,
+			)
+			return nil, err
+		}
+		slog.DebugContext(ctx, "redis connection opened", "addr", addr)
+		return conn, nil
+	}
+}
+
+func (connectionHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return next
+}
+
+func (connectionHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
@@ -2,35 +2,176 @@ package redis
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"github.com/redis/go-redis/v9"
 	"log/slog"
+	"os"
 	"time"
 )
 
 var (
-	Rdb *redis.Client
+	Rdb redis.UniversalClient
 	Ctx = context.Background()
 )
 
-func InitRedis(addr string, password string) error {
-	db := 0 // use default DB
+// Options selects the deployment Redis runs in: a sentinel-managed master when MasterName is set,
+// a cluster when ClusterAddrs is set, a single node at Addr otherwise.
+type Options struct {
+	Addr     string
+	Password string
+	DB       int
 
-	Rdb = redis.NewClient(&redis.Options{
-		Addr:     addr,
-		Password: password,
-		DB:       db,
-	})
+	MasterName       string
+	SentinelAddrs    []string
+	SentinelPassword string
+
+	ClusterAddrs []string
+
+	TLS           bool
+	TLSServerName string
+	TLSCAFile     string
+
+	PoolSize     int
+	MinIdleConns int
+}
+
+// Mode names the deployment the options describe
+func (o Options) Mode() string {
+	switch {
+	case o.MasterName != "":
+		return "sentinel"
+	case len(o.ClusterAddrs) > 0:
+		return "cluster"
+	default:
+		return "single"
+	}
+}
+
+func InitRedis(opts Options) error {
+	universal, err := universalOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	// Route go-redis' own messages (sentinel failovers, cluster reloads, pool errors) to the structured log
+	redis.SetLogger(slogLogger{})
+
+	Rdb = redis.NewUniversalClient(universal)
+	if cluster, ok := Rdb.(*redis.ClusterClient); ok {
+		cluster.OnNewNode(func(node *redis.Client) {
+			node.AddHook(connectionHook{})
+		})
+	} else {
+		Rdb.AddHook(connectionHook{})
+	}
 
 	// Test connection
-	_, err := Rdb.Ping(Ctx).Result()
+	_, err = Rdb.Ping(Ctx).Result()
 	if err != nil {
 		return err
 	}
 
-	slog.Info("connected to Redis")
+	slog.Info("connected to Redis",
+		"mode", opts.Mode(),
+		"addrs", universal.Addrs,
+		"db", universal.DB,
+	)
 	return nil
 }
 
+func universalOptions(opts Options) (*redis.UniversalOptions, error) {
+	universal := &redis.UniversalOptions{
+		Addrs:        []string{opts.Addr},
+		Password:     opts.Password,
+		DB:           opts.DB,
+		PoolSize:     opts.PoolSize,
+		MinIdleConns: opts.MinIdleConns,
+	}
+
+	switch opts.Mode() {
+	case "sentinel":
+		if len(opts.SentinelAddrs) == 0 {
+			return nil, errors.New("redis sentinel master name set without sentinel addresses")
+		}
+		universal.MasterName = opts.MasterName
+		universal.Addrs = opts.SentinelAddrs
+		universal.SentinelPassword = opts.SentinelPassword
+	case "cluster":
+		if opts.DB != 0 {
+			return nil, errors.New("redis cluster only supports DB 0")
+		}
+		universal.Addrs = opts.ClusterAddrs
+		universal.IsClusterMode = true
+	}
+
+	if opts.TLS {
+		tlsConfig := &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			ServerName: opts.TLSServerName,
+		}
+		if opts.TLSCAFile != "" {
+			pem, err := os.ReadFile(opts.TLSCAFile)
+			if err != nil {
+				return nil, fmt.Errorf("read redis CA file: %w", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, errors.New("redis CA file contains no certificates")
+			}
+			tlsConfig.RootCAs = pool
+		}
+		universal.TLSConfig = tlsConfig
+	}
+
+	return universal, nil
+}
+
+// WatchHealth pings Redis every interval in the background until ctx is cancelled, logging when it
+// becomes unreachable and when it recovers
+func WatchHealth(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		healthy := true
+		var downSince time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			err := Rdb.Ping(ctx).Err()
+			switch {
+			case err != nil && healthy:
+				healthy = false
+				downSince = time.Now()
+				slog.ErrorContext(ctx, "redis is unreachable",
+					"error", err,
+				)
+			case err == nil && !healthy:
+				healthy = true
+				slog.InfoContext(ctx, "redis recovered",
+					"downtime", time.Since(downSince).String(),
+				)
+			}
+
+			stats := Rdb.PoolStats()
+			slog.DebugContext(ctx, "redis pool",
+				"totalConns", stats.TotalConns,
+				"idleConns", stats.IdleConns,
+				"timeouts", stats.Timeouts,
+			)
+		}
+	}()
+
+	slog.Info("redis health check started", "interval", interval.String())
+}
+
 func Set(key string, value string, expiration time.Duration) error {
 	return Rdb.Set(Ctx, key, value, expiration).Err()
 }
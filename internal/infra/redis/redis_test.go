@@ -0,0 +1,219 @@
+package redis
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// writeCAFile writes a self-signed certificate as a PEM file and returns its path
+func writeCAFile(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "redis-test-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestUniversalOptions(t *testing.T) {
+	caFile := writeCAFile(t)
+	notPEM := filepath.Join(t.TempDir(), "ca.txt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		opts      Options
+		wantMode  string
+		wantAddrs []string
+		wantErr   bool
+		check     func(t *testing.T, universal *redis.UniversalOptions)
+	}{
+		{
+			name:      "single node",
+			opts:      Options{Addr: "localhost:6379", Password: "secret", DB: 2, PoolSize: 20},
+			wantMode:  "single",
+			wantAddrs: []string{"localhost:6379"},
+			check: func(t *testing.T, universal *redis.UniversalOptions) {
+				if universal.Password != "secret" || universal.DB != 2 || universal.PoolSize != 20 || universal.TLSConfig != nil {
+					t.Errorf("universalOptions() = %+v", universal)
+				}
+			},
+		},
+		{
+			name:      "sentinel",
+			opts:      Options{Addr: "localhost:6379", MasterName: "mymaster", SentinelAddrs: []string{"s1:26379", "s2:26379"}, SentinelPassword: "sentinel"},
+			wantMode:  "sentinel",
+			wantAddrs: []string{"s1:26379", "s2:26379"},
+			check: func(t *testing.T, universal *redis.UniversalOptions) {
+				if universal.MasterName != "mymaster" || universal.SentinelPassword != "sentinel" {
+					t.Errorf("universalOptions() = %+v", universal)
+				}
+			},
+		},
+		{
+			name:     "sentinel without sentinel addresses",
+			opts:     Options{Addr: "localhost:6379", MasterName: "mymaster"},
+			wantMode: "sentinel",
+			wantErr:  true,
+		},
+		{
+			name:      "cluster",
+			opts:      Options{Addr: "localhost:6379", ClusterAddrs: []string{"n1:6379", "n2:6379"}},
+			wantMode:  "cluster",
+			wantAddrs: []string{"n1:6379", "n2:6379"},
+			check: func(t *testing.T, universal *redis.UniversalOptions) {
+				if !universal.IsClusterMode {
+					t.Error("universalOptions() did not select cluster mode")
+				}
+			},
+		},
+		{
+			name:     "cluster with a database index",
+			opts:     Options{ClusterAddrs: []string{"n1:6379"}, DB: 1},
+			wantMode: "cluster",
+			wantErr:  true,
+		},
+		{
+			name:      "sentinel takes precedence over cluster",
+			opts:      Options{MasterName: "mymaster", SentinelAddrs: []string{"s1:26379"}, ClusterAddrs: []string{"n1:6379"}},
+			wantMode:  "sentinel",
+			wantAddrs: []string{"s1:26379"},
+		},
+		{
+			name:      "tls with a custom CA",
+			opts:      Options{Addr: "redis.internal:6380", TLS: true, TLSServerName: "redis", TLSCAFile: caFile},
+			wantMode:  "single",
+			wantAddrs: []string{"redis.internal:6380"},
+			check: func(t *testing.T, universal *redis.UniversalOptions) {
+				if universal.TLSConfig == nil || universal.TLSConfig.ServerName != "redis" || universal.TLSConfig.RootCAs == nil {
+					t.Errorf("universalOptions() TLS = %+v", universal.TLSConfig)
+				}
+			},
+		},
+		{
+			name:     "tls with a missing CA file",
+			opts:     Options{Addr: "localhost:6380", TLS: true, TLSCAFile: filepath.Join(t.TempDir(), "missing.pem")},
+			wantMode: "single",
+			wantErr:  true,
+		},
+		{
+			name:     "tls with a CA file without certificates",
+			opts:     Options{Addr: "localhost:6380", TLS: true, TLSCAFile: notPEM},
+			wantMode: "single",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.Mode(); got != tt.wantMode {
+				t.Errorf("Mode() = %s, want %s", got, tt.wantMode)
+			}
+
+			universal, err := universalOptions(tt.opts)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("universalOptions() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("universalOptions() error = %v", err)
+			}
+			if !slices.Equal(universal.Addrs, tt.wantAddrs) {
+				t.Errorf("universalOptions() addrs = %v, want %v", universal.Addrs, tt.wantAddrs)
+			}
+			if tt.check != nil {
+				tt.check(t, universal)
+			}
+		})
+	}
+}
+
+// syncBuffer lets the health check goroutine log while the test reads
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWatchHealthReportsOutage(t *testing.T) {
+	logs := &syncBuffer{}
+	previousLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previousLogger) })
+
+	// Nothing listens on port 1, so every ping fails
+	previousClient := Rdb
+	Rdb = redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 100 * time.Millisecond, MaxRetries: -1})
+	t.Cleanup(func() {
+		Rdb.Close()
+		Rdb = previousClient
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	WatchHealth(ctx, 20*time.Millisecond)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(logs.String(), "redis is unreachable") {
+		if time.Now().After(deadline) {
+			t.Fatalf("no outage logged, got:\n%s", logs.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The outage is reported once, not on every failed ping
+	time.Sleep(100 * time.Millisecond)
+	if n := strings.Count(logs.String(), "redis is unreachable"); n != 1 {
+		t.Errorf("outage logged %d times, want once", n)
+	}
+}
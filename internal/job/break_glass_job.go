@@ -0,0 +1,46 @@
+package job
+
+import (
+	"auth-service/internal/service"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// BreakGlassJob disables break-glass accounts once their activation window has elapsed
+type BreakGlassJob struct {
+	breakGlassService service.BreakGlassService
+	interval          time.Duration
+}
+
+func NewBreakGlassJob(breakGlassService service.BreakGlassService, interval time.Duration) *BreakGlassJob {
+	return &BreakGlassJob{breakGlassService, interval}
+}
+
+// Start runs the job in the background until ctx is cancelled
+func (j *BreakGlassJob) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	slog.Info("break-glass job started", "interval", j.interval.String())
+}
+
+func (j *BreakGlassJob) run(ctx context.Context) {
+	if err := j.breakGlassService.SyncActivations(ctx); err != nil {
+		slog.ErrorContext(ctx, "failed to disable expired break-glass accounts",
+			"error", err,
+		)
+	}
+}
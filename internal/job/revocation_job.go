@@ -0,0 +1,46 @@
+package job
+
+import (
+	"auth-service/internal/service"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// RevocationJob periodically replicates the token denylist to this instance
+type RevocationJob struct {
+	revocationService service.RevocationService
+	interval          time.Duration
+}
+
+func NewRevocationJob(revocationService service.RevocationService, interval time.Duration) *RevocationJob {
+	return &RevocationJob{revocationService, interval}
+}
+
+// Start runs the job in the background until ctx is cancelled
+func (j *RevocationJob) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	slog.Info("revocation job started", "interval", j.interval.String())
+}
+
+func (j *RevocationJob) run(ctx context.Context) {
+	if err := j.revocationService.Sync(ctx); err != nil {
+		slog.ErrorContext(ctx, "failed to synchronize token denylist",
+			"error", err,
+		)
+	}
+}
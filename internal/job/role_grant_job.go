@@ -0,0 +1,46 @@
+package job
+
+import (
+	"auth-service/internal/service"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// RoleGrantJob periodically activates and expires time-bound role grants
+type RoleGrantJob struct {
+	roleGrantService service.RoleGrantService
+	interval         time.Duration
+}
+
+func NewRoleGrantJob(roleGrantService service.RoleGrantService, interval time.Duration) *RoleGrantJob {
+	return &RoleGrantJob{roleGrantService, interval}
+}
+
+// Start runs the job in the background until ctx is cancelled
+func (j *RoleGrantJob) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	slog.Info("role grant job started", "interval", j.interval.String())
+}
+
+func (j *RoleGrantJob) run(ctx context.Context) {
+	if err := j.roleGrantService.SyncGrants(ctx); err != nil {
+		slog.ErrorContext(ctx, "failed to synchronize role grants",
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,59 @@
+package middlewares
+
+import (
+	responseDto "auth-service/internal/model/dto/response"
+	"auth-service/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeAuthService accepts every token and records the audience it was verified against
+type fakeAuthService struct {
+	service.AuthService
+	audiences []string
+}
+
+func (s *fakeAuthService) Verify(c *gin.Context, authToken string, audience string) (string, error) {
+	s.audiences = append(s.audiences, audience)
+	return `{"user":{"email":"ada@example.com"},"session":{}}`, nil
+}
+
+func (s *fakeAuthService) RecordImpersonatedRequest(c *gin.Context, user responseDto.UserResponse, serviceName string, path string, method string) {
+}
+
+func (s *fakeAuthService) EnforceAuthorization(c *gin.Context, email string, scope []string, serviceName string, path string, method string) error {
+	return nil
+}
+
+func TestAuthMiddlewareIsAudienceAgnostic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		middleware func(service.AuthService, string) gin.HandlerFunc
+	}{
+		{"Authenticate", Authenticate},
+		{"Authorize", Authorize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := &fakeAuthService{}
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			c.Request.Header.Set("Authorization", "Bearer token")
+
+			tt.middleware(auth, "auth-service")(c)
+
+			if c.IsAborted() {
+				t.Fatalf("%s aborted: %v", tt.name, c.Errors)
+			}
+			if len(auth.audiences) != 1 || auth.audiences[0] != "" {
+				t.Errorf("Verify() audiences = %q, want only the empty audience", auth.audiences)
+			}
+		})
+	}
+}
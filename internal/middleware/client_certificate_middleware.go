@@ -0,0 +1,49 @@
+package middlewares
+
+import (
+	"auth-service/internal/clientcert"
+	"auth-service/pkg/utils/exception"
+	"crypto/subtle"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClientCertificate stores the client certificate forwarded by the gateway in the context (see clientcert.FromContext).
+// The certificate headers are only trusted along with the gateway secret; without it they are ignored, as is
+// every certificate when no secret is configured.
+func ClientCertificate(gatewaySecret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		escapedPEM := c.GetHeader(clientcert.CertificateHeader)
+		fingerprint := c.GetHeader(clientcert.FingerprintHeader)
+		if escapedPEM == "" && fingerprint == "" {
+			c.Next()
+			return
+		}
+
+		secret := c.GetHeader(clientcert.GatewaySecretHeader)
+		if gatewaySecret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(gatewaySecret)) != 1 {
+			slog.WarnContext(c.Request.Context(), "ignoring client certificate not forwarded by the gateway",
+				"clientIp", c.ClientIP(),
+			)
+			c.Next()
+			return
+		}
+
+		var cert clientcert.Certificate
+		var err error
+		if escapedPEM != "" {
+			cert, err = clientcert.Parse(escapedPEM)
+		} else {
+			cert, err = clientcert.FromFingerprint(fingerprint)
+		}
+		if err != nil {
+			c.Error(exception.NewBadRequest("Invalid client certificate"))
+			c.Abort()
+			return
+		}
+
+		c.Set(clientcert.ContextKey, cert)
+		c.Next()
+	}
+}
@@ -0,0 +1,58 @@
+package middlewares
+
+import (
+	"auth-service/internal/clientcert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestClientCertificate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fingerprint := strings.Repeat("AB:", 31) + "AB"
+
+	tests := []struct {
+		name          string
+		gatewaySecret string
+		headers       map[string]string
+		wantCert      bool
+		wantAborted   bool
+	}{
+		{"no certificate", "s3cret", nil, false, false},
+		{"no gateway secret configured", "", map[string]string{clientcert.FingerprintHeader: fingerprint, clientcert.GatewaySecretHeader: "s3cret"}, false, false},
+		{"missing gateway secret", "s3cret", map[string]string{clientcert.FingerprintHeader: fingerprint}, false, false},
+		{"wrong gateway secret", "s3cret", map[string]string{clientcert.FingerprintHeader: fingerprint, clientcert.GatewaySecretHeader: "guess"}, false, false},
+		{"forwarded by the gateway", "s3cret", map[string]string{clientcert.FingerprintHeader: fingerprint, clientcert.GatewaySecretHeader: "s3cret"}, true, false},
+		{"invalid fingerprint", "s3cret", map[string]string{clientcert.FingerprintHeader: "abcd", clientcert.GatewaySecretHeader: "s3cret"}, false, true},
+		{"invalid certificate", "s3cret", map[string]string{clientcert.CertificateHeader: "not-a-pem", clientcert.GatewaySecretHeader: "s3cret"}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+			for name, value := range tt.headers {
+				c.Request.Header.Set(name, value)
+			}
+
+			ClientCertificate(tt.gatewaySecret)(c)
+
+			cert, ok := clientcert.FromContext(c)
+			if ok != tt.wantCert {
+				t.Fatalf("certificate in context = %v, want %v", ok, tt.wantCert)
+			}
+			if ok && cert.Fingerprint != strings.Repeat("ab", 32) {
+				t.Errorf("fingerprint = %q", cert.Fingerprint)
+			}
+			if c.IsAborted() != tt.wantAborted {
+				t.Errorf("aborted = %v, want %v", c.IsAborted(), tt.wantAborted)
+			}
+			if tt.wantAborted && len(c.Errors) == 0 {
+				t.Error("aborted without an error")
+			}
+		})
+	}
+}
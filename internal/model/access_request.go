@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	AccessRequestPending   = "PENDING"
+	AccessRequestApproved  = "APPROVED"
+	AccessRequestRejected  = "REJECTED"
+	AccessRequestCancelled = "CANCELLED"
+)
+
+type AccessRequest struct {
+	AccessRequestID uint       `gorm:"primaryKey;column:access_request_id"`
+	UserID          uint       `gorm:"column:user_id"`
+	RoleID          uint       `gorm:"column:role_id"`
+	Justification   string     `gorm:"column:justification"`
+	DurationSeconds *int       `gorm:"column:duration_seconds"`
+	Status          string     `gorm:"column:status"`
+	DecidedBy       *uint      `gorm:"column:decided_by"`
+	DecidedAt       *time.Time `gorm:"column:decided_at"`
+	DecisionNote    string     `gorm:"column:decision_note"`
+	CreatedAt       time.Time  `gorm:"column:created_at"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at"`
+
+	User   User                 `gorm:"foreignKey:UserID;references:ID"`
+	Role   Role                 `gorm:"foreignKey:RoleID;references:RoleID"`
+	Events []AccessRequestEvent `gorm:"foreignKey:AccessRequestID;references:AccessRequestID"`
+}
+
+// AccessRequestEvent records a single state change of an access request
+type AccessRequestEvent struct {
+	AccessRequestEventID uint      `gorm:"primaryKey;column:access_request_event_id"`
+	AccessRequestID      uint      `gorm:"column:access_request_id"`
+	Status               string    `gorm:"column:status"`
+	ActorID              *uint     `gorm:"column:actor_id"`
+	Note                 string    `gorm:"column:note"`
+	CreatedAt            time.Time `gorm:"column:created_at"`
+}
+
+// RoleApprover designates a user who may decide access requests for a role
+type RoleApprover struct {
+	RoleID uint `gorm:"primaryKey;column:role_id"`
+	UserID uint `gorm:"primaryKey;column:user_id"`
+
+	User User `gorm:"foreignKey:UserID;references:ID"`
+}
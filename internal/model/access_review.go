@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	AccessReviewOpen   = "OPEN"
+	AccessReviewClosed = "CLOSED"
+
+	AccessReviewPending = "PENDING"
+	AccessReviewKeep    = "KEEP"
+	AccessReviewRevoke  = "REVOKE"
+)
+
+type AccessReviewCampaign struct {
+	CampaignID  uint       `gorm:"primaryKey;column:campaign_id"`
+	Name        string     `gorm:"column:name"`
+	Description string     `gorm:"column:description"`
+	Status      string     `gorm:"column:status"`
+	DueAt       *time.Time `gorm:"column:due_at"`
+	CreatedBy   *uint      `gorm:"column:created_by"`
+	ClosedBy    *uint      `gorm:"column:closed_by"`
+	CreatedAt   time.Time  `gorm:"column:created_at"`
+	ClosedAt    *time.Time `gorm:"column:closed_at"`
+
+	Items []AccessReviewItem `gorm:"foreignKey:CampaignID;references:CampaignID"`
+}
+
+// AccessReviewItem is a snapshot of one user_roles assignment taken when the campaign started
+type AccessReviewItem struct {
+	ItemID     uint       `gorm:"primaryKey;column:item_id"`
+	CampaignID uint       `gorm:"column:campaign_id"`
+	UserID     uint       `gorm:"column:user_id"`
+	UserEmail  string     `gorm:"column:user_email"`
+	RoleID     uint       `gorm:"column:role_id"`
+	RoleName   string     `gorm:"column:role_name"`
+	ValidFrom  *time.Time `gorm:"column:valid_from"`
+	ValidUntil *time.Time `gorm:"column:valid_until"`
+	ReviewerID *uint      `gorm:"column:reviewer_id"`
+	Decision   string     `gorm:"column:decision"`
+	Comment    string     `gorm:"column:comment"`
+	DecidedAt  *time.Time `gorm:"column:decided_at"`
+	AppliedAt  *time.Time `gorm:"column:applied_at"`
+
+	Reviewer *User                 `gorm:"foreignKey:ReviewerID;references:ID"`
+	Campaign *AccessReviewCampaign `gorm:"foreignKey:CampaignID;references:CampaignID"`
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Attribute types; date values are "YYYY-MM-DD" strings
+const (
+	AttributeTypeString  = "string"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+	AttributeTypeDate    = "date"
+)
+
+// AttributeDefinition declares a custom user attribute. Attributes that are not UserEditable can only be set
+// by admins; ExposedInTokens ones are copied into issued tokens.
+type AttributeDefinition struct {
+	AttributeDefinitionID uint      `gorm:"primaryKey;column:attribute_definition_id"`
+	Name                  string    `gorm:"column:name"`
+	Type                  string    `gorm:"column:type"`
+	Description           string    `gorm:"column:description"`
+	Required              bool      `gorm:"column:required"`
+	UserEditable          bool      `gorm:"column:user_editable"`
+	ExposedInTokens       bool      `gorm:"column:exposed_in_tokens"`
+	CreatedAt             time.Time `gorm:"column:created_at"`
+}
+
+// Attributes are the custom attribute values of a user, stored as a JSONB object
+type Attributes map[string]any
+
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(a)
+	return string(data), err
+}
+
+func (a *Attributes) Scan(value any) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*a = Attributes{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Attributes", value)
+	}
+	return json.Unmarshal(data, a)
+}
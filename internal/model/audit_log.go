@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+)
+
+type AuditLog struct {
+	AuditLogID uint      `gorm:"primaryKey;column:audit_log_id"`
+	Action     string    `gorm:"column:action"`
+	ActorID    *uint     `gorm:"column:actor_id"`
+	TargetType string    `gorm:"column:target_type"`
+	TargetID   string    `gorm:"column:target_id"`
+	Metadata   string    `gorm:"column:metadata;type:jsonb"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+}
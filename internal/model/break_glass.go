@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	BreakGlassMethodTwoPerson  = "TWO_PERSON"
+	BreakGlassMethodSealedCode = "SEALED_CODE"
+
+	BreakGlassPending  = "PENDING"
+	BreakGlassActive   = "ACTIVE"
+	BreakGlassExpired  = "EXPIRED"
+	BreakGlassDisabled = "DISABLED"
+)
+
+// BreakGlassCode is a sealed one-time code that enables a break-glass account
+type BreakGlassCode struct {
+	CodeID    uint       `gorm:"primaryKey;column:code_id"`
+	UserID    uint       `gorm:"column:user_id"`
+	CodeHash  string     `gorm:"column:code_hash"`
+	CreatedAt time.Time  `gorm:"column:created_at"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+}
+
+// BreakGlassUnsealAttempts counts the failed unseal attempts of a break-glass account since its last lockout
+type BreakGlassUnsealAttempts struct {
+	UserID      uint       `gorm:"primaryKey;column:user_id"`
+	Failures    int        `gorm:"column:failures"`
+	LockedUntil *time.Time `gorm:"column:locked_until"`
+}
+
+type BreakGlassActivation struct {
+	ActivationID uint       `gorm:"primaryKey;column:activation_id"`
+	UserID       uint       `gorm:"column:user_id"`
+	Method       string     `gorm:"column:method"`
+	Status       string     `gorm:"column:status"`
+	Reason       string     `gorm:"column:reason"`
+	RequestedBy  *uint      `gorm:"column:requested_by"`
+	ApprovedBy   *uint      `gorm:"column:approved_by"`
+	CreatedAt    time.Time  `gorm:"column:created_at"`
+	ActivatedAt  *time.Time `gorm:"column:activated_at"`
+	ExpiresAt    *time.Time `gorm:"column:expires_at"`
+
+	User User `gorm:"foreignKey:UserID;references:ID"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+)
+
+// ClaimMappingAllAudiences is the audience of mappings applied to every token
+const ClaimMappingAllAudiences = "*"
+
+// ClaimMapping adds the custom claim Claim, taken from Source, to tokens and X-User headers for Audience
+type ClaimMapping struct {
+	ClaimMappingID uint      `gorm:"primaryKey;column:claim_mapping_id"`
+	Audience       string    `gorm:"column:audience"`
+	Claim          string    `gorm:"column:claim"`
+	Source         string    `gorm:"column:source"`
+	CreatedBy      *uint     `gorm:"column:created_by"`
+	CreatedAt      time.Time `gorm:"column:created_at"`
+}
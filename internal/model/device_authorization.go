@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	DeviceAuthorizationPending  = "PENDING"
+	DeviceAuthorizationApproved = "APPROVED"
+	DeviceAuthorizationDenied   = "DENIED"
+	DeviceAuthorizationUsed     = "USED"
+)
+
+// DeviceAuthorization is a device waiting for, or granted, a token through the device authorization grant.
+// UserID is the user who approved or denied it; PollInterval is the minimum time between polls in seconds.
+type DeviceAuthorization struct {
+	DeviceAuthorizationID uint       `gorm:"primaryKey;column:device_authorization_id"`
+	DeviceCodeHash        string     `gorm:"column:device_code_hash"`
+	UserCode              string     `gorm:"column:user_code"`
+	OAuthClientID         uint       `gorm:"column:oauth_client_id"`
+	Scope                 string     `gorm:"column:scope"`
+	Status                string     `gorm:"column:status"`
+	UserID                *uint      `gorm:"column:user_id"`
+	PollInterval          int        `gorm:"column:poll_interval"`
+	LastPolledAt          *time.Time `gorm:"column:last_polled_at"`
+	ExpiresAt             time.Time  `gorm:"column:expires_at"`
+	CreatedAt             time.Time  `gorm:"column:created_at"`
+
+	OAuthClient OAuthClient `gorm:"foreignKey:OAuthClientID;references:OAuthClientID"`
+}
+
+// ExpiredAt reports whether the device authorization can no longer be approved or redeemed at t
+func (d DeviceAuthorization) ExpiredAt(t time.Time) bool {
+	return !t.Before(d.ExpiresAt)
+}
@@ -0,0 +1,17 @@
+package requestDTO
+
+type CreateAccessRequestRequest struct {
+	RoleID        uint   `json:"role_id" binding:"required"`
+	Justification string `json:"justification" binding:"required,min=10,max=2000"`
+	Duration      string `json:"duration"`
+}
+
+// DecideAccessRequestRequest approves or rejects a request. On approval Duration overrides the requested one.
+type DecideAccessRequestRequest struct {
+	Note     string `json:"note" binding:"omitempty,max=2000"`
+	Duration string `json:"duration"`
+}
+
+type AddRoleApproverRequest struct {
+	UserID uint `json:"user_id" binding:"required"`
+}
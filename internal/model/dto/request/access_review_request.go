@@ -0,0 +1,20 @@
+package requestDTO
+
+import (
+	"time"
+)
+
+// StartAccessReviewRequest starts a campaign. RoleIDs limits the snapshot to those roles; DefaultReviewerID
+// receives assignments whose role has no designated approver (the campaign creator when empty).
+type StartAccessReviewRequest struct {
+	Name              string     `json:"name" binding:"required,max=255"`
+	Description       string     `json:"description" binding:"omitempty,max=2000"`
+	DueAt             *time.Time `json:"due_at"`
+	RoleIDs           []uint     `json:"role_ids"`
+	DefaultReviewerID *uint      `json:"default_reviewer_id"`
+}
+
+type AccessReviewDecisionRequest struct {
+	Decision string `json:"decision" binding:"required,oneof=KEEP REVOKE"`
+	Comment  string `json:"comment" binding:"omitempty,max=2000"`
+}
@@ -0,0 +1,16 @@
+package requestDTO
+
+// CreateAttributeDefinitionRequest defines a custom user attribute. UserEditable defaults to true.
+type CreateAttributeDefinitionRequest struct {
+	Name            string `json:"name" binding:"required,max=100"`
+	Type            string `json:"type" binding:"required,oneof=string number boolean date"`
+	Description     string `json:"description" binding:"omitempty,max=255"`
+	Required        bool   `json:"required"`
+	UserEditable    *bool  `json:"user_editable"`
+	ExposedInTokens bool   `json:"exposed_in_tokens"`
+}
+
+// UpdateAttributesRequest sets the given attributes; a null value removes the attribute
+type UpdateAttributesRequest struct {
+	Attributes map[string]any `json:"attributes" binding:"required"`
+}
@@ -0,0 +1,12 @@
+package requestDTO
+
+type UnsealBreakGlassRequest struct {
+	Email  string `json:"email" binding:"required,email"`
+	Code   string `json:"code" binding:"required"`
+	Reason string `json:"reason" binding:"required,min=5,max=2000"`
+}
+
+type BreakGlassActivationRequest struct {
+	UserID uint   `json:"user_id" binding:"required"`
+	Reason string `json:"reason" binding:"required,min=5,max=2000"`
+}
@@ -0,0 +1,11 @@
+package requestDTO
+
+// CreateClaimMappingRequest adds a custom claim for an audience ("*" for every token).
+// Source is one of user.id, user.first_name, user.last_name, user.email, user.full_name, roles,
+// roles.joined, role:<NAME> (whether the user holds the role), attribute:<name> (a custom attribute exposed
+// in tokens) or const:<value> (JSON, or else a string).
+type CreateClaimMappingRequest struct {
+	Audience string `json:"audience" binding:"required,max=100"`
+	Claim    string `json:"claim" binding:"required,max=100"`
+	Source   string `json:"source" binding:"required,max=255"`
+}
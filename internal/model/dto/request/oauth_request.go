@@ -0,0 +1,42 @@
+package requestDTO
+
+// CreateOAuthClientRequest registers a service as OAuth client. Name is the service name it is known by
+// (as in the endpoints table); AllowedAudiences are the services it may exchange user tokens for.
+// Public clients, such as CLIs using the device authorization grant, get no secret and no audiences.
+type CreateOAuthClientRequest struct {
+	Name             string   `json:"name" binding:"required,max=100"`
+	AllowedAudiences []string `json:"allowed_audiences" binding:"omitempty,dive,required,max=100"`
+	Public           bool     `json:"public"`
+}
+
+// DeviceAuthorizationRequest starts the device authorization grant (RFC 8628). Scope, space-separated and
+// optional, limits the token to those permissions. Confidential clients may authenticate with HTTP Basic instead.
+type DeviceAuthorizationRequest struct {
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+	Scope        string `form:"scope"`
+}
+
+// DeviceDecisionRequest is the form of the device approval page
+type DeviceDecisionRequest struct {
+	UserCode string `form:"user_code" binding:"required"`
+	Email    string `form:"email" binding:"required"`
+	Password string `form:"password" binding:"required"`
+	Action   string `form:"action" binding:"required,oneof=approve deny"`
+}
+
+// TokenRequest is the form posted to the token endpoint. The client authenticates with HTTP Basic or,
+// alternatively, with ClientID and ClientSecret. The Subject* and RequestedTokenType fields belong to
+// the token exchange grant (RFC 8693), DeviceCode to the device authorization grant (RFC 8628); Scope is
+// space-separated.
+type TokenRequest struct {
+	GrantType          string `form:"grant_type" binding:"required"`
+	ClientID           string `form:"client_id"`
+	ClientSecret       string `form:"client_secret"`
+	DeviceCode         string `form:"device_code"`
+	SubjectToken       string `form:"subject_token"`
+	SubjectTokenType   string `form:"subject_token_type"`
+	RequestedTokenType string `form:"requested_token_type"`
+	Audience           string `form:"audience"`
+	Scope              string `form:"scope"`
+}
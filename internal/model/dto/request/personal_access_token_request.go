@@ -0,0 +1,12 @@
+package requestDTO
+
+import (
+	"time"
+)
+
+// CreatePersonalAccessTokenRequest creates a personal access token limited to Scope, a subset of the owner's permissions
+type CreatePersonalAccessTokenRequest struct {
+	Name      string    `json:"name" binding:"required,max=100"`
+	Scope     []string  `json:"scope" binding:"required,min=1,dive,required"`
+	ExpiresAt time.Time `json:"expires_at" binding:"required"`
+}
@@ -0,0 +1,22 @@
+package requestDTO
+
+import (
+	"time"
+)
+
+// GrantRoleRequest assigns a role to a user. The grant window is either ValidFrom/ValidUntil
+// or ValidFrom plus Duration (e.g. "2h"); leaving both ends empty makes the grant permanent.
+type GrantRoleRequest struct {
+	UserID     uint       `json:"user_id" binding:"required"`
+	RoleID     uint       `json:"role_id" binding:"required"`
+	ValidFrom  *time.Time `json:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until"`
+	Duration   string     `json:"duration"`
+	Reason     string     `json:"reason" binding:"omitempty,max=255"`
+}
+
+type RevokeRoleRequest struct {
+	UserID uint   `json:"user_id" binding:"required"`
+	RoleID uint   `json:"role_id" binding:"required"`
+	Reason string `json:"reason" binding:"omitempty,max=255"`
+}
@@ -0,0 +1,18 @@
+package requestDTO
+
+// CreateServiceAccountRequest creates a service account. Name identifies it, as in its address
+// <name>@service-accounts.invalid; roles are granted to it like to any user.
+type CreateServiceAccountRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type CreateServiceAccountKeyRequest struct {
+	Description string `json:"description" binding:"max=255"`
+}
+
+// AddServiceAccountCertificateRequest maps client certificates to a service account. Value is the certificate's
+// hex SHA-256 fingerprint, a URI SAN such as a SPIFFE ID, or a subject in RFC 2253 form ("CN=billing,O=Example").
+type AddServiceAccountCertificateRequest struct {
+	MatchType string `json:"match_type" binding:"required,oneof=FINGERPRINT URI SUBJECT"`
+	Value     string `json:"value" binding:"required,max=1024"`
+}
@@ -5,4 +5,20 @@ type RegisterRequest struct {
 	LastName  string `json:"last_name" binding:"omitempty,max=100"`
 	Email     string `json:"email" binding:"required,email"`
 	Password  string `json:"password" binding:"required,min=6"`
+	// Attributes are values of user-editable custom attributes; required ones must be present
+	Attributes map[string]any `json:"attributes"`
+}
+
+type ImpersonateRequest struct {
+	UserID uint   `json:"user_id" binding:"required"`
+	Reason string `json:"reason" binding:"required,min=5,max=255"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+type ForceLogoutRequest struct {
+	Reason string `json:"reason" binding:"omitempty,max=255"`
 }
@@ -0,0 +1,35 @@
+package responseDto
+
+import (
+	"time"
+)
+
+type AccessRequestResponse struct {
+	ID            uint                         `json:"id"`
+	UserID        uint                         `json:"user_id"`
+	UserEmail     string                       `json:"user_email,omitempty"`
+	RoleID        uint                         `json:"role_id"`
+	RoleName      string                       `json:"role_name"`
+	Justification string                       `json:"justification"`
+	Duration      string                       `json:"duration,omitempty"`
+	Status        string                       `json:"status"`
+	DecidedBy     *uint                        `json:"decided_by"`
+	DecidedAt     *time.Time                   `json:"decided_at"`
+	DecisionNote  string                       `json:"decision_note,omitempty"`
+	CreatedAt     time.Time                    `json:"created_at"`
+	Events        []AccessRequestEventResponse `json:"events,omitempty"`
+}
+
+type AccessRequestEventResponse struct {
+	Status    string    `json:"status"`
+	ActorID   *uint     `json:"actor_id"`
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RoleApproverResponse struct {
+	UserID    uint   `json:"user_id"`
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
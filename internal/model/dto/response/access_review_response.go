@@ -0,0 +1,35 @@
+package responseDto
+
+import (
+	"time"
+)
+
+type AccessReviewCampaignResponse struct {
+	ID          uint                       `json:"id"`
+	Name        string                     `json:"name"`
+	Description string                     `json:"description,omitempty"`
+	Status      string                     `json:"status"`
+	DueAt       *time.Time                 `json:"due_at"`
+	CreatedBy   *uint                      `json:"created_by"`
+	CreatedAt   time.Time                  `json:"created_at"`
+	ClosedBy    *uint                      `json:"closed_by"`
+	ClosedAt    *time.Time                 `json:"closed_at"`
+	Items       []AccessReviewItemResponse `json:"items,omitempty"`
+}
+
+type AccessReviewItemResponse struct {
+	ID            uint       `json:"id"`
+	CampaignID    uint       `json:"campaign_id"`
+	UserID        uint       `json:"user_id"`
+	UserEmail     string     `json:"user_email"`
+	RoleID        uint       `json:"role_id"`
+	RoleName      string     `json:"role_name"`
+	ValidFrom     *time.Time `json:"valid_from"`
+	ValidUntil    *time.Time `json:"valid_until"`
+	ReviewerID    *uint      `json:"reviewer_id"`
+	ReviewerEmail string     `json:"reviewer_email,omitempty"`
+	Decision      string     `json:"decision"`
+	Comment       string     `json:"comment,omitempty"`
+	DecidedAt     *time.Time `json:"decided_at"`
+	AppliedAt     *time.Time `json:"applied_at"`
+}
@@ -0,0 +1,16 @@
+package responseDto
+
+import (
+	"time"
+)
+
+type AttributeDefinitionResponse struct {
+	ID              uint      `json:"id"`
+	Name            string    `json:"name"`
+	Type            string    `json:"type"`
+	Description     string    `json:"description"`
+	Required        bool      `json:"required"`
+	UserEditable    bool      `json:"user_editable"`
+	ExposedInTokens bool      `json:"exposed_in_tokens"`
+	CreatedAt       time.Time `json:"created_at"`
+}
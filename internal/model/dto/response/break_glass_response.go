@@ -0,0 +1,19 @@
+package responseDto
+
+import (
+	"time"
+)
+
+type BreakGlassActivationResponse struct {
+	ID          uint       `json:"id"`
+	UserID      uint       `json:"user_id"`
+	UserEmail   string     `json:"user_email,omitempty"`
+	Method      string     `json:"method"`
+	Status      string     `json:"status"`
+	Reason      string     `json:"reason"`
+	RequestedBy *uint      `json:"requested_by"`
+	ApprovedBy  *uint      `json:"approved_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ActivatedAt *time.Time `json:"activated_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
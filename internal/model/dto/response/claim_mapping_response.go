@@ -0,0 +1,13 @@
+package responseDto
+
+import (
+	"time"
+)
+
+type ClaimMappingResponse struct {
+	ID        uint      `json:"id"`
+	Audience  string    `json:"audience"`
+	Claim     string    `json:"claim"`
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"created_at"`
+}
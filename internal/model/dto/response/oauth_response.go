@@ -0,0 +1,33 @@
+package responseDto
+
+import (
+	"time"
+)
+
+type OAuthClientResponse struct {
+	ID               uint      `json:"id"`
+	ClientID         string    `json:"client_id"`
+	Name             string    `json:"name"`
+	AllowedAudiences []string  `json:"allowed_audiences"`
+	Public           bool      `json:"public"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// DeviceAuthorizationResponse is returned to the device starting the device authorization grant (RFC 8628 section 3.2)
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+}
+
+// TokenResponse is the successful token endpoint response (RFC 6749 section 5.1, RFC 8693 section 2.2.1)
+type TokenResponse struct {
+	AccessToken     string `json:"access_token"`
+	IssuedTokenType string `json:"issued_token_type,omitempty"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int64  `json:"expires_in"`
+	Scope           string `json:"scope,omitempty"`
+}
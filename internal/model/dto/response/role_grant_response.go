@@ -0,0 +1,15 @@
+package responseDto
+
+import (
+	"time"
+)
+
+type RoleGrantResponse struct {
+	UserID      uint       `json:"user_id"`
+	RoleID      uint       `json:"role_id"`
+	RoleName    string     `json:"role_name"`
+	ValidFrom   *time.Time `json:"valid_from"`
+	ValidUntil  *time.Time `json:"valid_until"`
+	ActivatedAt *time.Time `json:"activated_at"`
+	Active      bool       `json:"active"`
+}
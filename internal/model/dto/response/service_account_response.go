@@ -0,0 +1,29 @@
+package responseDto
+
+import (
+	"time"
+)
+
+type ServiceAccountResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Roles     []string  `json:"roles"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ServiceAccountKeyResponse struct {
+	ID          uint       `json:"id"`
+	KeyID       string     `json:"key_id"`
+	Description string     `json:"description"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type ServiceAccountCertificateResponse struct {
+	ID        uint      `json:"id"`
+	MatchType string    `json:"match_type"`
+	Value     string    `json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,8 +1,44 @@
 package responseDto
 
 type UserResponse struct {
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Email     string `json:"email"`
-	Roles     string `json:"roles"`
+	FirstName string         `json:"first_name"`
+	LastName  string         `json:"last_name"`
+	Email     string         `json:"email"`
+	Roles     string         `json:"roles"`
+	Scope     []string       `json:"scope,omitempty"`
+	Actor     *ActorResponse `json:"act,omitempty"`
+}
+
+// ActorResponse identifies who acts on behalf of the user: the admin of an impersonation token (ID and Email),
+// or the service that exchanged the token (Subject). Actor chains the previous actor, if any.
+type ActorResponse struct {
+	ID      uint           `json:"id,omitempty"`
+	Email   string         `json:"email,omitempty"`
+	Subject string         `json:"sub,omitempty"`
+	Actor   *ActorResponse `json:"act,omitempty"`
+}
+
+// Impersonator returns the impersonating admin in the actor chain, or nil
+func (a *ActorResponse) Impersonator() *ActorResponse {
+	for actor := a; actor != nil; actor = actor.Actor {
+		if actor.ID != 0 {
+			return actor
+		}
+	}
+	return nil
+}
+
+// UserResponseV2 is the /api/v2 view of a verified user, with roles as a list.
+// Permissions are only filled when the caller asks for them; Attributes are those exposed in tokens.
+// Scope, when set, limits the token to those permissions.
+type UserResponseV2 struct {
+	ID          uint           `json:"id"`
+	FirstName   string         `json:"first_name"`
+	LastName    string         `json:"last_name"`
+	Email       string         `json:"email"`
+	Roles       []string       `json:"roles"`
+	Permissions []string       `json:"permissions,omitempty"`
+	Attributes  map[string]any `json:"attributes,omitempty"`
+	Scope       []string       `json:"scope,omitempty"`
+	Actor       *ActorResponse `json:"act,omitempty"`
 }
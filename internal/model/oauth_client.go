@@ -0,0 +1,41 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// OAuthClient is a service allowed to call the token endpoint. Name is the service name it is known by;
+// AllowedAudiences, comma-separated, are the services it may exchange tokens for. Public clients, such as
+// CLIs, have no secret.
+type OAuthClient struct {
+	OAuthClientID    uint      `gorm:"primaryKey;column:oauth_client_id"`
+	ClientID         string    `gorm:"column:client_id"`
+	Name             string    `gorm:"column:name"`
+	SecretHash       string    `gorm:"column:secret_hash"`
+	AllowedAudiences string    `gorm:"column:allowed_audiences"`
+	Public           bool      `gorm:"column:public"`
+	CreatedBy        *uint     `gorm:"column:created_by"`
+	CreatedAt        time.Time `gorm:"column:created_at"`
+}
+
+func (OAuthClient) TableName() string {
+	return "oauth_clients"
+}
+
+// Audiences returns the allowed audiences as a list
+func (c OAuthClient) Audiences() []string {
+	audiences := []string{}
+	for _, audience := range strings.Split(c.AllowedAudiences, ",") {
+		if audience = strings.TrimSpace(audience); audience != "" {
+			audiences = append(audiences, audience)
+		}
+	}
+	return audiences
+}
+
+// AllowsAudience reports whether the client may exchange tokens for the audience
+func (c OAuthClient) AllowsAudience(audience string) bool {
+	return slices.Contains(c.Audiences(), audience)
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// PersonalAccessToken is a long-lived token a user created for scripts, limited to Scope, space-separated
+// permission names. Only the hash of the token is stored; TokenPrefix is its start, shown to tell tokens apart.
+// Generation is the owner's token generation at creation, so signing out everywhere ends the token too.
+type PersonalAccessToken struct {
+	PersonalAccessTokenID uint       `gorm:"primaryKey;column:personal_access_token_id"`
+	UserID                uint       `gorm:"column:user_id"`
+	Name                  string     `gorm:"column:name"`
+	TokenHash             string     `gorm:"column:token_hash"`
+	TokenPrefix           string     `gorm:"column:token_prefix"`
+	Scope                 string     `gorm:"column:scope"`
+	Generation            int        `gorm:"column:generation"`
+	ExpiresAt             time.Time  `gorm:"column:expires_at"`
+	LastUsedAt            *time.Time `gorm:"column:last_used_at"`
+	RevokedAt             *time.Time `gorm:"column:revoked_at"`
+	CreatedAt             time.Time  `gorm:"column:created_at"`
+}
+
+// Permissions returns the scope as a list
+func (t PersonalAccessToken) Permissions() []string {
+	return strings.Fields(t.Scope)
+}
+
+// IsActiveAt reports whether the token is neither revoked nor expired at t
+func (t PersonalAccessToken) IsActiveAt(at time.Time) bool {
+	return t.RevokedAt == nil && at.Before(t.ExpiresAt)
+}
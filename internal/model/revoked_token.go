@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+)
+
+// RevokedToken is a denylist entry: a single token by JTI, or every token of UserID issued under a generation below MinGeneration
+type RevokedToken struct {
+	RevokedTokenID uint      `gorm:"primaryKey;column:revoked_token_id"`
+	JTI            *string   `gorm:"column:jti"`
+	UserID         *uint     `gorm:"column:user_id"`
+	MinGeneration  *int      `gorm:"column:min_generation"`
+	ExpiresAt      time.Time `gorm:"column:expires_at"`
+	CreatedAt      time.Time `gorm:"column:created_at"`
+}
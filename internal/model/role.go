@@ -4,6 +4,8 @@ type Role struct {
 	RoleID      uint   `gorm:"primaryKey;column:role_id"`
 	Name        string `gorm:"column:name"`
 	Description string `gorm:"column:description"`
+	// MaxSessions caps concurrent sessions of the role's holders; nil falls back to the global limit, 0 means unlimited
+	MaxSessions *int `gorm:"column:max_sessions"`
 
 	Permissions []Permission `gorm:"many2many:role_permissions;joinForeignKey:RoleID;joinReferences:PermissionID"`
 }
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+// How a client certificate is matched to a service account
+const (
+	CertificateMatchFingerprint = "FINGERPRINT"
+	CertificateMatchURI         = "URI"
+	CertificateMatchSubject     = "SUBJECT"
+)
+
+// ServiceAccountCertificate maps client certificates to a service account: the certificate with a given
+// fingerprint, or every certificate with a given URI SAN (such as a SPIFFE ID) or subject.
+type ServiceAccountCertificate struct {
+	ServiceAccountCertificateID uint      `gorm:"primaryKey;column:service_account_certificate_id"`
+	UserID                      uint      `gorm:"column:user_id"`
+	MatchType                   string    `gorm:"column:match_type"`
+	MatchValue                  string    `gorm:"column:match_value"`
+	CreatedBy                   *uint     `gorm:"column:created_by"`
+	CreatedAt                   time.Time `gorm:"column:created_at"`
+
+	User User `gorm:"foreignKey:UserID;references:ID"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+)
+
+// ServiceAccountKey is an API key of a service account. The secret is never stored: it is derived from the
+// configured master secret and KeyID.
+type ServiceAccountKey struct {
+	ServiceAccountKeyID uint       `gorm:"primaryKey;column:service_account_key_id"`
+	UserID              uint       `gorm:"column:user_id"`
+	KeyID               string     `gorm:"column:key_id"`
+	Description         string     `gorm:"column:description"`
+	LastUsedAt          *time.Time `gorm:"column:last_used_at"`
+	RevokedAt           *time.Time `gorm:"column:revoked_at"`
+	CreatedBy           *uint      `gorm:"column:created_by"`
+	CreatedAt           time.Time  `gorm:"column:created_at"`
+
+	User User `gorm:"foreignKey:UserID;references:ID"`
+}
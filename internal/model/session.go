@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+)
+
+// Session describes where and when a token was issued. It is stored next to the token's user data.
+type Session struct {
+	ID         string    `json:"id"`
+	UserID     uint      `json:"user_id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	// ExpiresAt is the absolute end of the session, however active it is
+	ExpiresAt time.Time `json:"expires_at"`
+	// Generation is the user's token generation at issue time, checked for tokens that carry no claims
+	Generation int `json:"gen,omitempty"`
+	// ExchangedBy is the client that obtained the token through token exchange. Such a session is a delegation
+	// rather than a sign-in, so it is neither listed to the user nor counted toward session limits.
+	ExchangedBy string `json:"exchanged_by,omitempty"`
+}
+
+// Exchanged reports whether the session belongs to a token obtained through token exchange
+func (s Session) Exchanged() bool {
+	return s.ExchangedBy != ""
+}
+
+// IdleExpired reports whether the session saw no activity for longer than idleTimeout (0 disables the check)
+func (s Session) IdleExpired(now time.Time, idleTimeout time.Duration) bool {
+	return idleTimeout > 0 && now.Sub(s.LastSeenAt) > idleTimeout
+}
+
+// LifetimeExpired reports whether the absolute lifetime has ended. Sessions without a recorded end rely on the token expiry.
+func (s Session) LifetimeExpired(now time.Time) bool {
+	return !s.ExpiresAt.IsZero() && !now.Before(s.ExpiresAt)
+}
+
+// SlidingTTL is how long the session's storage must live from now: until the idle deadline, capped by the absolute end
+func (s Session) SlidingTTL(now time.Time, idleTimeout time.Duration) time.Duration {
+	remaining := s.ExpiresAt.Sub(now)
+	if s.ExpiresAt.IsZero() || (idleTimeout > 0 && idleTimeout < remaining) {
+		return idleTimeout
+	}
+	return remaining
+}
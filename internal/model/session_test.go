@@ -0,0 +1,70 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		session        Session
+		idleTimeout    time.Duration
+		wantIdle       bool
+		wantLifetime   bool
+		wantSlidingTTL time.Duration
+	}{
+		{
+			name:           "active session",
+			session:        Session{LastSeenAt: now.Add(-time.Minute), ExpiresAt: now.Add(time.Hour)},
+			idleTimeout:    30 * time.Minute,
+			wantSlidingTTL: 30 * time.Minute,
+		},
+		{
+			name:           "idle session",
+			session:        Session{LastSeenAt: now.Add(-31 * time.Minute), ExpiresAt: now.Add(time.Hour)},
+			idleTimeout:    30 * time.Minute,
+			wantIdle:       true,
+			wantSlidingTTL: 30 * time.Minute,
+		},
+		{
+			name:           "idle timeout disabled",
+			session:        Session{LastSeenAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)},
+			wantSlidingTTL: time.Hour,
+		},
+		{
+			name:           "idle deadline capped by the absolute end",
+			session:        Session{LastSeenAt: now, ExpiresAt: now.Add(10 * time.Minute)},
+			idleTimeout:    30 * time.Minute,
+			wantSlidingTTL: 10 * time.Minute,
+		},
+		{
+			name:         "lifetime ended",
+			session:      Session{LastSeenAt: now, ExpiresAt: now},
+			idleTimeout:  30 * time.Minute,
+			wantLifetime: true,
+		},
+		{
+			name:           "session without a recorded end",
+			session:        Session{LastSeenAt: now},
+			idleTimeout:    30 * time.Minute,
+			wantSlidingTTL: 30 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.session.IdleExpired(now, tt.idleTimeout); got != tt.wantIdle {
+				t.Errorf("IdleExpired() = %v, want %v", got, tt.wantIdle)
+			}
+			if got := tt.session.LifetimeExpired(now); got != tt.wantLifetime {
+				t.Errorf("LifetimeExpired() = %v, want %v", got, tt.wantLifetime)
+			}
+			if got := tt.session.SlidingTTL(now, tt.idleTimeout); got != tt.wantSlidingTTL {
+				t.Errorf("SlidingTTL() = %v, want %v", got, tt.wantSlidingTTL)
+			}
+		})
+	}
+}
@@ -4,14 +4,43 @@ import (
 	"time"
 )
 
+const (
+	AccountTypeStandard   = "STANDARD"
+	AccountTypeBreakGlass = "BREAK_GLASS"
+	// AccountTypeService is a non-human principal; it has no password and authenticates with API keys
+	AccountTypeService = "SERVICE"
+)
+
 type User struct {
-	ID        uint      `gorm:"primaryKey;column:id"`
-	FirstName string    `gorm:"column:first_name"`
-	LastName  string    `gorm:"column:last_name"`
-	Email     string    `gorm:"column:email;unique"`
-	Password  string    `gorm:"column:password"`
-	CreatedAt time.Time `gorm:"column:created_at"`
-	UpdatedAt time.Time `gorm:"column:updated_at"`
+	ID           uint       `gorm:"primaryKey;column:id"`
+	FirstName    string     `gorm:"column:first_name"`
+	LastName     string     `gorm:"column:last_name"`
+	Email        string     `gorm:"column:email;unique"`
+	Password     string     `gorm:"column:password"`
+	AccountType  string     `gorm:"column:account_type;default:STANDARD"`
+	EnabledUntil *time.Time `gorm:"column:enabled_until"`
+	// TokenGeneration is embedded in issued tokens; tokens of an older generation are rejected
+	TokenGeneration int `gorm:"column:token_generation"`
+	// Attributes hold the values of the admin-defined custom attributes
+	Attributes Attributes `gorm:"column:attributes;type:jsonb"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at"`
 
 	Roles []Role `gorm:"many2many:user_roles;joinForeignKey:UserId;joinReferences:RoleID"`
 }
+
+func (u User) IsBreakGlass() bool {
+	return u.AccountType == AccountTypeBreakGlass
+}
+
+func (u User) IsServiceAccount() bool {
+	return u.AccountType == AccountTypeService
+}
+
+// CanLoginAt reports whether the account may open a session at t. Break-glass accounts are disabled outside their activation window.
+func (u User) CanLoginAt(t time.Time) bool {
+	if !u.IsBreakGlass() {
+		return true
+	}
+	return u.EnabledUntil != nil && u.EnabledUntil.After(t)
+}
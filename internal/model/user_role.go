@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+)
+
+// UserRole is a role assignment. A nil ValidFrom/ValidUntil leaves that side of the window open.
+type UserRole struct {
+	UserID      uint       `gorm:"primaryKey;column:user_id"`
+	RoleID      uint       `gorm:"primaryKey;column:role_id"`
+	ValidFrom   *time.Time `gorm:"column:valid_from"`
+	ValidUntil  *time.Time `gorm:"column:valid_until"`
+	ActivatedAt *time.Time `gorm:"column:activated_at"`
+	GrantedBy   *uint      `gorm:"column:granted_by"`
+	CreatedAt   time.Time  `gorm:"column:created_at"`
+
+	User User `gorm:"foreignKey:UserID;references:ID"`
+	Role Role `gorm:"foreignKey:RoleID;references:RoleID"`
+}
+
+func (UserRole) TableName() string {
+	return "user_roles"
+}
+
+// IsActiveAt reports whether the grant window contains t.
+func (ur UserRole) IsActiveAt(t time.Time) bool {
+	if ur.ValidFrom != nil && ur.ValidFrom.After(t) {
+		return false
+	}
+	if ur.ValidUntil != nil && !ur.ValidUntil.After(t) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package nonce
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryStore struct {
+	mu       sync.Mutex
+	entries  map[string]time.Time
+	prunedAt time.Time
+}
+
+// NewMemoryStore records used values in process memory. They are not shared between instances, so behind a load
+// balancer a value can be used once against each instance; it is meant for development like the memory session store.
+func NewMemoryStore() Store {
+	return &memoryStore{entries: make(map[string]time.Time)}
+}
+
+func (s *memoryStore) Use(ctx context.Context, value string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.prunedAt) >= time.Second {
+		for entry, expiresAt := range s.entries {
+			if !expiresAt.After(now) {
+				delete(s.entries, entry)
+			}
+		}
+		s.prunedAt = now
+	}
+	if expiresAt, used := s.entries[value]; used && expiresAt.After(now) {
+		return false, nil
+	}
+	s.entries[value] = now.Add(ttl)
+	return true, nil
+}
@@ -0,0 +1,37 @@
+package nonce
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreUse(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	steps := []struct {
+		name  string
+		value string
+		ttl   time.Duration
+		wait  time.Duration
+		fresh bool
+	}{
+		{"first use", "a", 50 * time.Millisecond, 0, true},
+		{"replay", "a", 50 * time.Millisecond, 0, false},
+		{"another value", "b", time.Minute, 0, true},
+		{"use after expiry", "a", time.Minute, 60 * time.Millisecond, true},
+		{"replay after reuse", "a", time.Minute, 0, false},
+	}
+
+	for _, step := range steps {
+		time.Sleep(step.wait)
+		fresh, err := store.Use(ctx, step.value, step.ttl)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if fresh != step.fresh {
+			t.Errorf("%s: Use(%q) = %v, want %v", step.name, step.value, fresh, step.fresh)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package nonce
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type postgresStore struct {
+	db *gorm.DB
+
+	// sweepInterval bounds how often expired rows are deleted
+	sweepInterval time.Duration
+	mu            sync.Mutex
+	lastSweep     time.Time
+}
+
+// NewPostgresStore records used values in the request_nonces table
+func NewPostgresStore(db *gorm.DB) Store {
+	return &postgresStore{db: db, sweepInterval: time.Minute}
+}
+
+func (s *postgresStore) Use(ctx context.Context, value string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	// An expired row is taken over; a live one leaves the insert without effect
+	result := s.db.WithContext(ctx).Exec(`
+		INSERT INTO request_nonces (nonce, expires_at) VALUES (?, ?)
+		ON CONFLICT (nonce) DO UPDATE SET expires_at = EXCLUDED.expires_at
+		WHERE request_nonces.expires_at <= ?`,
+		value, now.Add(ttl), now,
+	)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	s.sweep(ctx, now)
+	return result.RowsAffected == 1, nil
+}
+
+// sweep deletes expired rows at most once per sweepInterval
+func (s *postgresStore) sweep(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < s.sweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	if err := s.db.WithContext(ctx).Exec("DELETE FROM request_nonces WHERE expires_at <= ?", now).Error; err != nil {
+		slog.ErrorContext(ctx, "failed to sweep expired nonces",
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,25 @@
+package nonce
+
+import (
+	"context"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// redisKeyPrefix is the prefix of used values in Redis
+const redisKeyPrefix = "auth:nonce:"
+
+type redisStore struct {
+	client goredis.UniversalClient
+	prefix string
+}
+
+// NewRedisStore records used values as keys under prefix that expire with them
+func NewRedisStore(client goredis.UniversalClient, prefix string) Store {
+	return &redisStore{client, prefix}
+}
+
+func (s *redisStore) Use(ctx context.Context, value string, ttl time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, s.prefix+value, 1, ttl).Result()
+}
@@ -0,0 +1,41 @@
+// Package nonce records values that may only be used once, such as the signatures of signed requests, in the
+// backend shared by every instance, so that a value used against one instance is refused by all of them.
+package nonce
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/infra/db"
+	"auth-service/internal/infra/redis"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Store records used values until they expire
+type Store interface {
+	// Use records the value for ttl, which must be positive. It reports false when the value was already recorded
+	// and has not expired yet.
+	Use(ctx context.Context, value string, ttl time.Duration) (bool, error)
+}
+
+// Open builds the store of the backend selected by SESSION_STORE, which must already be connected by session.Open:
+// used values live next to the sessions.
+func Open(cfg config.Config) (Store, error) {
+	switch cfg.SessionStore {
+	case config.SessionStoreRedis:
+		if redis.Rdb == nil {
+			return nil, errors.New("redis nonce store requires a Redis connection")
+		}
+		return NewRedisStore(redis.Rdb, redisKeyPrefix), nil
+	case config.SessionStorePostgres:
+		if db.DB == nil {
+			return nil, errors.New("postgres nonce store requires a database connection")
+		}
+		return NewPostgresStore(db.DB), nil
+	case config.SessionStoreMemory:
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown nonce store %q", cfg.SessionStore)
+	}
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type AccessRequestRepository interface {
+	Create(accessRequest model.AccessRequest) (model.AccessRequest, error)
+	FindByID(id uint) (model.AccessRequest, error)
+	FindByUserID(userID uint) ([]model.AccessRequest, error)
+	FindByRoleIDs(roleIDs []uint, status string) ([]model.AccessRequest, error)
+	ExistsPending(userID uint, roleID uint) (bool, error)
+	Decide(id uint, status string, actorID uint, note string, at time.Time) (bool, error)
+	Reopen(id uint, at time.Time) (bool, error)
+	CreateEvent(event model.AccessRequestEvent) error
+}
+
+type accessRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewAccessRequestRepository(db *gorm.DB) AccessRequestRepository {
+	return &accessRequestRepository{db}
+}
+
+func (r *accessRequestRepository) Create(accessRequest model.AccessRequest) (model.AccessRequest, error) {
+	result := r.db.Omit("User", "Role", "Events").Create(&accessRequest)
+	return accessRequest, result.Error
+}
+
+func (r *accessRequestRepository) FindByID(id uint) (model.AccessRequest, error) {
+	var accessRequest model.AccessRequest
+	result := r.db.Preload("User").Preload("Role").
+		Preload("Events", func(db *gorm.DB) *gorm.DB { return db.Order("created_at, access_request_event_id") }).
+		Where("access_request_id = ?", id).
+		First(&accessRequest)
+	return accessRequest, result.Error
+}
+
+func (r *accessRequestRepository) FindByUserID(userID uint) ([]model.AccessRequest, error) {
+	var accessRequests []model.AccessRequest
+	result := r.db.Preload("Role").Where("user_id = ?", userID).Order("created_at DESC").Find(&accessRequests)
+	return accessRequests, result.Error
+}
+
+// FindByRoleIDs lists requests for the given roles; an empty status matches every status.
+func (r *accessRequestRepository) FindByRoleIDs(roleIDs []uint, status string) ([]model.AccessRequest, error) {
+	var accessRequests []model.AccessRequest
+	if len(roleIDs) == 0 {
+		return accessRequests, nil
+	}
+
+	query := r.db.Preload("User").Preload("Role").Where("role_id IN (?)", roleIDs)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	result := query.Order("created_at DESC").Find(&accessRequests)
+	return accessRequests, result.Error
+}
+
+func (r *accessRequestRepository) ExistsPending(userID uint, roleID uint) (bool, error) {
+	var count int64
+	result := r.db.Model(&model.AccessRequest{}).
+		Where("user_id = ? AND role_id = ? AND status = ?", userID, roleID, model.AccessRequestPending).
+		Count(&count)
+	return count > 0, result.Error
+}
+
+// Decide moves a pending request to its final status. It reports false when the request was no longer pending.
+func (r *accessRequestRepository) Decide(id uint, status string, actorID uint, note string, at time.Time) (bool, error) {
+	result := r.db.Model(&model.AccessRequest{}).
+		Where("access_request_id = ? AND status = ?", id, model.AccessRequestPending).
+		Updates(map[string]any{
+			"status":        status,
+			"decided_by":    actorID,
+			"decided_at":    at,
+			"decision_note": note,
+			"updated_at":    at,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Reopen moves an approved request back to pending, clearing its decision. It reports false when it was not approved.
+func (r *accessRequestRepository) Reopen(id uint, at time.Time) (bool, error) {
+	result := r.db.Model(&model.AccessRequest{}).
+		Where("access_request_id = ? AND status = ?", id, model.AccessRequestApproved).
+		Updates(map[string]any{
+			"status":        model.AccessRequestPending,
+			"decided_by":    nil,
+			"decided_at":    nil,
+			"decision_note": "",
+			"updated_at":    at,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *accessRequestRepository) CreateEvent(event model.AccessRequestEvent) error {
+	return r.db.Create(&event).Error
+}
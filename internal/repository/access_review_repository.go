@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type AccessReviewRepository interface {
+	CreateCampaign(campaign model.AccessReviewCampaign) (model.AccessReviewCampaign, error)
+	FindCampaigns() ([]model.AccessReviewCampaign, error)
+	FindCampaignByID(id uint) (model.AccessReviewCampaign, error)
+	FindItemByID(id uint) (model.AccessReviewItem, error)
+	FindOpenItemsByReviewer(reviewerID uint) ([]model.AccessReviewItem, error)
+	CountPendingItems(campaignID uint) (int64, error)
+	DecideItem(id uint, decision string, comment string, at time.Time) error
+	MarkItemApplied(id uint, at time.Time) error
+	CloseCampaign(id uint, actorID uint, at time.Time) (bool, error)
+}
+
+type accessReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewAccessReviewRepository(db *gorm.DB) AccessReviewRepository {
+	return &accessReviewRepository{db}
+}
+
+// CreateCampaign saves the campaign together with its items in a single transaction
+func (r *accessReviewRepository) CreateCampaign(campaign model.AccessReviewCampaign) (model.AccessReviewCampaign, error) {
+	result := r.db.Create(&campaign)
+	return campaign, result.Error
+}
+
+func (r *accessReviewRepository) FindCampaigns() ([]model.AccessReviewCampaign, error) {
+	var campaigns []model.AccessReviewCampaign
+	result := r.db.Order("created_at DESC").Find(&campaigns)
+	return campaigns, result.Error
+}
+
+func (r *accessReviewRepository) FindCampaignByID(id uint) (model.AccessReviewCampaign, error) {
+	var campaign model.AccessReviewCampaign
+	result := r.db.
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("user_id, role_id") }).
+		Preload("Items.Reviewer").
+		Where("campaign_id = ?", id).
+		First(&campaign)
+	return campaign, result.Error
+}
+
+func (r *accessReviewRepository) FindItemByID(id uint) (model.AccessReviewItem, error) {
+	var item model.AccessReviewItem
+	result := r.db.Preload("Campaign").Where("item_id = ?", id).First(&item)
+	return item, result.Error
+}
+
+func (r *accessReviewRepository) FindOpenItemsByReviewer(reviewerID uint) ([]model.AccessReviewItem, error) {
+	var items []model.AccessReviewItem
+	result := r.db.Preload("Campaign").
+		Joins("JOIN access_review_campaigns c ON c.campaign_id = access_review_items.campaign_id").
+		Where("access_review_items.reviewer_id = ? AND c.status = ?", reviewerID, model.AccessReviewOpen).
+		Order("access_review_items.campaign_id, access_review_items.item_id").
+		Find(&items)
+	return items, result.Error
+}
+
+func (r *accessReviewRepository) CountPendingItems(campaignID uint) (int64, error) {
+	var count int64
+	result := r.db.Model(&model.AccessReviewItem{}).
+		Where("campaign_id = ? AND decision = ?", campaignID, model.AccessReviewPending).
+		Count(&count)
+	return count, result.Error
+}
+
+func (r *accessReviewRepository) DecideItem(id uint, decision string, comment string, at time.Time) error {
+	return r.db.Model(&model.AccessReviewItem{}).
+		Where("item_id = ?", id).
+		Updates(map[string]any{
+			"decision":   decision,
+			"comment":    comment,
+			"decided_at": at,
+		}).Error
+}
+
+func (r *accessReviewRepository) MarkItemApplied(id uint, at time.Time) error {
+	return r.db.Model(&model.AccessReviewItem{}).Where("item_id = ?", id).Update("applied_at", at).Error
+}
+
+// CloseCampaign moves an open campaign to closed. It reports false when the campaign was no longer open.
+func (r *accessReviewRepository) CloseCampaign(id uint, actorID uint, at time.Time) (bool, error) {
+	result := r.db.Model(&model.AccessReviewCampaign{}).
+		Where("campaign_id = ? AND status = ?", id, model.AccessReviewOpen).
+		Updates(map[string]any{
+			"status":    model.AccessReviewClosed,
+			"closed_by": actorID,
+			"closed_at": at,
+		})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"gorm.io/gorm"
+)
+
+type AttributeRepository interface {
+	FindAll() ([]model.AttributeDefinition, error)
+	FindByName(name string) (model.AttributeDefinition, error)
+	Create(definition model.AttributeDefinition) (model.AttributeDefinition, error)
+	Delete(id uint) (bool, error)
+}
+
+type attributeRepository struct {
+	db *gorm.DB
+}
+
+func NewAttributeRepository(db *gorm.DB) AttributeRepository {
+	return &attributeRepository{db}
+}
+
+func (r *attributeRepository) FindAll() ([]model.AttributeDefinition, error) {
+	var definitions []model.AttributeDefinition
+	result := r.db.Order("name").Find(&definitions)
+	return definitions, result.Error
+}
+
+func (r *attributeRepository) FindByName(name string) (model.AttributeDefinition, error) {
+	var definition model.AttributeDefinition
+	result := r.db.Where("name = ?", name).First(&definition)
+	return definition, result.Error
+}
+
+func (r *attributeRepository) Create(definition model.AttributeDefinition) (model.AttributeDefinition, error) {
+	result := r.db.Create(&definition)
+	return definition, result.Error
+}
+
+func (r *attributeRepository) Delete(id uint) (bool, error) {
+	result := r.db.Where("attribute_definition_id = ?", id).Delete(&model.AttributeDefinition{})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"gorm.io/gorm"
+)
+
+type AuditRepository interface {
+	Create(log model.AuditLog) (model.AuditLog, error)
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db}
+}
+
+func (r *auditRepository) Create(log model.AuditLog) (model.AuditLog, error) {
+	result := r.db.Create(&log)
+	return log, result.Error
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type BreakGlassRepository interface {
+	ReplaceCode(code model.BreakGlassCode) (model.BreakGlassCode, error)
+	FindUnusedCodes(userID uint) ([]model.BreakGlassCode, error)
+	MarkCodeUsed(codeID uint, at time.Time) (bool, error)
+	FindUnsealLock(userID uint) (*time.Time, error)
+	RecordUnsealFailure(userID uint, maxAttempts int, lockedUntil time.Time) (bool, error)
+	ResetUnsealFailures(userID uint) error
+	CreateActivation(activation model.BreakGlassActivation) (model.BreakGlassActivation, error)
+	FindActivationByID(id uint) (model.BreakGlassActivation, error)
+	FindActivations() ([]model.BreakGlassActivation, error)
+	ApproveActivation(id uint, approverID uint, at time.Time, expiresAt time.Time) (bool, error)
+	CloseActiveActivations(userID uint, status string) error
+}
+
+type breakGlassRepository struct {
+	db *gorm.DB
+}
+
+func NewBreakGlassRepository(db *gorm.DB) BreakGlassRepository {
+	return &breakGlassRepository{db}
+}
+
+// ReplaceCode stores a new sealed code and discards every unused code previously issued for the account
+func (r *breakGlassRepository) ReplaceCode(code model.BreakGlassCode) (model.BreakGlassCode, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND used_at IS NULL", code.UserID).Delete(&model.BreakGlassCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&code).Error
+	})
+	return code, err
+}
+
+func (r *breakGlassRepository) FindUnusedCodes(userID uint) ([]model.BreakGlassCode, error) {
+	var codes []model.BreakGlassCode
+	result := r.db.Where("user_id = ? AND used_at IS NULL", userID).Find(&codes)
+	return codes, result.Error
+}
+
+// MarkCodeUsed consumes a code. It reports false when the code was already used.
+func (r *breakGlassRepository) MarkCodeUsed(codeID uint, at time.Time) (bool, error) {
+	result := r.db.Model(&model.BreakGlassCode{}).
+		Where("code_id = ? AND used_at IS NULL", codeID).
+		Update("used_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindUnsealLock returns until when unsealing the account is locked, or nil when it never was
+func (r *breakGlassRepository) FindUnsealLock(userID uint) (*time.Time, error) {
+	var attempts model.BreakGlassUnsealAttempts
+	result := r.db.Where("user_id = ?", userID).Limit(1).Find(&attempts)
+	return attempts.LockedUntil, result.Error
+}
+
+// RecordUnsealFailure counts a failed unseal attempt. The maxAttempts-th failure locks unsealing until lockedUntil
+// and starts the count over; it reports whether this attempt locked the account.
+func (r *breakGlassRepository) RecordUnsealFailure(userID uint, maxAttempts int, lockedUntil time.Time) (bool, error) {
+	locked := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var attempts model.BreakGlassUnsealAttempts
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).Limit(1).Find(&attempts).Error; err != nil {
+			return err
+		}
+
+		attempts.UserID = userID
+		attempts.Failures++
+		if attempts.Failures >= maxAttempts {
+			attempts.Failures = 0
+			attempts.LockedUntil = &lockedUntil
+			locked = true
+		}
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&attempts).Error
+	})
+	return locked, err
+}
+
+// ResetUnsealFailures forgets the failed attempts of the account after it was unsealed
+func (r *breakGlassRepository) ResetUnsealFailures(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&model.BreakGlassUnsealAttempts{}).Error
+}
+
+func (r *breakGlassRepository) CreateActivation(activation model.BreakGlassActivation) (model.BreakGlassActivation, error) {
+	result := r.db.Omit("User").Create(&activation)
+	return activation, result.Error
+}
+
+func (r *breakGlassRepository) FindActivationByID(id uint) (model.BreakGlassActivation, error) {
+	var activation model.BreakGlassActivation
+	result := r.db.Preload("User").Where("activation_id = ?", id).First(&activation)
+	return activation, result.Error
+}
+
+func (r *breakGlassRepository) FindActivations() ([]model.BreakGlassActivation, error) {
+	var activations []model.BreakGlassActivation
+	result := r.db.Preload("User").Order("created_at DESC").Find(&activations)
+	return activations, result.Error
+}
+
+// ApproveActivation activates a pending activation. It reports false when it was no longer pending.
+func (r *breakGlassRepository) ApproveActivation(id uint, approverID uint, at time.Time, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&model.BreakGlassActivation{}).
+		Where("activation_id = ? AND status = ?", id, model.BreakGlassPending).
+		Updates(map[string]any{
+			"status":       model.BreakGlassActive,
+			"approved_by":  approverID,
+			"activated_at": at,
+			"expires_at":   expiresAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// CloseActiveActivations moves the account's active activations to the given final status
+func (r *breakGlassRepository) CloseActiveActivations(userID uint, status string) error {
+	return r.db.Model(&model.BreakGlassActivation{}).
+		Where("user_id = ? AND status = ?", userID, model.BreakGlassActive).
+		Update("status", status).Error
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"gorm.io/gorm"
+)
+
+type ClaimMappingRepository interface {
+	FindAll() ([]model.ClaimMapping, error)
+	FindByAudienceAndClaim(audience string, claim string) (model.ClaimMapping, error)
+	Create(mapping model.ClaimMapping) (model.ClaimMapping, error)
+	Delete(id uint) (bool, error)
+}
+
+type claimMappingRepository struct {
+	db *gorm.DB
+}
+
+func NewClaimMappingRepository(db *gorm.DB) ClaimMappingRepository {
+	return &claimMappingRepository{db}
+}
+
+func (r *claimMappingRepository) FindAll() ([]model.ClaimMapping, error) {
+	var mappings []model.ClaimMapping
+	result := r.db.Order("audience, claim").Find(&mappings)
+	return mappings, result.Error
+}
+
+func (r *claimMappingRepository) FindByAudienceAndClaim(audience string, claim string) (model.ClaimMapping, error) {
+	var mapping model.ClaimMapping
+	result := r.db.Where("audience = ? AND claim = ?", audience, claim).First(&mapping)
+	return mapping, result.Error
+}
+
+func (r *claimMappingRepository) Create(mapping model.ClaimMapping) (model.ClaimMapping, error) {
+	result := r.db.Create(&mapping)
+	return mapping, result.Error
+}
+
+func (r *claimMappingRepository) Delete(id uint) (bool, error) {
+	result := r.db.Where("claim_mapping_id = ?", id).Delete(&model.ClaimMapping{})
+	return result.RowsAffected > 0, result.Error
+}
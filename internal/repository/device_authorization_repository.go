@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type DeviceAuthorizationRepository interface {
+	Create(authorization model.DeviceAuthorization) (model.DeviceAuthorization, error)
+	FindByDeviceCodeHash(deviceCodeHash string) (model.DeviceAuthorization, error)
+	FindByUserCode(userCode string) (model.DeviceAuthorization, error)
+	RecordPoll(id uint, at time.Time, pollInterval int) error
+	Decide(id uint, status string, userID uint) (bool, error)
+	MarkUsed(id uint) (bool, error)
+	DeleteExpired(before time.Time) error
+}
+
+type deviceAuthorizationRepository struct {
+	db *gorm.DB
+}
+
+func NewDeviceAuthorizationRepository(db *gorm.DB) DeviceAuthorizationRepository {
+	return &deviceAuthorizationRepository{db}
+}
+
+func (r *deviceAuthorizationRepository) Create(authorization model.DeviceAuthorization) (model.DeviceAuthorization, error) {
+	result := r.db.Create(&authorization)
+	return authorization, result.Error
+}
+
+func (r *deviceAuthorizationRepository) FindByDeviceCodeHash(deviceCodeHash string) (model.DeviceAuthorization, error) {
+	var authorization model.DeviceAuthorization
+	result := r.db.Preload("OAuthClient").Where("device_code_hash = ?", deviceCodeHash).First(&authorization)
+	return authorization, result.Error
+}
+
+func (r *deviceAuthorizationRepository) FindByUserCode(userCode string) (model.DeviceAuthorization, error) {
+	var authorization model.DeviceAuthorization
+	result := r.db.Preload("OAuthClient").Where("user_code = ?", userCode).First(&authorization)
+	return authorization, result.Error
+}
+
+// RecordPoll stores the time of the device's latest poll along with its, possibly increased, poll interval
+func (r *deviceAuthorizationRepository) RecordPoll(id uint, at time.Time, pollInterval int) error {
+	return r.db.Model(&model.DeviceAuthorization{}).
+		Where("device_authorization_id = ?", id).
+		Updates(map[string]any{
+			"last_polled_at": at,
+			"poll_interval":  pollInterval,
+		}).Error
+}
+
+// Decide approves or denies a pending authorization. It reports false when it was no longer pending.
+func (r *deviceAuthorizationRepository) Decide(id uint, status string, userID uint) (bool, error) {
+	result := r.db.Model(&model.DeviceAuthorization{}).
+		Where("device_authorization_id = ? AND status = ?", id, model.DeviceAuthorizationPending).
+		Updates(map[string]any{
+			"status":  status,
+			"user_id": userID,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkUsed redeems an approved authorization. It reports false when it was already redeemed.
+func (r *deviceAuthorizationRepository) MarkUsed(id uint) (bool, error) {
+	result := r.db.Model(&model.DeviceAuthorization{}).
+		Where("device_authorization_id = ? AND status = ?", id, model.DeviceAuthorizationApproved).
+		Update("status", model.DeviceAuthorizationUsed)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *deviceAuthorizationRepository) DeleteExpired(before time.Time) error {
+	return r.db.Where("expires_at < ?", before).Delete(&model.DeviceAuthorization{}).Error
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"gorm.io/gorm"
+)
+
+type OAuthClientRepository interface {
+	FindAll() ([]model.OAuthClient, error)
+	FindByClientID(clientID string) (model.OAuthClient, error)
+	FindByName(name string) (model.OAuthClient, error)
+	Create(client model.OAuthClient) (model.OAuthClient, error)
+	Delete(id uint) (bool, error)
+}
+
+type oauthClientRepository struct {
+	db *gorm.DB
+}
+
+func NewOAuthClientRepository(db *gorm.DB) OAuthClientRepository {
+	return &oauthClientRepository{db}
+}
+
+func (r *oauthClientRepository) FindAll() ([]model.OAuthClient, error) {
+	var clients []model.OAuthClient
+	result := r.db.Order("name").Find(&clients)
+	return clients, result.Error
+}
+
+func (r *oauthClientRepository) FindByClientID(clientID string) (model.OAuthClient, error) {
+	var client model.OAuthClient
+	result := r.db.Where("client_id = ?", clientID).First(&client)
+	return client, result.Error
+}
+
+func (r *oauthClientRepository) FindByName(name string) (model.OAuthClient, error) {
+	var client model.OAuthClient
+	result := r.db.Where("name = ?", name).First(&client)
+	return client, result.Error
+}
+
+func (r *oauthClientRepository) Create(client model.OAuthClient) (model.OAuthClient, error) {
+	result := r.db.Create(&client)
+	return client, result.Error
+}
+
+func (r *oauthClientRepository) Delete(id uint) (bool, error) {
+	result := r.db.Where("oauth_client_id = ?", id).Delete(&model.OAuthClient{})
+	return result.RowsAffected > 0, result.Error
+}
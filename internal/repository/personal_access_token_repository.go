@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type PersonalAccessTokenRepository interface {
+	FindByUserID(userID uint) ([]model.PersonalAccessToken, error)
+	FindByTokenHash(tokenHash string) (model.PersonalAccessToken, error)
+	ExistsActiveName(userID uint, name string, at time.Time) (bool, error)
+	Create(token model.PersonalAccessToken) (model.PersonalAccessToken, error)
+	Revoke(id uint, userID uint, at time.Time) (bool, error)
+	Touch(id uint, at time.Time) error
+}
+
+type personalAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPersonalAccessTokenRepository(db *gorm.DB) PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{db}
+}
+
+func (r *personalAccessTokenRepository) FindByUserID(userID uint) ([]model.PersonalAccessToken, error) {
+	var tokens []model.PersonalAccessToken
+	result := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens)
+	return tokens, result.Error
+}
+
+func (r *personalAccessTokenRepository) FindByTokenHash(tokenHash string) (model.PersonalAccessToken, error) {
+	var token model.PersonalAccessToken
+	result := r.db.Where("token_hash = ?", tokenHash).First(&token)
+	return token, result.Error
+}
+
+// ExistsActiveName reports whether the user has a token with the name that is neither revoked nor expired at the given time
+func (r *personalAccessTokenRepository) ExistsActiveName(userID uint, name string, at time.Time) (bool, error) {
+	var count int64
+	result := r.db.Model(&model.PersonalAccessToken{}).
+		Where("user_id = ? AND name = ? AND revoked_at IS NULL AND expires_at > ?", userID, name, at).
+		Count(&count)
+	return count > 0, result.Error
+}
+
+func (r *personalAccessTokenRepository) Create(token model.PersonalAccessToken) (model.PersonalAccessToken, error) {
+	result := r.db.Create(&token)
+	return token, result.Error
+}
+
+// Revoke revokes one of the user's tokens. It reports false when the user has no such unrevoked token.
+func (r *personalAccessTokenRepository) Revoke(id uint, userID uint, at time.Time) (bool, error) {
+	result := r.db.Model(&model.PersonalAccessToken{}).
+		Where("personal_access_token_id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Touch records the token's last use
+func (r *personalAccessTokenRepository) Touch(id uint, at time.Time) error {
+	return r.db.Model(&model.PersonalAccessToken{}).
+		Where("personal_access_token_id = ?", id).
+		Update("last_used_at", at).Error
+}
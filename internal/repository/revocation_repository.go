@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type RevocationRepository interface {
+	Create(entry model.RevokedToken) (model.RevokedToken, error)
+	FindLive(at time.Time) ([]model.RevokedToken, error)
+	DeleteExpired(at time.Time) (int64, error)
+}
+
+type revocationRepository struct {
+	db *gorm.DB
+}
+
+func NewRevocationRepository(db *gorm.DB) RevocationRepository {
+	return &revocationRepository{db}
+}
+
+func (r *revocationRepository) Create(entry model.RevokedToken) (model.RevokedToken, error) {
+	result := r.db.Create(&entry)
+	return entry, result.Error
+}
+
+// FindLive returns the entries still covering unexpired tokens at the given time
+func (r *revocationRepository) FindLive(at time.Time) ([]model.RevokedToken, error) {
+	var entries []model.RevokedToken
+	result := r.db.Where("expires_at > ?", at).Find(&entries)
+	return entries, result.Error
+}
+
+func (r *revocationRepository) DeleteExpired(at time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", at).Delete(&model.RevokedToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RoleApproverRepository interface {
+	FindByRoleID(roleID uint) ([]model.RoleApprover, error)
+	FindRoleIDsByApprover(userID uint) ([]uint, error)
+	IsApprover(roleID uint, userID uint) (bool, error)
+	Add(roleID uint, userID uint) error
+	Remove(roleID uint, userID uint) error
+}
+
+type roleApproverRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleApproverRepository(db *gorm.DB) RoleApproverRepository {
+	return &roleApproverRepository{db}
+}
+
+func (r *roleApproverRepository) FindByRoleID(roleID uint) ([]model.RoleApprover, error) {
+	var approvers []model.RoleApprover
+	result := r.db.Preload("User").Where("role_id = ?", roleID).Order("user_id").Find(&approvers)
+	return approvers, result.Error
+}
+
+func (r *roleApproverRepository) FindRoleIDsByApprover(userID uint) ([]uint, error) {
+	var roleIDs []uint
+	result := r.db.Model(&model.RoleApprover{}).Where("user_id = ?", userID).Pluck("role_id", &roleIDs)
+	return roleIDs, result.Error
+}
+
+func (r *roleApproverRepository) IsApprover(roleID uint, userID uint) (bool, error) {
+	var count int64
+	result := r.db.Model(&model.RoleApprover{}).Where("role_id = ? AND user_id = ?", roleID, userID).Count(&count)
+	return count > 0, result.Error
+}
+
+func (r *roleApproverRepository) Add(roleID uint, userID uint) error {
+	return r.db.Omit("User").Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.RoleApprover{RoleID: roleID, UserID: userID}).Error
+}
+
+func (r *roleApproverRepository) Remove(roleID uint, userID uint) error {
+	result := r.db.Where("role_id = ? AND user_id = ?", roleID, userID).Delete(&model.RoleApprover{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -3,10 +3,13 @@ package repository
 import (
 	"auth-service/internal/model"
 	"gorm.io/gorm"
+	"time"
 )
 
 type RoleRepository interface {
 	GetPermissionsByRoleIds(ids []int) ([]model.Permission, error)
+	FindByID(id uint) (model.Role, error)
+	FindActiveByUserID(userID uint, at time.Time) ([]model.Role, error)
 }
 
 type roleRepository struct {
@@ -32,3 +35,21 @@ func (r *roleRepository) GetPermissionsByRoleIds(ids []int) ([]model.Permission,
 
 	return permissions, nil
 }
+
+func (r *roleRepository) FindByID(id uint) (model.Role, error) {
+	var role model.Role
+	result := r.db.Where("role_id = ?", id).First(&role)
+	return role, result.Error
+}
+
+// FindActiveByUserID returns the roles whose user_roles grant window contains the given time.
+func (r *roleRepository) FindActiveByUserID(userID uint, at time.Time) ([]model.Role, error) {
+	var roles []model.Role
+	result := r.db.
+		Joins("JOIN user_roles ON user_roles.role_id = roles.role_id").
+		Where("user_roles.user_id = ?", userID).
+		Where("user_roles.valid_from IS NULL OR user_roles.valid_from <= ?", at).
+		Where("user_roles.valid_until IS NULL OR user_roles.valid_until > ?", at).
+		Find(&roles)
+	return roles, result.Error
+}
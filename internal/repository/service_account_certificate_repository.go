@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"gorm.io/gorm"
+)
+
+type ServiceAccountCertificateRepository interface {
+	FindByUserID(userID uint) ([]model.ServiceAccountCertificate, error)
+	FindByMatch(matchType string, matchValue string) (model.ServiceAccountCertificate, error)
+	FindMatching(fingerprint string, subject string, uris []string) ([]model.ServiceAccountCertificate, error)
+	Create(certificate model.ServiceAccountCertificate) (model.ServiceAccountCertificate, error)
+	Delete(id uint, userID uint) (bool, error)
+}
+
+type serviceAccountCertificateRepository struct {
+	db *gorm.DB
+}
+
+func NewServiceAccountCertificateRepository(db *gorm.DB) ServiceAccountCertificateRepository {
+	return &serviceAccountCertificateRepository{db}
+}
+
+func (r *serviceAccountCertificateRepository) FindByUserID(userID uint) ([]model.ServiceAccountCertificate, error) {
+	var certificates []model.ServiceAccountCertificate
+	result := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&certificates)
+	return certificates, result.Error
+}
+
+func (r *serviceAccountCertificateRepository) FindByMatch(matchType string, matchValue string) (model.ServiceAccountCertificate, error) {
+	var certificate model.ServiceAccountCertificate
+	result := r.db.Where("match_type = ? AND match_value = ?", matchType, matchValue).First(&certificate)
+	return certificate, result.Error
+}
+
+// FindMatching returns the mappings matching a certificate by its fingerprint, subject or any of its URI SANs
+func (r *serviceAccountCertificateRepository) FindMatching(fingerprint string, subject string, uris []string) ([]model.ServiceAccountCertificate, error) {
+	var certificates []model.ServiceAccountCertificate
+	query := r.db.Where("match_type = ? AND match_value = ?", model.CertificateMatchFingerprint, fingerprint)
+	if subject != "" {
+		query = query.Or("match_type = ? AND match_value = ?", model.CertificateMatchSubject, subject)
+	}
+	if len(uris) > 0 {
+		query = query.Or("match_type = ? AND match_value IN ?", model.CertificateMatchURI, uris)
+	}
+	result := r.db.Preload("User").Where(query).Find(&certificates)
+	return certificates, result.Error
+}
+
+func (r *serviceAccountCertificateRepository) Create(certificate model.ServiceAccountCertificate) (model.ServiceAccountCertificate, error) {
+	result := r.db.Omit("User").Create(&certificate)
+	return certificate, result.Error
+}
+
+// Delete removes one of the service account's certificate mappings. It reports false when it has no such mapping.
+func (r *serviceAccountCertificateRepository) Delete(id uint, userID uint) (bool, error) {
+	result := r.db.Where("service_account_certificate_id = ? AND user_id = ?", id, userID).Delete(&model.ServiceAccountCertificate{})
+	return result.RowsAffected > 0, result.Error
+}
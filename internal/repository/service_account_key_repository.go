@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type ServiceAccountKeyRepository interface {
+	FindByUserID(userID uint) ([]model.ServiceAccountKey, error)
+	FindByKeyID(keyID string) (model.ServiceAccountKey, error)
+	Create(key model.ServiceAccountKey) (model.ServiceAccountKey, error)
+	Revoke(id uint, userID uint, at time.Time) (bool, error)
+	Touch(id uint, at time.Time) error
+}
+
+type serviceAccountKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewServiceAccountKeyRepository(db *gorm.DB) ServiceAccountKeyRepository {
+	return &serviceAccountKeyRepository{db}
+}
+
+func (r *serviceAccountKeyRepository) FindByUserID(userID uint) ([]model.ServiceAccountKey, error) {
+	var keys []model.ServiceAccountKey
+	result := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&keys)
+	return keys, result.Error
+}
+
+func (r *serviceAccountKeyRepository) FindByKeyID(keyID string) (model.ServiceAccountKey, error) {
+	var key model.ServiceAccountKey
+	result := r.db.Preload("User").Where("key_id = ?", keyID).First(&key)
+	return key, result.Error
+}
+
+func (r *serviceAccountKeyRepository) Create(key model.ServiceAccountKey) (model.ServiceAccountKey, error) {
+	result := r.db.Omit("User").Create(&key)
+	return key, result.Error
+}
+
+// Revoke revokes one of the service account's keys. It reports false when it has no such unrevoked key.
+func (r *serviceAccountKeyRepository) Revoke(id uint, userID uint, at time.Time) (bool, error) {
+	result := r.db.Model(&model.ServiceAccountKey{}).
+		Where("service_account_key_id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Touch records the key's last use
+func (r *serviceAccountKeyRepository) Touch(id uint, at time.Time) error {
+	return r.db.Model(&model.ServiceAccountKey{}).
+		Where("service_account_key_id = ?", id).
+		Update("last_used_at", at).Error
+}
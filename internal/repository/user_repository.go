@@ -3,12 +3,22 @@ package repository
 import (
 	"auth-service/internal/model"
 	"gorm.io/gorm"
+	"time"
 )
 
 type UserRepository interface {
 	FindAll() ([]model.User, error)
 	Create(user model.User) (model.User, error)
 	FindByEmail(email string) (model.User, error)
+	FindByID(id uint) (model.User, error)
+	FindByAccountType(accountType string) ([]model.User, error)
+	Delete(id uint) (bool, error)
+	FindExpiredBreakGlass(at time.Time) ([]model.User, error)
+	SetEnabledUntil(id uint, enabledUntil *time.Time) error
+	UpdatePassword(id uint, hashedPassword string) error
+	UpdateAttributes(id uint, attributes model.Attributes) error
+	GetTokenGeneration(email string) (int, error)
+	IncrementTokenGeneration(id uint) (int, error)
 }
 
 type userRepository struct {
@@ -31,7 +41,70 @@ func (r *userRepository) FindByEmail(email string) (model.User, error) {
 	return user, result.Error
 }
 
+func (r *userRepository) FindByID(id uint) (model.User, error) {
+	var user model.User
+	result := r.db.Preload("Roles").Where("id = ?", id).First(&user)
+	return user, result.Error
+}
+
 func (r *userRepository) Create(user model.User) (model.User, error) {
 	result := r.db.Create(&user)
 	return user, result.Error
 }
+
+func (r *userRepository) FindByAccountType(accountType string) ([]model.User, error) {
+	var users []model.User
+	result := r.db.Preload("Roles").Where("account_type = ?", accountType).Order("first_name").Find(&users)
+	return users, result.Error
+}
+
+func (r *userRepository) Delete(id uint) (bool, error) {
+	result := r.db.Where("id = ?", id).Delete(&model.User{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindExpiredBreakGlass returns break-glass accounts whose activation window has closed but that are still marked enabled
+func (r *userRepository) FindExpiredBreakGlass(at time.Time) ([]model.User, error) {
+	var users []model.User
+	result := r.db.Where("account_type = ? AND enabled_until IS NOT NULL AND enabled_until <= ?", model.AccountTypeBreakGlass, at).Find(&users)
+	return users, result.Error
+}
+
+func (r *userRepository) SetEnabledUntil(id uint, enabledUntil *time.Time) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).Updates(map[string]any{
+		"enabled_until": enabledUntil,
+		"updated_at":    time.Now(),
+	}).Error
+}
+
+func (r *userRepository) UpdatePassword(id uint, hashedPassword string) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).Updates(map[string]any{
+		"password":   hashedPassword,
+		"updated_at": time.Now(),
+	}).Error
+}
+
+func (r *userRepository) UpdateAttributes(id uint, attributes model.Attributes) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).Updates(map[string]any{
+		"attributes": attributes,
+		"updated_at": time.Now(),
+	}).Error
+}
+
+func (r *userRepository) GetTokenGeneration(email string) (int, error) {
+	var generation int
+	result := r.db.Model(&model.User{}).Select("token_generation").Where("email = ?", email).Scan(&generation)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return generation, result.Error
+}
+
+func (r *userRepository) IncrementTokenGeneration(id uint) (int, error) {
+	var generation int
+	result := r.db.Raw("UPDATE users SET token_generation = token_generation + 1 WHERE id = ? RETURNING token_generation", id).Scan(&generation)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return generation, result.Error
+}
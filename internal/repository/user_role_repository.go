@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type UserRoleRepository interface {
+	Upsert(userRole model.UserRole) (model.UserRole, error)
+	Find(userID uint, roleID uint) (model.UserRole, error)
+	FindByUserID(userID uint) ([]model.UserRole, error)
+	FindAll(roleIDs []uint) ([]model.UserRole, error)
+	FindPendingActivation(at time.Time) ([]model.UserRole, error)
+	FindExpired(at time.Time) ([]model.UserRole, error)
+	MarkActivated(userID uint, roleID uint, at time.Time) (bool, error)
+	Delete(userID uint, roleID uint) error
+}
+
+type userRoleRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRoleRepository(db *gorm.DB) UserRoleRepository {
+	return &userRoleRepository{db}
+}
+
+// Upsert creates the assignment, or replaces the grant window of an existing one.
+func (r *userRoleRepository) Upsert(userRole model.UserRole) (model.UserRole, error) {
+	result := r.db.Omit("User", "Role").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "role_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"valid_from", "valid_until", "activated_at", "granted_by"}),
+	}).Create(&userRole)
+	return userRole, result.Error
+}
+
+func (r *userRoleRepository) Find(userID uint, roleID uint) (model.UserRole, error) {
+	var userRole model.UserRole
+	result := r.db.Preload("Role").Where("user_id = ? AND role_id = ?", userID, roleID).First(&userRole)
+	return userRole, result.Error
+}
+
+func (r *userRoleRepository) FindByUserID(userID uint) ([]model.UserRole, error) {
+	var userRoles []model.UserRole
+	result := r.db.Preload("Role").Where("user_id = ?", userID).Order("role_id").Find(&userRoles)
+	return userRoles, result.Error
+}
+
+// FindAll returns every assignment, limited to the given roles when roleIDs is not empty
+func (r *userRoleRepository) FindAll(roleIDs []uint) ([]model.UserRole, error) {
+	var userRoles []model.UserRole
+	query := r.db.Preload("User").Preload("Role")
+	if len(roleIDs) > 0 {
+		query = query.Where("role_id IN (?)", roleIDs)
+	}
+	result := query.Order("user_id, role_id").Find(&userRoles)
+	return userRoles, result.Error
+}
+
+// FindPendingActivation returns grants whose window has opened but that were not yet marked activated.
+func (r *userRoleRepository) FindPendingActivation(at time.Time) ([]model.UserRole, error) {
+	var userRoles []model.UserRole
+	result := r.db.Preload("Role").
+		Where("activated_at IS NULL").
+		Where("valid_from IS NULL OR valid_from <= ?", at).
+		Where("valid_until IS NULL OR valid_until > ?", at).
+		Find(&userRoles)
+	return userRoles, result.Error
+}
+
+func (r *userRoleRepository) FindExpired(at time.Time) ([]model.UserRole, error) {
+	var userRoles []model.UserRole
+	result := r.db.Preload("Role").Where("valid_until IS NOT NULL AND valid_until <= ?", at).Find(&userRoles)
+	return userRoles, result.Error
+}
+
+// MarkActivated claims the activation of a grant. It reports false when another instance already claimed it.
+func (r *userRoleRepository) MarkActivated(userID uint, roleID uint, at time.Time) (bool, error) {
+	result := r.db.Model(&model.UserRole{}).
+		Where("user_id = ? AND role_id = ? AND activated_at IS NULL", userID, roleID).
+		Update("activated_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *userRoleRepository) Delete(userID uint, roleID uint) error {
+	result := r.db.Where("user_id = ? AND role_id = ?", userID, roleID).Delete(&model.UserRole{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
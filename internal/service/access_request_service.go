@@ -0,0 +1,385 @@
+package service
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/request"
+	"auth-service/internal/repository"
+	"auth-service/pkg/utils/exception"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type AccessRequestService interface {
+	CreateRequest(c *gin.Context, actorEmail string, req requestDTO.CreateAccessRequestRequest) (model.AccessRequest, error)
+	ListMine(c *gin.Context, actorEmail string) ([]model.AccessRequest, error)
+	ListForApprover(c *gin.Context, actorEmail string, status string) ([]model.AccessRequest, error)
+	Get(c *gin.Context, actorEmail string, id uint) (model.AccessRequest, error)
+	Approve(c *gin.Context, actorEmail string, id uint, req requestDTO.DecideAccessRequestRequest) (model.AccessRequest, error)
+	Reject(c *gin.Context, actorEmail string, id uint, req requestDTO.DecideAccessRequestRequest) (model.AccessRequest, error)
+	Cancel(c *gin.Context, actorEmail string, id uint) (model.AccessRequest, error)
+
+	ListApprovers(c *gin.Context, roleID uint) ([]model.RoleApprover, error)
+	AddApprover(c *gin.Context, actorEmail string, roleID uint, userID uint) error
+	RemoveApprover(c *gin.Context, actorEmail string, roleID uint, userID uint) error
+}
+
+type accessRequestService struct {
+	userRepo          repository.UserRepository
+	roleRepo          repository.RoleRepository
+	accessRequestRepo repository.AccessRequestRepository
+	roleApproverRepo  repository.RoleApproverRepository
+	roleGrantService  RoleGrantService
+	auditService      AuditService
+}
+
+func NewAccessRequestService(userRepo repository.UserRepository, roleRepo repository.RoleRepository, accessRequestRepo repository.AccessRequestRepository, roleApproverRepo repository.RoleApproverRepository, roleGrantService RoleGrantService, auditService AuditService) AccessRequestService {
+	return &accessRequestService{userRepo, roleRepo, accessRequestRepo, roleApproverRepo, roleGrantService, auditService}
+}
+
+func (s *accessRequestService) CreateRequest(c *gin.Context, actorEmail string, req requestDTO.CreateAccessRequestRequest) (model.AccessRequest, error) {
+	actor, err := s.findActor(actorEmail)
+	if err != nil {
+		return model.AccessRequest{}, err
+	}
+
+	role, err := s.roleRepo.FindByID(req.RoleID)
+	if err != nil {
+		return model.AccessRequest{}, exception.NewNotFound("Role not found")
+	}
+
+	durationSeconds, err := parseDurationSeconds(req.Duration)
+	if err != nil {
+		return model.AccessRequest{}, err
+	}
+
+	// Approving a request can never shorten a grant, so there is nothing to request on top of a permanent one
+	grants, err := s.roleGrantService.ListGrants(c, actor.ID)
+	if err != nil {
+		return model.AccessRequest{}, err
+	}
+	now := time.Now()
+	for _, grant := range grants {
+		if grant.RoleID == role.RoleID && grant.ValidUntil == nil && grant.IsActiveAt(now) {
+			return model.AccessRequest{}, exception.NewConflictBusinessException("You already hold this role permanently")
+		}
+	}
+
+	pending, err := s.accessRequestRepo.ExistsPending(actor.ID, role.RoleID)
+	if err != nil {
+		return model.AccessRequest{}, exception.ErrInternal
+	}
+	if pending {
+		return model.AccessRequest{}, exception.NewConflictBusinessException("A pending request for this role already exists")
+	}
+
+	accessRequest, err := s.accessRequestRepo.Create(model.AccessRequest{
+		UserID:          actor.ID,
+		RoleID:          role.RoleID,
+		Justification:   strings.TrimSpace(req.Justification),
+		DurationSeconds: durationSeconds,
+		Status:          model.AccessRequestPending,
+	})
+	if err != nil {
+		return model.AccessRequest{}, exception.NewInternal("Failed to save access request")
+	}
+
+	s.recordTransition(c, accessRequest, model.AccessRequestPending, actor.ID, accessRequest.Justification, AuditAccessRequested)
+
+	return s.accessRequestRepo.FindByID(accessRequest.AccessRequestID)
+}
+
+func (s *accessRequestService) ListMine(c *gin.Context, actorEmail string) ([]model.AccessRequest, error) {
+	actor, err := s.findActor(actorEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	accessRequests, err := s.accessRequestRepo.FindByUserID(actor.ID)
+	if err != nil {
+		return nil, exception.ErrInternal
+	}
+	return accessRequests, nil
+}
+
+// ListForApprover lists requests for the roles the actor is a designated approver of
+func (s *accessRequestService) ListForApprover(c *gin.Context, actorEmail string, status string) ([]model.AccessRequest, error) {
+	actor, err := s.findActor(actorEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	status = strings.ToUpper(strings.TrimSpace(status))
+	switch status {
+	case "", model.AccessRequestPending, model.AccessRequestApproved, model.AccessRequestRejected, model.AccessRequestCancelled:
+	default:
+		return nil, exception.NewBadRequest("Invalid status filter")
+	}
+
+	roleIDs, err := s.roleApproverRepo.FindRoleIDsByApprover(actor.ID)
+	if err != nil {
+		return nil, exception.ErrInternal
+	}
+
+	accessRequests, err := s.accessRequestRepo.FindByRoleIDs(roleIDs, status)
+	if err != nil {
+		return nil, exception.ErrInternal
+	}
+	return accessRequests, nil
+}
+
+func (s *accessRequestService) Get(c *gin.Context, actorEmail string, id uint) (model.AccessRequest, error) {
+	actor, err := s.findActor(actorEmail)
+	if err != nil {
+		return model.AccessRequest{}, err
+	}
+
+	accessRequest, err := s.findRequest(id)
+	if err != nil {
+		return model.AccessRequest{}, err
+	}
+
+	if accessRequest.UserID != actor.ID {
+		isApprover, err := s.roleApproverRepo.IsApprover(accessRequest.RoleID, actor.ID)
+		if err != nil {
+			return model.AccessRequest{}, exception.ErrInternal
+		}
+		if !isApprover {
+			return model.AccessRequest{}, exception.NewNotFound("Access request not found")
+		}
+	}
+
+	return accessRequest, nil
+}
+
+func (s *accessRequestService) Approve(c *gin.Context, actorEmail string, id uint, req requestDTO.DecideAccessRequestRequest) (model.AccessRequest, error) {
+	actor, accessRequest, err := s.findDecidable(actorEmail, id)
+	if err != nil {
+		return model.AccessRequest{}, err
+	}
+
+	// Validate the grant before claiming the decision so that a bad duration leaves the request pending
+	duration := req.Duration
+	if duration == "" && accessRequest.DurationSeconds != nil {
+		duration = (time.Duration(*accessRequest.DurationSeconds) * time.Second).String()
+	}
+	if _, err := parseDurationSeconds(duration); err != nil {
+		return model.AccessRequest{}, err
+	}
+
+	if err := s.decide(c, accessRequest, model.AccessRequestApproved, actor.ID, req.Note, AuditAccessApproved); err != nil {
+		return model.AccessRequest{}, err
+	}
+
+	_, err = s.roleGrantService.GrantRole(c, actor.Email, requestDTO.GrantRoleRequest{
+		UserID:   accessRequest.UserID,
+		RoleID:   accessRequest.RoleID,
+		Duration: duration,
+		Reason:   "access request #" + strconv.FormatUint(uint64(accessRequest.AccessRequestID), 10),
+	})
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "access request approved but role grant failed, reopening it",
+			"accessRequestId", accessRequest.AccessRequestID,
+			"error", err,
+		)
+		// Put the request back so that the approval can be retried
+		if reopened, reopenErr := s.accessRequestRepo.Reopen(accessRequest.AccessRequestID, time.Now()); reopenErr != nil || !reopened {
+			slog.ErrorContext(c.Request.Context(), "failed to reopen access request",
+				"accessRequestId", accessRequest.AccessRequestID,
+				"error", reopenErr,
+			)
+		} else {
+			s.recordTransition(c, accessRequest, model.AccessRequestPending, actor.ID, "role grant failed", AuditAccessReopened)
+		}
+		return model.AccessRequest{}, err
+	}
+
+	return s.accessRequestRepo.FindByID(accessRequest.AccessRequestID)
+}
+
+func (s *accessRequestService) Reject(c *gin.Context, actorEmail string, id uint, req requestDTO.DecideAccessRequestRequest) (model.AccessRequest, error) {
+	actor, accessRequest, err := s.findDecidable(actorEmail, id)
+	if err != nil {
+		return model.AccessRequest{}, err
+	}
+
+	if err := s.decide(c, accessRequest, model.AccessRequestRejected, actor.ID, req.Note, AuditAccessRejected); err != nil {
+		return model.AccessRequest{}, err
+	}
+
+	return s.accessRequestRepo.FindByID(accessRequest.AccessRequestID)
+}
+
+func (s *accessRequestService) Cancel(c *gin.Context, actorEmail string, id uint) (model.AccessRequest, error) {
+	actor, err := s.findActor(actorEmail)
+	if err != nil {
+		return model.AccessRequest{}, err
+	}
+
+	accessRequest, err := s.findRequest(id)
+	if err != nil {
+		return model.AccessRequest{}, err
+	}
+	if accessRequest.UserID != actor.ID {
+		return model.AccessRequest{}, exception.NewNotFound("Access request not found")
+	}
+
+	if err := s.decide(c, accessRequest, model.AccessRequestCancelled, actor.ID, "", AuditAccessCancelled); err != nil {
+		return model.AccessRequest{}, err
+	}
+
+	return s.accessRequestRepo.FindByID(accessRequest.AccessRequestID)
+}
+
+func (s *accessRequestService) ListApprovers(c *gin.Context, roleID uint) ([]model.RoleApprover, error) {
+	if _, err := s.roleRepo.FindByID(roleID); err != nil {
+		return nil, exception.NewNotFound("Role not found")
+	}
+
+	approvers, err := s.roleApproverRepo.FindByRoleID(roleID)
+	if err != nil {
+		return nil, exception.ErrInternal
+	}
+	return approvers, nil
+}
+
+func (s *accessRequestService) AddApprover(c *gin.Context, actorEmail string, roleID uint, userID uint) error {
+	actor, err := s.findActor(actorEmail)
+	if err != nil {
+		return err
+	}
+	if _, err := s.roleRepo.FindByID(roleID); err != nil {
+		return exception.NewNotFound("Role not found")
+	}
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return exception.NewNotFound("User not found")
+	}
+
+	if err := s.roleApproverRepo.Add(roleID, userID); err != nil {
+		return exception.NewInternal("Failed to save role approver")
+	}
+
+	s.auditService.Record(c.Request.Context(), AuditRoleApproverAdded, &actor.ID, AuditTargetRole, strconv.FormatUint(uint64(roleID), 10), map[string]any{
+		"approver_id": userID,
+	})
+	return nil
+}
+
+func (s *accessRequestService) RemoveApprover(c *gin.Context, actorEmail string, roleID uint, userID uint) error {
+	actor, err := s.findActor(actorEmail)
+	if err != nil {
+		return err
+	}
+
+	if err := s.roleApproverRepo.Remove(roleID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exception.NewNotFound("Role approver not found")
+		}
+		return exception.NewInternal("Failed to remove role approver")
+	}
+
+	s.auditService.Record(c.Request.Context(), AuditRoleApproverRemoved, &actor.ID, AuditTargetRole, strconv.FormatUint(uint64(roleID), 10), map[string]any{
+		"approver_id": userID,
+	})
+	return nil
+}
+
+func (s *accessRequestService) findActor(actorEmail string) (model.User, error) {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return model.User{}, exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+	return actor, nil
+}
+
+func (s *accessRequestService) findRequest(id uint) (model.AccessRequest, error) {
+	accessRequest, err := s.accessRequestRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.AccessRequest{}, exception.NewNotFound("Access request not found")
+		}
+		return model.AccessRequest{}, exception.ErrInternal
+	}
+	return accessRequest, nil
+}
+
+// findDecidable loads a request the actor may approve or reject: a designated approver of the role, never the requester.
+func (s *accessRequestService) findDecidable(actorEmail string, id uint) (model.User, model.AccessRequest, error) {
+	actor, err := s.findActor(actorEmail)
+	if err != nil {
+		return model.User{}, model.AccessRequest{}, err
+	}
+
+	accessRequest, err := s.findRequest(id)
+	if err != nil {
+		return model.User{}, model.AccessRequest{}, err
+	}
+
+	isApprover, err := s.roleApproverRepo.IsApprover(accessRequest.RoleID, actor.ID)
+	if err != nil {
+		return model.User{}, model.AccessRequest{}, exception.ErrInternal
+	}
+	if !isApprover {
+		return model.User{}, model.AccessRequest{}, exception.NewUnauthorizedBusinessException("User is not an approver for this role")
+	}
+	if accessRequest.UserID == actor.ID {
+		return model.User{}, model.AccessRequest{}, exception.NewConflictBusinessException("Approvers cannot decide their own requests")
+	}
+
+	return actor, accessRequest, nil
+}
+
+func (s *accessRequestService) decide(c *gin.Context, accessRequest model.AccessRequest, status string, actorID uint, note string, auditAction string) error {
+	if accessRequest.Status != model.AccessRequestPending {
+		return exception.NewConflictBusinessException("Access request is already " + strings.ToLower(accessRequest.Status))
+	}
+
+	decided, err := s.accessRequestRepo.Decide(accessRequest.AccessRequestID, status, actorID, note, time.Now())
+	if err != nil {
+		return exception.ErrInternal
+	}
+	if !decided {
+		return exception.NewConflictBusinessException("Access request was decided concurrently")
+	}
+
+	s.recordTransition(c, accessRequest, status, actorID, note, auditAction)
+	return nil
+}
+
+func (s *accessRequestService) recordTransition(c *gin.Context, accessRequest model.AccessRequest, status string, actorID uint, note string, auditAction string) {
+	event := model.AccessRequestEvent{
+		AccessRequestID: accessRequest.AccessRequestID,
+		Status:          status,
+		ActorID:         &actorID,
+		Note:            note,
+	}
+	if err := s.accessRequestRepo.CreateEvent(event); err != nil {
+		slog.ErrorContext(c.Request.Context(), "failed to record access request event",
+			"accessRequestId", accessRequest.AccessRequestID,
+			"error", err,
+		)
+	}
+
+	s.auditService.Record(c.Request.Context(), auditAction, &actorID, AuditTargetAccessRequest, strconv.FormatUint(uint64(accessRequest.AccessRequestID), 10), map[string]any{
+		"user_id": accessRequest.UserID,
+		"role_id": accessRequest.RoleID,
+		"status":  status,
+	})
+}
+
+func parseDurationSeconds(duration string) (*int, error) {
+	if duration == "" {
+		return nil, nil
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil || d < time.Second {
+		return nil, exception.NewBadRequest("Invalid duration")
+	}
+	seconds := int(d / time.Second)
+	return &seconds, nil
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/request"
+	"auth-service/internal/repository"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fakeAccessRequestRepository keeps requests in a map
+type fakeAccessRequestRepository struct {
+	repository.AccessRequestRepository
+	requests map[uint]model.AccessRequest
+}
+
+func (r *fakeAccessRequestRepository) Create(accessRequest model.AccessRequest) (model.AccessRequest, error) {
+	accessRequest.AccessRequestID = uint(len(r.requests) + 1)
+	r.requests[accessRequest.AccessRequestID] = accessRequest
+	return accessRequest, nil
+}
+
+func (r *fakeAccessRequestRepository) FindByID(id uint) (model.AccessRequest, error) {
+	accessRequest, ok := r.requests[id]
+	if !ok {
+		return model.AccessRequest{}, gorm.ErrRecordNotFound
+	}
+	return accessRequest, nil
+}
+
+func (r *fakeAccessRequestRepository) ExistsPending(userID uint, roleID uint) (bool, error) {
+	for _, accessRequest := range r.requests {
+		if accessRequest.UserID == userID && accessRequest.RoleID == roleID && accessRequest.Status == model.AccessRequestPending {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeAccessRequestRepository) Decide(id uint, status string, actorID uint, note string, at time.Time) (bool, error) {
+	accessRequest, ok := r.requests[id]
+	if !ok || accessRequest.Status != model.AccessRequestPending {
+		return false, nil
+	}
+	accessRequest.Status = status
+	r.requests[id] = accessRequest
+	return true, nil
+}
+
+func (r *fakeAccessRequestRepository) Reopen(id uint, at time.Time) (bool, error) {
+	accessRequest, ok := r.requests[id]
+	if !ok || accessRequest.Status != model.AccessRequestApproved {
+		return false, nil
+	}
+	accessRequest.Status = model.AccessRequestPending
+	r.requests[id] = accessRequest
+	return true, nil
+}
+
+func (r *fakeAccessRequestRepository) CreateEvent(event model.AccessRequestEvent) error {
+	return nil
+}
+
+// fakeRoleApproverRepository approves every role with the configured approvers
+type fakeRoleApproverRepository struct {
+	repository.RoleApproverRepository
+	approverIDs []uint
+}
+
+func (r *fakeRoleApproverRepository) IsApprover(roleID uint, userID uint) (bool, error) {
+	for _, approverID := range r.approverIDs {
+		if approverID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// newTestAccessRequestService wires the service to a role grant service over the given grants, for the requester
+// ada@example.com (1) and the approver admin@example.com (9) of the AUDITOR role (5)
+func newTestAccessRequestService(grants *fakeUserRoleRepository, requests *fakeAccessRequestRepository) AccessRequestService {
+	users := &fakeUserRepository{users: []model.User{{ID: 1, Email: "ada@example.com"}, {ID: 9, Email: "admin@example.com"}}}
+	roles := &fakeRoleRepository{roles: map[uint][]model.Role{1: {{RoleID: 5, Name: "AUDITOR"}}}}
+	audit := &fakeAuditService{}
+	roleGrants := NewRoleGrantService(users, roles, grants, audit, &fakeTokenRevoker{})
+	return NewAccessRequestService(users, roles, requests, &fakeRoleApproverRepository{approverIDs: []uint{9}}, roleGrants, audit)
+}
+
+func TestAccessRequestServiceCreateRequest(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		grant    *model.UserRole
+		wantCode string
+	}{
+		{"no grant", nil, ""},
+		{"active permanent grant", &model.UserRole{UserID: 1, RoleID: 5, ActivatedAt: at(now.Add(-time.Hour))}, "CONFLICT"},
+		{"active time-bound grant", &model.UserRole{UserID: 1, RoleID: 5, ValidUntil: at(now.Add(time.Hour)), ActivatedAt: at(now.Add(-time.Hour))}, ""},
+		{"scheduled permanent grant", &model.UserRole{UserID: 1, RoleID: 5, ValidFrom: at(now.Add(24 * time.Hour))}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grants := newFakeUserRoleRepository()
+			if tt.grant != nil {
+				grants = newFakeUserRoleRepository(*tt.grant)
+			}
+			requests := &fakeAccessRequestRepository{requests: map[uint]model.AccessRequest{}}
+			s := newTestAccessRequestService(grants, requests)
+
+			_, err := s.CreateRequest(newTestContext(), "ada@example.com", requestDTO.CreateAccessRequestRequest{RoleID: 5, Justification: "quarterly audit", Duration: "8h"})
+			if code := errorCode(err); code != tt.wantCode {
+				t.Fatalf("CreateRequest() code = %q, want %q", code, tt.wantCode)
+			}
+			wantRequests := 1
+			if tt.wantCode != "" {
+				wantRequests = 0
+			}
+			if len(requests.requests) != wantRequests {
+				t.Errorf("%d requests saved, want %d", len(requests.requests), wantRequests)
+			}
+		})
+	}
+}
+
+func TestAccessRequestServiceApproveNeverDowngrades(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		grant      model.UserRole
+		wantCode   string
+		wantStatus string
+		wantUntil  *time.Time
+	}{
+		{
+			name:       "permanent grant kept and request reopened",
+			grant:      model.UserRole{UserID: 1, RoleID: 5, ActivatedAt: at(now.Add(-time.Hour))},
+			wantCode:   "CONFLICT",
+			wantStatus: model.AccessRequestPending,
+		},
+		{
+			name:       "longer grant kept and request reopened",
+			grant:      model.UserRole{UserID: 1, RoleID: 5, ValidUntil: at(now.Add(48 * time.Hour)), ActivatedAt: at(now.Add(-time.Hour))},
+			wantCode:   "CONFLICT",
+			wantStatus: model.AccessRequestPending,
+			wantUntil:  at(now.Add(48 * time.Hour)),
+		},
+		{
+			name:       "shorter grant extended",
+			grant:      model.UserRole{UserID: 1, RoleID: 5, ValidUntil: at(now.Add(time.Hour)), ActivatedAt: at(now.Add(-time.Hour))},
+			wantStatus: model.AccessRequestApproved,
+			wantUntil:  at(now.Add(8 * time.Hour)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grants := newFakeUserRoleRepository(tt.grant)
+			durationSeconds := int((8 * time.Hour).Seconds())
+			requests := &fakeAccessRequestRepository{requests: map[uint]model.AccessRequest{
+				1: {AccessRequestID: 1, UserID: 1, RoleID: 5, DurationSeconds: &durationSeconds, Status: model.AccessRequestPending},
+			}}
+			s := newTestAccessRequestService(grants, requests)
+
+			_, err := s.Approve(newTestContext(), "admin@example.com", 1, requestDTO.DecideAccessRequestRequest{})
+			if code := errorCode(err); code != tt.wantCode {
+				t.Fatalf("Approve() code = %q, want %q", code, tt.wantCode)
+			}
+			if status := requests.requests[1].Status; status != tt.wantStatus {
+				t.Errorf("request status = %s, want %s", status, tt.wantStatus)
+			}
+
+			until := grants.grants[[2]uint{1, 5}].ValidUntil
+			if tt.wantUntil == nil {
+				if until != nil {
+					t.Errorf("valid_until = %v, want the grant to stay permanent", until)
+				}
+				return
+			}
+			if until == nil || until.Sub(*tt.wantUntil).Abs() > time.Second {
+				t.Errorf("valid_until = %v, want %v", until, tt.wantUntil)
+			}
+		})
+	}
+}
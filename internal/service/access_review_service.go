@@ -0,0 +1,276 @@
+package service
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/request"
+	"auth-service/internal/repository"
+	"auth-service/pkg/utils/exception"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type AccessReviewService interface {
+	StartCampaign(c *gin.Context, actorEmail string, req requestDTO.StartAccessReviewRequest) (model.AccessReviewCampaign, error)
+	ListCampaigns(c *gin.Context) ([]model.AccessReviewCampaign, error)
+	GetCampaign(c *gin.Context, id uint) (model.AccessReviewCampaign, error)
+	ListAssigned(c *gin.Context, actorEmail string) ([]model.AccessReviewItem, error)
+	DecideItem(c *gin.Context, actorEmail string, itemID uint, req requestDTO.AccessReviewDecisionRequest) (model.AccessReviewItem, error)
+	CloseCampaign(c *gin.Context, actorEmail string, id uint) (model.AccessReviewCampaign, error)
+}
+
+type accessReviewService struct {
+	userRepo         repository.UserRepository
+	userRoleRepo     repository.UserRoleRepository
+	roleApproverRepo repository.RoleApproverRepository
+	accessReviewRepo repository.AccessReviewRepository
+	roleGrantService RoleGrantService
+	auditService     AuditService
+}
+
+func NewAccessReviewService(userRepo repository.UserRepository, userRoleRepo repository.UserRoleRepository, roleApproverRepo repository.RoleApproverRepository, accessReviewRepo repository.AccessReviewRepository, roleGrantService RoleGrantService, auditService AuditService) AccessReviewService {
+	return &accessReviewService{userRepo, userRoleRepo, roleApproverRepo, accessReviewRepo, roleGrantService, auditService}
+}
+
+func (s *accessReviewService) StartCampaign(c *gin.Context, actorEmail string, req requestDTO.StartAccessReviewRequest) (model.AccessReviewCampaign, error) {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return model.AccessReviewCampaign{}, exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	defaultReviewerID := actor.ID
+	if req.DefaultReviewerID != nil {
+		if _, err := s.userRepo.FindByID(*req.DefaultReviewerID); err != nil {
+			return model.AccessReviewCampaign{}, exception.NewNotFound("Default reviewer not found")
+		}
+		defaultReviewerID = *req.DefaultReviewerID
+	}
+
+	// Snapshot the current assignments
+	assignments, err := s.userRoleRepo.FindAll(req.RoleIDs)
+	if err != nil {
+		return model.AccessReviewCampaign{}, exception.ErrInternal
+	}
+	if len(assignments) == 0 {
+		return model.AccessReviewCampaign{}, exception.NewBadRequest("There are no role assignments to review")
+	}
+
+	approversByRole := map[uint][]uint{}
+	items := make([]model.AccessReviewItem, 0, len(assignments))
+	for _, assignment := range assignments {
+		approverIDs, ok := approversByRole[assignment.RoleID]
+		if !ok {
+			approvers, err := s.roleApproverRepo.FindByRoleID(assignment.RoleID)
+			if err != nil {
+				return model.AccessReviewCampaign{}, exception.ErrInternal
+			}
+			for _, approver := range approvers {
+				approverIDs = append(approverIDs, approver.UserID)
+			}
+			approversByRole[assignment.RoleID] = approverIDs
+		}
+
+		reviewerID, ok := pickReviewer(assignment.UserID, approverIDs, defaultReviewerID)
+		if !ok {
+			return model.AccessReviewCampaign{}, exception.NewBadRequest(fmt.Sprintf(
+				"No reviewer other than %s for role %s; add an approver to the role or choose another default reviewer",
+				assignment.User.Email, assignment.Role.Name))
+		}
+		items = append(items, model.AccessReviewItem{
+			UserID:     assignment.UserID,
+			UserEmail:  assignment.User.Email,
+			RoleID:     assignment.RoleID,
+			RoleName:   assignment.Role.Name,
+			ValidFrom:  assignment.ValidFrom,
+			ValidUntil: assignment.ValidUntil,
+			ReviewerID: &reviewerID,
+			Decision:   model.AccessReviewPending,
+		})
+	}
+
+	campaign, err := s.accessReviewRepo.CreateCampaign(model.AccessReviewCampaign{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		Status:      model.AccessReviewOpen,
+		DueAt:       req.DueAt,
+		CreatedBy:   &actor.ID,
+		Items:       items,
+	})
+	if err != nil {
+		return model.AccessReviewCampaign{}, exception.NewInternal("Failed to save access review campaign")
+	}
+
+	s.auditService.Record(c.Request.Context(), AuditAccessReviewStarted, &actor.ID, AuditTargetAccessReview, strconv.FormatUint(uint64(campaign.CampaignID), 10), map[string]any{
+		"name":  campaign.Name,
+		"items": len(items),
+	})
+
+	return s.GetCampaign(c, campaign.CampaignID)
+}
+
+func (s *accessReviewService) ListCampaigns(c *gin.Context) ([]model.AccessReviewCampaign, error) {
+	campaigns, err := s.accessReviewRepo.FindCampaigns()
+	if err != nil {
+		return nil, exception.ErrInternal
+	}
+	return campaigns, nil
+}
+
+func (s *accessReviewService) GetCampaign(c *gin.Context, id uint) (model.AccessReviewCampaign, error) {
+	campaign, err := s.accessReviewRepo.FindCampaignByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.AccessReviewCampaign{}, exception.NewNotFound("Access review campaign not found")
+		}
+		return model.AccessReviewCampaign{}, exception.ErrInternal
+	}
+	return campaign, nil
+}
+
+func (s *accessReviewService) ListAssigned(c *gin.Context, actorEmail string) ([]model.AccessReviewItem, error) {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return nil, exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	items, err := s.accessReviewRepo.FindOpenItemsByReviewer(actor.ID)
+	if err != nil {
+		return nil, exception.ErrInternal
+	}
+	return items, nil
+}
+
+func (s *accessReviewService) DecideItem(c *gin.Context, actorEmail string, itemID uint, req requestDTO.AccessReviewDecisionRequest) (model.AccessReviewItem, error) {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return model.AccessReviewItem{}, exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	item, err := s.accessReviewRepo.FindItemByID(itemID)
+	if err != nil {
+		return model.AccessReviewItem{}, exception.NewNotFound("Access review item not found")
+	}
+	if item.ReviewerID == nil || *item.ReviewerID != actor.ID {
+		return model.AccessReviewItem{}, exception.NewNotFound("Access review item not found")
+	}
+	if item.UserID == actor.ID {
+		return model.AccessReviewItem{}, exception.New(http.StatusForbidden, "SELF_REVIEW_FORBIDDEN", "You cannot review your own access")
+	}
+	if item.AppliedAt != nil {
+		return model.AccessReviewItem{}, exception.NewConflictBusinessException("Access review item was already applied")
+	}
+	if item.Campaign == nil || item.Campaign.Status != model.AccessReviewOpen {
+		return model.AccessReviewItem{}, exception.NewConflictBusinessException("Access review campaign is closed")
+	}
+
+	now := time.Now()
+	if err := s.accessReviewRepo.DecideItem(item.ItemID, req.Decision, req.Comment, now); err != nil {
+		return model.AccessReviewItem{}, exception.ErrInternal
+	}
+
+	s.auditService.Record(c.Request.Context(), AuditAccessReviewDecided, &actor.ID, AuditTargetAccessReview, strconv.FormatUint(uint64(item.CampaignID), 10), map[string]any{
+		"item_id":  item.ItemID,
+		"user_id":  item.UserID,
+		"role_id":  item.RoleID,
+		"decision": req.Decision,
+	})
+
+	item.Decision = req.Decision
+	item.Comment = req.Comment
+	item.DecidedAt = &now
+	return item, nil
+}
+
+// CloseCampaign applies the revocations of a fully reviewed campaign and closes it. When a revocation fails the
+// campaign stays open, so that closing it again retries the revocations not applied yet.
+func (s *accessReviewService) CloseCampaign(c *gin.Context, actorEmail string, id uint) (model.AccessReviewCampaign, error) {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return model.AccessReviewCampaign{}, exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	campaign, err := s.GetCampaign(c, id)
+	if err != nil {
+		return model.AccessReviewCampaign{}, err
+	}
+	if campaign.Status != model.AccessReviewOpen {
+		return model.AccessReviewCampaign{}, exception.NewConflictBusinessException("Access review campaign is already closed")
+	}
+
+	pending, err := s.accessReviewRepo.CountPendingItems(campaign.CampaignID)
+	if err != nil {
+		return model.AccessReviewCampaign{}, exception.ErrInternal
+	}
+	if pending > 0 {
+		return model.AccessReviewCampaign{}, exception.NewConflictBusinessException(strconv.FormatInt(pending, 10) + " items are still pending review")
+	}
+
+	now := time.Now()
+	revoked, failed := 0, 0
+	for _, item := range campaign.Items {
+		if item.Decision != model.AccessReviewRevoke || item.AppliedAt != nil {
+			continue
+		}
+
+		err := s.roleGrantService.RevokeRole(c, actorEmail, requestDTO.RevokeRoleRequest{
+			UserID: item.UserID,
+			RoleID: item.RoleID,
+			Reason: "access review #" + strconv.FormatUint(uint64(campaign.CampaignID), 10),
+		})
+		var appErr *exception.AppError
+		if err != nil && !(errors.As(err, &appErr) && appErr.StatusCode == exception.ErrNotFound.StatusCode) {
+			slog.ErrorContext(c.Request.Context(), "failed to apply access review revocation",
+				"campaignId", campaign.CampaignID,
+				"itemId", item.ItemID,
+				"error", err,
+			)
+			failed++
+			continue
+		}
+
+		// An assignment that is already gone counts as applied
+		if err := s.accessReviewRepo.MarkItemApplied(item.ItemID, now); err != nil {
+			return model.AccessReviewCampaign{}, exception.ErrInternal
+		}
+		revoked++
+	}
+	if failed > 0 {
+		return model.AccessReviewCampaign{}, exception.NewInternal(strconv.Itoa(failed) + " revocations could not be applied; the campaign stays open, close it again to retry")
+	}
+
+	closed, err := s.accessReviewRepo.CloseCampaign(campaign.CampaignID, actor.ID, now)
+	if err != nil {
+		return model.AccessReviewCampaign{}, exception.ErrInternal
+	}
+	if !closed {
+		return model.AccessReviewCampaign{}, exception.NewConflictBusinessException("Access review campaign was closed concurrently")
+	}
+
+	s.auditService.Record(c.Request.Context(), AuditAccessReviewClosed, &actor.ID, AuditTargetAccessReview, strconv.FormatUint(uint64(campaign.CampaignID), 10), map[string]any{
+		"items":   len(campaign.Items),
+		"revoked": revoked,
+	})
+
+	return s.GetCampaign(c, campaign.CampaignID)
+}
+
+// pickReviewer routes an assignment to the first designated approver of its role who is not the assignee, or else
+// to the default reviewer. It reports false when the assignee is the only candidate: nobody certifies their own access.
+func pickReviewer(userID uint, approverIDs []uint, defaultReviewerID uint) (uint, bool) {
+	for _, approverID := range approverIDs {
+		if approverID != userID {
+			return approverID, true
+		}
+	}
+	if defaultReviewerID == userID {
+		return 0, false
+	}
+	return defaultReviewerID, true
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/request"
+	"auth-service/internal/repository"
+	"auth-service/pkg/utils/exception"
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// fakeUserRepository looks users up in memory by email and ID
+type fakeUserRepository struct {
+	repository.UserRepository
+	users []model.User
+}
+
+func (r *fakeUserRepository) FindByEmail(email string) (model.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return model.User{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepository) FindByID(id uint) (model.User, error) {
+	for _, user := range r.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return model.User{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepository) UpdatePassword(id uint, hashedPassword string) error {
+	for i := range r.users {
+		if r.users[i].ID == id {
+			r.users[i].Password = hashedPassword
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepository) GetTokenGeneration(email string) (int, error) {
+	user, err := r.FindByEmail(email)
+	return user.TokenGeneration, err
+}
+
+func (r *fakeUserRepository) IncrementTokenGeneration(id uint) (int, error) {
+	for i := range r.users {
+		if r.users[i].ID == id {
+			r.users[i].TokenGeneration++
+			return r.users[i].TokenGeneration, nil
+		}
+	}
+	return 0, gorm.ErrRecordNotFound
+}
+
+// fakeAccessReviewRepository holds a single campaign
+type fakeAccessReviewRepository struct {
+	repository.AccessReviewRepository
+	campaign model.AccessReviewCampaign
+}
+
+func (r *fakeAccessReviewRepository) FindCampaignByID(id uint) (model.AccessReviewCampaign, error) {
+	if id != r.campaign.CampaignID {
+		return model.AccessReviewCampaign{}, gorm.ErrRecordNotFound
+	}
+	return r.campaign, nil
+}
+
+func (r *fakeAccessReviewRepository) CountPendingItems(campaignID uint) (int64, error) {
+	var count int64
+	for _, item := range r.campaign.Items {
+		if item.Decision == model.AccessReviewPending {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeAccessReviewRepository) MarkItemApplied(id uint, at time.Time) error {
+	for i := range r.campaign.Items {
+		if r.campaign.Items[i].ItemID == id {
+			r.campaign.Items[i].AppliedAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *fakeAccessReviewRepository) CloseCampaign(id uint, actorID uint, at time.Time) (bool, error) {
+	if r.campaign.Status != model.AccessReviewOpen {
+		return false, nil
+	}
+	r.campaign.Status = model.AccessReviewClosed
+	r.campaign.ClosedBy = &actorID
+	r.campaign.ClosedAt = &at
+	return true, nil
+}
+
+// fakeRoleGrantService revokes grants by failing with the error configured for the role, if any
+type fakeRoleGrantService struct {
+	RoleGrantService
+	errs    map[uint]error
+	revoked []uint
+}
+
+func (s *fakeRoleGrantService) RevokeRole(c *gin.Context, actorEmail string, req requestDTO.RevokeRoleRequest) error {
+	if err := s.errs[req.RoleID]; err != nil {
+		return err
+	}
+	s.revoked = append(s.revoked, req.RoleID)
+	return nil
+}
+
+func newTestContext() *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("POST", "/", nil)
+	return c
+}
+
+func TestPickReviewer(t *testing.T) {
+	tests := []struct {
+		name              string
+		userID            uint
+		approverIDs       []uint
+		defaultReviewerID uint
+		want              uint
+		wantOK            bool
+	}{
+		{"first approver", 1, []uint{2, 3}, 9, 2, true},
+		{"assignee skipped", 2, []uint{2, 3}, 9, 3, true},
+		{"assignee is the only approver", 2, []uint{2}, 9, 9, true},
+		{"no approvers", 1, nil, 9, 9, true},
+		{"assignee is the default reviewer", 9, nil, 9, 0, false},
+		{"assignee is the only approver and the default reviewer", 9, []uint{9}, 9, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := pickReviewer(tt.userID, tt.approverIDs, tt.defaultReviewerID)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("pickReviewer() = %d, %v; want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestAccessReviewServiceCloseCampaign(t *testing.T) {
+	admin := model.User{ID: 9, Email: "admin@example.com"}
+	appliedAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		items       []model.AccessReviewItem
+		errs        map[uint]error
+		wantStatus  int
+		wantRevoked []uint
+		wantApplied []uint
+	}{
+		{
+			name: "pending items block completion",
+			items: []model.AccessReviewItem{
+				{ItemID: 1, UserID: 1, RoleID: 1, Decision: model.AccessReviewRevoke},
+				{ItemID: 2, UserID: 1, RoleID: 2, Decision: model.AccessReviewPending},
+			},
+			wantStatus: 409,
+		},
+		{
+			name: "revocations are applied and kept grants left alone",
+			items: []model.AccessReviewItem{
+				{ItemID: 1, UserID: 1, RoleID: 1, Decision: model.AccessReviewRevoke},
+				{ItemID: 2, UserID: 1, RoleID: 2, Decision: model.AccessReviewKeep},
+			},
+			wantRevoked: []uint{1},
+			wantApplied: []uint{1},
+		},
+		{
+			name: "an assignment already gone counts as applied",
+			items: []model.AccessReviewItem{
+				{ItemID: 1, UserID: 1, RoleID: 1, Decision: model.AccessReviewRevoke},
+			},
+			errs:        map[uint]error{1: exception.NewNotFound("Role grant not found")},
+			wantApplied: []uint{1},
+		},
+		{
+			name: "a failed revocation keeps the campaign open",
+			items: []model.AccessReviewItem{
+				{ItemID: 1, UserID: 1, RoleID: 1, Decision: model.AccessReviewRevoke},
+				{ItemID: 2, UserID: 1, RoleID: 2, Decision: model.AccessReviewRevoke},
+			},
+			errs:        map[uint]error{1: errors.New("database is down")},
+			wantStatus:  500,
+			wantRevoked: []uint{2},
+			wantApplied: []uint{2},
+		},
+		{
+			name: "closing again retries only the revocations not applied yet",
+			items: []model.AccessReviewItem{
+				{ItemID: 1, UserID: 1, RoleID: 1, Decision: model.AccessReviewRevoke},
+				{ItemID: 2, UserID: 1, RoleID: 2, Decision: model.AccessReviewRevoke, AppliedAt: &appliedAt},
+			},
+			wantRevoked: []uint{1},
+			wantApplied: []uint{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reviews := &fakeAccessReviewRepository{campaign: model.AccessReviewCampaign{CampaignID: 1, Status: model.AccessReviewOpen, Items: tt.items}}
+			grants := &fakeRoleGrantService{errs: tt.errs}
+			s := NewAccessReviewService(&fakeUserRepository{users: []model.User{admin}}, nil, nil, reviews, grants, &fakeAuditService{})
+
+			_, err := s.CloseCampaign(newTestContext(), admin.Email, 1)
+			wantCampaignStatus := model.AccessReviewClosed
+			if tt.wantStatus != 0 {
+				var appErr *exception.AppError
+				if !errors.As(err, &appErr) || appErr.StatusCode != tt.wantStatus {
+					t.Fatalf("CloseCampaign() error = %v, want status %d", err, tt.wantStatus)
+				}
+				wantCampaignStatus = model.AccessReviewOpen
+			} else if err != nil {
+				t.Fatalf("CloseCampaign() error = %v", err)
+			}
+			if reviews.campaign.Status != wantCampaignStatus {
+				t.Errorf("campaign status = %s, want %s", reviews.campaign.Status, wantCampaignStatus)
+			}
+			if !slices.Equal(grants.revoked, tt.wantRevoked) {
+				t.Errorf("revoked roles %v, want %v", grants.revoked, tt.wantRevoked)
+			}
+
+			var applied []uint
+			for _, item := range reviews.campaign.Items {
+				if item.AppliedAt != nil {
+					applied = append(applied, item.ItemID)
+				}
+			}
+			if !slices.Equal(applied, tt.wantApplied) {
+				t.Errorf("applied items %v, want %v", applied, tt.wantApplied)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/request"
+	"auth-service/internal/repository"
+	"auth-service/pkg/utils/exception"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttributeService manages custom user attribute definitions and validates attribute values against them
+type AttributeService interface {
+	ListDefinitions(c *gin.Context) ([]model.AttributeDefinition, error)
+	CreateDefinition(c *gin.Context, actorEmail string, req requestDTO.CreateAttributeDefinitionRequest) (model.AttributeDefinition, error)
+	DeleteDefinition(c *gin.Context, actorEmail string, id uint) error
+	GetAttributes(c *gin.Context, userID uint) (model.Attributes, error)
+	GetOwnAttributes(c *gin.Context, userEmail string) (model.Attributes, error)
+	UpdateOwnAttributes(c *gin.Context, userEmail string, req requestDTO.UpdateAttributesRequest) (model.Attributes, error)
+	UpdateUserAttributes(c *gin.Context, actorEmail string, userID uint, req requestDTO.UpdateAttributesRequest) (model.Attributes, error)
+	// ValidateNew validates the attributes a user registers with
+	ValidateNew(attributes map[string]any) (model.Attributes, error)
+	// Exposed returns the user's attributes that are exposed in tokens
+	Exposed(user model.User) (model.Attributes, error)
+}
+
+type attributeService struct {
+	userRepo      repository.UserRepository
+	attributeRepo repository.AttributeRepository
+	auditService  AuditService
+}
+
+func NewAttributeService(userRepo repository.UserRepository, attributeRepo repository.AttributeRepository, auditService AuditService) AttributeService {
+	return &attributeService{userRepo, attributeRepo, auditService}
+}
+
+var attributeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+func (s *attributeService) ListDefinitions(c *gin.Context) ([]model.AttributeDefinition, error) {
+	definitions, err := s.attributeRepo.FindAll()
+	if err != nil {
+		return nil, exception.ErrInternal
+	}
+	return definitions, nil
+}
+
+func (s *attributeService) CreateDefinition(c *gin.Context, actorEmail string, req requestDTO.CreateAttributeDefinitionRequest) (model.AttributeDefinition, error) {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return model.AttributeDefinition{}, exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	if !attributeNamePattern.MatchString(req.Name) {
+		return model.AttributeDefinition{}, exception.NewBadRequest("Attribute names are lowercase letters, digits and underscores")
+	}
+	if _, err := s.attributeRepo.FindByName(req.Name); err == nil {
+		return model.AttributeDefinition{}, exception.NewConflictBusinessException("Attribute already exists")
+	}
+
+	userEditable := true
+	if req.UserEditable != nil {
+		userEditable = *req.UserEditable
+	}
+
+	definition, err := s.attributeRepo.Create(model.AttributeDefinition{
+		Name:            req.Name,
+		Type:            req.Type,
+		Description:     req.Description,
+		Required:        req.Required,
+		UserEditable:    userEditable,
+		ExposedInTokens: req.ExposedInTokens,
+	})
+	if err != nil {
+		return model.AttributeDefinition{}, exception.NewInternal("Failed to save attribute definition")
+	}
+
+	s.auditService.Record(c.Request.Context(), AuditAttributeDefined, &actor.ID, AuditTargetAttribute, definition.Name, map[string]any{
+		"type":              definition.Type,
+		"required":          definition.Required,
+		"user_editable":     definition.UserEditable,
+		"exposed_in_tokens": definition.ExposedInTokens,
+	})
+
+	return definition, nil
+}
+
+// DeleteDefinition removes the definition; values users still hold are dropped on their next update
+func (s *attributeService) DeleteDefinition(c *gin.Context, actorEmail string, id uint) error {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	deleted, err := s.attributeRepo.Delete(id)
+	if err != nil {
+		return exception.NewInternal("Failed to delete attribute definition")
+	}
+	if !deleted {
+		return exception.NewNotFound("Attribute definition not found")
+	}
+
+	s.auditService.Record(c.Request.Context(), AuditAttributeDeleted, &actor.ID, AuditTargetAttribute, strconv.FormatUint(uint64(id), 10), nil)
+
+	return nil
+}
+
+// GetAttributes returns the user's values of the currently defined attributes
+func (s *attributeService) GetAttributes(c *gin.Context, userID uint) (model.Attributes, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, exception.NewNotFound("User not found")
+	}
+
+	definitions, err := s.definitions()
+	if err != nil {
+		return nil, err
+	}
+	return defined(user.Attributes, definitions), nil
+}
+
+func (s *attributeService) GetOwnAttributes(c *gin.Context, userEmail string) (model.Attributes, error) {
+	user, err := s.userRepo.FindByEmail(strings.TrimSpace(userEmail))
+	if err != nil {
+		return nil, exception.NewUnauthorizedBusinessException("User not found")
+	}
+	return s.GetAttributes(c, user.ID)
+}
+
+// UpdateOwnAttributes lets a user change their user-editable attributes
+func (s *attributeService) UpdateOwnAttributes(c *gin.Context, userEmail string, req requestDTO.UpdateAttributesRequest) (model.Attributes, error) {
+	user, err := s.userRepo.FindByEmail(strings.TrimSpace(userEmail))
+	if err != nil {
+		return nil, exception.NewUnauthorizedBusinessException("User not found")
+	}
+	return s.update(c, user, &user.ID, req.Attributes, false)
+}
+
+// UpdateUserAttributes lets an admin change any attribute of a user
+func (s *attributeService) UpdateUserAttributes(c *gin.Context, actorEmail string, userID uint, req requestDTO.UpdateAttributesRequest) (model.Attributes, error) {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return nil, exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, exception.NewNotFound("User not found")
+	}
+	return s.update(c, user, &actor.ID, req.Attributes, true)
+}
+
+func (s *attributeService) update(c *gin.Context, user model.User, actorID *uint, changes map[string]any, admin bool) (model.Attributes, error) {
+	definitions, err := s.definitions()
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := applyAttributes(definitions, user.Attributes, changes, admin)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.UpdateAttributes(user.ID, attributes); err != nil {
+		return nil, exception.NewInternal("Failed to update attributes")
+	}
+
+	changed := make([]string, 0, len(changes))
+	for name := range changes {
+		changed = append(changed, name)
+	}
+	s.auditService.Record(c.Request.Context(), AuditAttributesUpdated, actorID, AuditTargetUser, strconv.FormatUint(uint64(user.ID), 10), map[string]any{
+		"attributes": changed,
+	})
+
+	return attributes, nil
+}
+
+func (s *attributeService) ValidateNew(attributes map[string]any) (model.Attributes, error) {
+	definitions, err := s.definitions()
+	if err != nil {
+		return nil, err
+	}
+	return applyAttributes(definitions, nil, attributes, false)
+}
+
+func (s *attributeService) Exposed(user model.User) (model.Attributes, error) {
+	definitions, err := s.definitions()
+	if err != nil {
+		return nil, err
+	}
+
+	var exposed model.Attributes
+	for name, definition := range definitions {
+		value, ok := user.Attributes[name]
+		if !ok || !definition.ExposedInTokens {
+			continue
+		}
+		if exposed == nil {
+			exposed = model.Attributes{}
+		}
+		exposed[name] = value
+	}
+	return exposed, nil
+}
+
+func (s *attributeService) definitions() (map[string]model.AttributeDefinition, error) {
+	definitions, err := s.attributeRepo.FindAll()
+	if err != nil {
+		return nil, exception.ErrInternal
+	}
+
+	byName := make(map[string]model.AttributeDefinition, len(definitions))
+	for _, definition := range definitions {
+		byName[definition.Name] = definition
+	}
+	return byName, nil
+}
+
+// applyAttributes validates changes against the definitions and applies them to current, dropping values of
+// attributes that are no longer defined. Users may only touch user-editable attributes and only have to provide
+// the required ones they can edit; admins may touch and must provide all of them.
+func applyAttributes(definitions map[string]model.AttributeDefinition, current model.Attributes, changes map[string]any, admin bool) (model.Attributes, error) {
+	attributes := defined(current, definitions)
+
+	for name, value := range changes {
+		definition, ok := definitions[name]
+		if !ok {
+			return nil, exception.NewBadRequest(fmt.Sprintf("Unknown attribute %q", name))
+		}
+		if !admin && !definition.UserEditable {
+			return nil, exception.NewBadRequest(fmt.Sprintf("Attribute %q can only be changed by an administrator", name))
+		}
+
+		if value == nil {
+			delete(attributes, name)
+			continue
+		}
+		if err := checkAttributeType(definition, value); err != nil {
+			return nil, err
+		}
+		attributes[name] = value
+	}
+
+	for name, definition := range definitions {
+		if !definition.Required || !(admin || definition.UserEditable) {
+			continue
+		}
+		if _, ok := attributes[name]; !ok {
+			return nil, exception.NewBadRequest(fmt.Sprintf("Attribute %q is required", name))
+		}
+	}
+
+	return attributes, nil
+}
+
+// defined returns a copy of the attributes that are still defined
+func defined(attributes model.Attributes, definitions map[string]model.AttributeDefinition) model.Attributes {
+	result := model.Attributes{}
+	for name, value := range attributes {
+		if _, ok := definitions[name]; ok {
+			result[name] = value
+		}
+	}
+	return result
+}
+
+func checkAttributeType(definition model.AttributeDefinition, value any) error {
+	valid := false
+	switch definition.Type {
+	case model.AttributeTypeString:
+		_, valid = value.(string)
+	case model.AttributeTypeNumber:
+		_, valid = value.(float64)
+	case model.AttributeTypeBoolean:
+		_, valid = value.(bool)
+	case model.AttributeTypeDate:
+		if date, ok := value.(string); ok {
+			_, err := time.Parse(time.DateOnly, date)
+			valid = err == nil
+		}
+	}
+
+	if !valid {
+		return exception.NewBadRequest(fmt.Sprintf("Attribute %q must be a %s", definition.Name, definition.Type))
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"auth-service/internal/model"
+	"auth-service/pkg/utils/exception"
+	"errors"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// fakeAttributeRepository keeps definitions in a slice
+type fakeAttributeRepository struct {
+	definitions []model.AttributeDefinition
+}
+
+func (r *fakeAttributeRepository) FindAll() ([]model.AttributeDefinition, error) {
+	return r.definitions, nil
+}
+
+func (r *fakeAttributeRepository) FindByName(name string) (model.AttributeDefinition, error) {
+	for _, definition := range r.definitions {
+		if definition.Name == name {
+			return definition, nil
+		}
+	}
+	return model.AttributeDefinition{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeAttributeRepository) Create(definition model.AttributeDefinition) (model.AttributeDefinition, error) {
+	definition.AttributeDefinitionID = uint(len(r.definitions) + 1)
+	r.definitions = append(r.definitions, definition)
+	return definition, nil
+}
+
+func (r *fakeAttributeRepository) Delete(id uint) (bool, error) {
+	for i, definition := range r.definitions {
+		if definition.AttributeDefinitionID == id {
+			r.definitions = append(r.definitions[:i], r.definitions[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestCheckAttributeType(t *testing.T) {
+	tests := []struct {
+		name      string
+		valueType string
+		value     any
+		valid     bool
+	}{
+		{"string", model.AttributeTypeString, "Finance", true},
+		{"string given a number", model.AttributeTypeString, float64(1), false},
+		{"number", model.AttributeTypeNumber, float64(42), true},
+		{"number given a numeric string", model.AttributeTypeNumber, "42", false},
+		{"boolean", model.AttributeTypeBoolean, true, true},
+		{"boolean given a string", model.AttributeTypeBoolean, "true", false},
+		{"date", model.AttributeTypeDate, "2026-01-31", true},
+		{"date out of range", model.AttributeTypeDate, "2026-02-31", false},
+		{"date with time", model.AttributeTypeDate, "2026-01-31T10:00:00Z", false},
+		{"date given a number", model.AttributeTypeDate, float64(20260131), false},
+		{"unknown type", "color", "red", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definition := model.AttributeDefinition{Name: "attr", Type: tt.valueType}
+			err := checkAttributeType(definition, tt.value)
+			if (err == nil) != tt.valid {
+				t.Errorf("checkAttributeType(%v) = %v, want valid %v", tt.value, err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestApplyAttributes(t *testing.T) {
+	definitions := map[string]model.AttributeDefinition{
+		"department":  {Name: "department", Type: model.AttributeTypeString, Required: true, UserEditable: true},
+		"nickname":    {Name: "nickname", Type: model.AttributeTypeString, UserEditable: true},
+		"cost_center": {Name: "cost_center", Type: model.AttributeTypeNumber, Required: true},
+	}
+
+	tests := []struct {
+		name    string
+		current model.Attributes
+		changes map[string]any
+		admin   bool
+		want    model.Attributes
+		wantErr string
+	}{
+		{
+			name:    "admin sets every attribute",
+			changes: map[string]any{"department": "Finance", "cost_center": float64(7)},
+			admin:   true,
+			want:    model.Attributes{"department": "Finance", "cost_center": float64(7)},
+		},
+		{
+			name:    "user only needs editable required attributes",
+			changes: map[string]any{"department": "Finance"},
+			want:    model.Attributes{"department": "Finance"},
+		},
+		{
+			name:    "user cannot set admin attributes",
+			changes: map[string]any{"department": "Finance", "cost_center": float64(7)},
+			wantErr: `Attribute "cost_center" can only be changed by an administrator`,
+		},
+		{
+			name:    "unknown attribute",
+			changes: map[string]any{"department": "Finance", "shoe_size": float64(44)},
+			wantErr: `Unknown attribute "shoe_size"`,
+		},
+		{
+			name:    "wrong type",
+			changes: map[string]any{"department": float64(1)},
+			wantErr: `Attribute "department" must be a string`,
+		},
+		{
+			name:    "missing required attribute",
+			changes: map[string]any{"nickname": "Ada"},
+			wantErr: `Attribute "department" is required`,
+		},
+		{
+			name:    "admin must set admin-only required attributes",
+			changes: map[string]any{"department": "Finance"},
+			admin:   true,
+			wantErr: `Attribute "cost_center" is required`,
+		},
+		{
+			name:    "null removes an attribute",
+			current: model.Attributes{"department": "Finance", "nickname": "Ada"},
+			changes: map[string]any{"nickname": nil},
+			want:    model.Attributes{"department": "Finance"},
+		},
+		{
+			name:    "null cannot remove a required attribute",
+			current: model.Attributes{"department": "Finance"},
+			changes: map[string]any{"department": nil},
+			wantErr: `Attribute "department" is required`,
+		},
+		{
+			name:    "values of deleted definitions are dropped",
+			current: model.Attributes{"department": "Finance", "legacy": "x"},
+			changes: map[string]any{"nickname": "Ada"},
+			want:    model.Attributes{"department": "Finance", "nickname": "Ada"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyAttributes(definitions, tt.current, tt.changes, tt.admin)
+			if tt.wantErr != "" {
+				var appErr *exception.AppError
+				if !errors.As(err, &appErr) || appErr.Message != tt.wantErr {
+					t.Fatalf("applyAttributes() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyAttributes() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("applyAttributes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttributeServiceExposed(t *testing.T) {
+	repo := &fakeAttributeRepository{definitions: []model.AttributeDefinition{
+		{Name: "department", Type: model.AttributeTypeString, ExposedInTokens: true},
+		{Name: "clearance", Type: model.AttributeTypeNumber, ExposedInTokens: true},
+		{Name: "salary", Type: model.AttributeTypeNumber},
+	}}
+	s := NewAttributeService(nil, repo, nil)
+
+	tests := []struct {
+		name       string
+		attributes model.Attributes
+		want       model.Attributes
+	}{
+		{"no attributes", nil, nil},
+		{"only hidden attributes", model.Attributes{"salary": float64(100)}, nil},
+		{"exposed only", model.Attributes{"department": "Finance", "salary": float64(100)}, model.Attributes{"department": "Finance"}},
+		{"undefined attributes are dropped", model.Attributes{"clearance": float64(3), "legacy": "x"}, model.Attributes{"clearance": float64(3)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Exposed(model.User{ID: 1, Attributes: tt.attributes})
+			if err != nil {
+				t.Fatalf("Exposed() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Exposed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	AuditRoleGranted             = "ROLE_GRANTED"
+	AuditRoleRevoked             = "ROLE_REVOKED"
+	AuditRoleGrantActivated      = "ROLE_GRANT_ACTIVATED"
+	AuditRoleGrantExpired        = "ROLE_GRANT_EXPIRED"
+	AuditAccessRequested         = "ACCESS_REQUESTED"
+	AuditAccessApproved          = "ACCESS_REQUEST_APPROVED"
+	AuditAccessRejected          = "ACCESS_REQUEST_REJECTED"
+	AuditAccessCancelled         = "ACCESS_REQUEST_CANCELLED"
+	AuditAccessReopened          = "ACCESS_REQUEST_REOPENED"
+	AuditRoleApproverAdded       = "ROLE_APPROVER_ADDED"
+	AuditRoleApproverRemoved     = "ROLE_APPROVER_REMOVED"
+	AuditAccessReviewStarted     = "ACCESS_REVIEW_STARTED"
+	AuditAccessReviewDecided     = "ACCESS_REVIEW_DECIDED"
+	AuditAccessReviewClosed      = "ACCESS_REVIEW_CLOSED"
+	AuditImpersonationStarted    = "IMPERSONATION_STARTED"
+	AuditImpersonatedRequest     = "IMPERSONATED_REQUEST"
+	AuditBreakGlassCreated       = "BREAK_GLASS_ACCOUNT_CREATED"
+	AuditBreakGlassCodeSealed    = "BREAK_GLASS_CODE_SEALED"
+	AuditBreakGlassRequested     = "BREAK_GLASS_ACTIVATION_REQUESTED"
+	AuditBreakGlassEnabled       = "BREAK_GLASS_ENABLED"
+	AuditBreakGlassDisabled      = "BREAK_GLASS_DISABLED"
+	AuditBreakGlassLogin         = "BREAK_GLASS_LOGIN"
+	AuditBreakGlassUnsealFailed  = "BREAK_GLASS_UNSEAL_FAILED"
+	AuditBreakGlassUnsealLocked  = "BREAK_GLASS_UNSEAL_LOCKED"
+	AuditBreakGlassLoginDisabled = "BREAK_GLASS_LOGIN_WHILE_DISABLED"
+	AuditTokensRevoked           = "TOKENS_REVOKED"
+	AuditPasswordChanged         = "PASSWORD_CHANGED"
+	AuditSessionEvicted          = "SESSION_EVICTED"
+	AuditClaimMappingCreated     = "CLAIM_MAPPING_CREATED"
+	AuditClaimMappingDeleted     = "CLAIM_MAPPING_DELETED"
+	AuditAttributeDefined        = "ATTRIBUTE_DEFINED"
+	AuditAttributeDeleted        = "ATTRIBUTE_DELETED"
+	AuditAttributesUpdated       = "USER_ATTRIBUTES_UPDATED"
+	AuditOAuthClientCreated      = "OAUTH_CLIENT_CREATED"
+	AuditOAuthClientDeleted      = "OAUTH_CLIENT_DELETED"
+	AuditTokenExchanged          = "TOKEN_EXCHANGED"
+	AuditDeviceApproved          = "DEVICE_AUTHORIZATION_APPROVED"
+	AuditDeviceDenied            = "DEVICE_AUTHORIZATION_DENIED"
+	AuditPersonalTokenCreated    = "PERSONAL_ACCESS_TOKEN_CREATED"
+	AuditPersonalTokenRevoked    = "PERSONAL_ACCESS_TOKEN_REVOKED"
+	AuditPersonalTokenDenied     = "PERSONAL_ACCESS_TOKEN_DENIED"
+	AuditServiceAccountCreated   = "SERVICE_ACCOUNT_CREATED"
+	AuditServiceAccountDeleted   = "SERVICE_ACCOUNT_DELETED"
+	AuditServiceKeyCreated       = "SERVICE_ACCOUNT_KEY_CREATED"
+	AuditServiceKeyRevoked       = "SERVICE_ACCOUNT_KEY_REVOKED"
+	AuditServiceCertAdded        = "SERVICE_ACCOUNT_CERTIFICATE_ADDED"
+	AuditServiceCertRemoved      = "SERVICE_ACCOUNT_CERTIFICATE_REMOVED"
+
+	AuditTargetUserRole      = "user_role"
+	AuditTargetAccessRequest = "access_request"
+	AuditTargetRole          = "role"
+	AuditTargetAccessReview  = "access_review"
+	AuditTargetUser          = "user"
+	AuditTargetClaimMapping  = "claim_mapping"
+	AuditTargetAttribute     = "attribute"
+	AuditTargetOAuthClient   = "oauth_client"
+	AuditTargetDevice        = "device_authorization"
+	AuditTargetPersonalToken = "personal_access_token"
+	AuditTargetServiceKey    = "service_account_key"
+	AuditTargetServiceCert   = "service_account_certificate"
+)
+
+type AuditService interface {
+	Record(ctx context.Context, action string, actorID *uint, targetType string, targetID string, metadata map[string]any)
+	Alert(ctx context.Context, action string, actorID *uint, targetType string, targetID string, metadata map[string]any)
+	Flush(ctx context.Context)
+}
+
+type auditService struct {
+	auditRepo       repository.AuditRepository
+	alertWebhookURL string
+	httpClient      *http.Client
+	deliveries      sync.WaitGroup
+}
+
+// NewAuditService creates the audit service. Alerts are additionally posted as JSON to alertWebhookURL when it is set.
+func NewAuditService(auditRepo repository.AuditRepository, alertWebhookURL string) AuditService {
+	return &auditService{
+		auditRepo:       auditRepo,
+		alertWebhookURL: alertWebhookURL,
+		httpClient:      &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Record persists an audit entry. Failures are logged rather than returned so that auditing never blocks the audited action.
+func (s *auditService) Record(ctx context.Context, action string, actorID *uint, targetType string, targetID string, metadata map[string]any) {
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		metadataJSON = []byte("{}")
+	}
+
+	entry := model.AuditLog{
+		Action:     action,
+		ActorID:    actorID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Metadata:   string(metadataJSON),
+	}
+
+	if _, err := s.auditRepo.Create(entry); err != nil {
+		slog.ErrorContext(ctx, "failed to write audit log",
+			"action", action,
+			"error", err,
+		)
+		return
+	}
+
+	slog.InfoContext(ctx, "audit",
+		"action", action,
+		"targetType", targetType,
+		"targetId", targetID,
+	)
+}
+
+// Alert records a security-relevant event that someone has to look at: it is audited, logged at error level
+// and pushed to the alert webhook.
+func (s *auditService) Alert(ctx context.Context, action string, actorID *uint, targetType string, targetID string, metadata map[string]any) {
+	s.Record(ctx, action, actorID, targetType, targetID, metadata)
+
+	slog.ErrorContext(ctx, "security alert",
+		"action", action,
+		"targetType", targetType,
+		"targetId", targetID,
+		"metadata", metadata,
+	)
+
+	if s.alertWebhookURL == "" {
+		return
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"action":      action,
+		"actor_id":    actorID,
+		"target_type": targetType,
+		"target_id":   targetID,
+		"metadata":    metadata,
+		"occurred_at": time.Now().UTC(),
+	})
+	if err != nil {
+		return
+	}
+
+	s.deliveries.Add(1)
+	go func() {
+		defer s.deliveries.Done()
+		resp, err := s.httpClient.Post(s.alertWebhookURL, "application/json", bytes.NewReader(payload))
+		if err != nil {
+			slog.Error("failed to deliver security alert", "action", action, "error", err)
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			slog.Error("security alert webhook rejected", "action", action, "status", resp.StatusCode)
+		}
+	}()
+}
+
+// Flush waits until the alerts posted so far have been delivered to the webhook, or until ctx is done. Short-lived
+// processes call it before exiting so their alerts are not lost.
+func (s *auditService) Flush(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.deliveries.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		slog.WarnContext(ctx, "gave up waiting for security alert delivery", "error", ctx.Err())
+	}
+}
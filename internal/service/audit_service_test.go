@@ -0,0 +1,44 @@
+package service
+
+import (
+	"auth-service/internal/model"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeAuditRepository accepts every entry
+type fakeAuditRepository struct{}
+
+func (r *fakeAuditRepository) Create(log model.AuditLog) (model.AuditLog, error) {
+	return log, nil
+}
+
+func TestAuditServiceFlush(t *testing.T) {
+	var delivered atomic.Int32
+	release := make(chan struct{})
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		delivered.Add(1)
+	}))
+	defer webhook.Close()
+
+	s := NewAuditService(&fakeAuditRepository{}, webhook.URL)
+	s.Alert(context.Background(), AuditBreakGlassCodeSealed, nil, AuditTargetUser, "1", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	s.Flush(ctx)
+	if delivered.Load() != 0 {
+		t.Fatal("alert delivered before the webhook answered")
+	}
+
+	close(release)
+	s.Flush(context.Background())
+	if delivered.Load() != 1 {
+		t.Errorf("Flush() returned before the alert was delivered")
+	}
+}
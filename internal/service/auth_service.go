@@ -2,37 +2,68 @@ package service
 
 import (
 	"auth-service/internal/config"
-	"auth-service/internal/infra/redis"
 	"auth-service/internal/model"
 	"auth-service/internal/model/dto/request"
+	"auth-service/internal/model/dto/response"
 	"auth-service/internal/repository"
+	"auth-service/internal/session"
+	"auth-service/internal/token"
+	"auth-service/pkg/identity"
 	"auth-service/pkg/utils/exception"
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthService interface {
-	Register(c *gin.Context,req requestDTO.RegisterRequest) error
+	TokenRevoker
+
+	Register(c *gin.Context, req requestDTO.RegisterRequest) error
 	Login(c *gin.Context, email, password string) (string, error)
-	Verify(c *gin.Context, authToken string) (string, error)
+	Verify(c *gin.Context, authToken string, audience string) (string, error)
+	VerifiedUser(c *gin.Context, data string, withPermissions bool) (responseDto.UserResponseV2, error)
+	CustomClaims(c *gin.Context, user responseDto.UserResponseV2, audience string) (map[string]any, error)
+	IdentityAssertion(c *gin.Context, user responseDto.UserResponseV2, claims map[string]any, audience string) (string, error)
+	AssertionKeys() identity.JWKS
 	Logout(c *gin.Context, authToken string) error
-	EnforceAuthorization(c *gin.Context, userEmail string, service string, endpoint string, httpMethod string) error
+	Impersonate(c *gin.Context, actorEmail string, req requestDTO.ImpersonateRequest) (string, error)
+	RecordImpersonatedRequest(c *gin.Context, user responseDto.UserResponse, service string, path string, httpMethod string)
+	ListSessions(c *gin.Context, userEmail string) ([]model.Session, error)
+	RevokeSession(c *gin.Context, userEmail string, sessionID string) error
+	LogoutAll(c *gin.Context, userEmail string) error
+	ForceLogout(c *gin.Context, actorEmail string, userID uint, reason string) error
+	ChangePassword(c *gin.Context, userEmail string, req requestDTO.ChangePasswordRequest) (string, error)
+	ExchangeToken(c *gin.Context, client model.OAuthClient, req requestDTO.TokenRequest) (responseDto.TokenResponse, error)
+	IssueDeviceToken(c *gin.Context, userID uint, scope []string) (responseDto.TokenResponse, error)
+	EnforceAuthorization(c *gin.Context, userEmail string, scope []string, service string, endpoint string, httpMethod string) error
 }
 
 type authService struct {
-	userRepo     repository.UserRepository
-	roleRepo     repository.RoleRepository
-	endpointRepo repository.EndpointRepository
+	userRepo             repository.UserRepository
+	roleRepo             repository.RoleRepository
+	endpointRepo         repository.EndpointRepository
+	personalAccessTokens repository.PersonalAccessTokenRepository
+	sessions             session.Store
+	tokens               token.Issuer
+	revocations          RevocationService
+	claimMappings        ClaimMappingService
+	attributes           AttributeService
+	assertions           *identity.Signer
+	auditService         AuditService
 }
 
-func NewAuthService(userRepo repository.UserRepository, roleRepo repository.RoleRepository, endpointRepo repository.EndpointRepository) AuthService {
-	return &authService{userRepo, roleRepo, endpointRepo}
+func NewAuthService(userRepo repository.UserRepository, roleRepo repository.RoleRepository, endpointRepo repository.EndpointRepository, personalAccessTokens repository.PersonalAccessTokenRepository, sessions session.Store, tokens token.Issuer, revocations RevocationService, claimMappings ClaimMappingService, attributes AttributeService, assertions *identity.Signer, auditService AuditService) AuthService {
+	return &authService{userRepo, roleRepo, endpointRepo, personalAccessTokens, sessions, tokens, revocations, claimMappings, attributes, assertions, auditService}
 }
 
 func (s *authService) Register(c *gin.Context, req requestDTO.RegisterRequest) error {
@@ -49,6 +80,12 @@ func (s *authService) Register(c *gin.Context, req requestDTO.RegisterRequest) e
 		return exception.NewConflictBusinessException("User already exists")
 	}
 
+	// Custom attributes must match their definitions
+	user.Attributes, err = s.attributes.ValidateNew(req.Attributes)
+	if err != nil {
+		return err
+	}
+
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -69,107 +106,898 @@ func (s *authService) Register(c *gin.Context, req requestDTO.RegisterRequest) e
 func (s *authService) Login(c *gin.Context, email, password string) (string, error) {
 	// Find user by email
 	user, err := s.userRepo.FindByEmail(strings.TrimSpace(email))
-	if err != nil || user.ID == 0 {
+	if err != nil || user.ID == 0 || user.IsServiceAccount() {
 		return "", exception.NewUnauthorizedBusinessException("Invalid email or password")
 	}
 
+	// Only grants whose validity window contains the current time count
+	roles, err := s.roleRepo.FindActiveByUserID(user.ID, time.Now())
+	if err != nil {
+		return "", exception.ErrInternal
+	}
+
 	var roleNames []string
-	for _, r := range user.Roles {
+	for _, r := range roles {
 		roleNames = append(roleNames, r.Name)
 	}
 
-	roleNamesString := strings.Join(roleNames, "|") // e.g. "SUPERADMIN|ADMIN|etc"
-
 	// Compare password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
 		return "", exception.NewUnauthorizedBusinessException("Invalid email or password")
 	}
 
-	// Load secret
-	secret := config.LoadConfig().JwtSecret
-	if secret == "" {
-		return "", exception.NewInternal("JWT secret is not set")
+	ttl := config.LoadConfig().SessionMaxLifetime
+
+	/*
+		Break-glass accounts only sign in during their activation window, and loudly
+	*/
+	if user.IsBreakGlass() {
+		now := time.Now()
+		userID := strconv.FormatUint(uint64(user.ID), 10)
+		if !user.CanLoginAt(now) {
+			s.auditService.Alert(c.Request.Context(), AuditBreakGlassLoginDisabled, &user.ID, AuditTargetUser, userID, map[string]any{
+				"client_ip": c.ClientIP(),
+			})
+			return "", exception.NewUnauthorizedBusinessException("Account is disabled")
+		}
+
+		// The session never outlives the activation window
+		if remaining := user.EnabledUntil.Sub(now); remaining < ttl {
+			ttl = remaining
+		}
+
+		s.auditService.Alert(c.Request.Context(), AuditBreakGlassLogin, &user.ID, AuditTargetUser, userID, map[string]any{
+			"client_ip":  c.ClientIP(),
+			"user_agent": c.Request.UserAgent(),
+			"expires_at": now.Add(ttl),
+		})
+	}
+
+	if err := s.enforceSessionLimit(c, user, roles); err != nil {
+		return "", err
+	}
+
+	return s.issueToken(c, user, roleNames, tokenOptions{ttl: ttl})
+}
+
+// IssueDeviceToken signs the user in on a device they approved through the device authorization grant, like Login
+// without the password. A non-nil scope limits the token to the requested permissions the user still holds.
+func (s *authService) IssueDeviceToken(c *gin.Context, userID uint, scope []string) (responseDto.TokenResponse, error) {
+	cfg := config.LoadConfig()
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return responseDto.TokenResponse{}, oauthError(OAuthInvalidGrant, "User not found")
+	}
+	if user.IsBreakGlass() {
+		return responseDto.TokenResponse{}, oauthError(OAuthInvalidGrant, "Break-glass accounts must sign in with their password")
+	}
+
+	roles, err := s.roleRepo.FindActiveByUserID(user.ID, time.Now())
+	if err != nil {
+		return responseDto.TokenResponse{}, exception.ErrInternal
+	}
+	var roleNames []string
+	for _, r := range roles {
+		roleNames = append(roleNames, r.Name)
+	}
+
+	if scope != nil {
+		permissions, err := s.roleRepo.GetPermissionsByRoleIds(extractRoleIDs(roles))
+		if err != nil {
+			return responseDto.TokenResponse{}, exception.ErrInternal
+		}
+		if scope = grantedScope(scope, permissionNames(permissions), nil); len(scope) == 0 {
+			return responseDto.TokenResponse{}, oauthError(OAuthInvalidScope, "None of the requested scope can be granted")
+		}
+	}
+
+	if err := s.enforceSessionLimit(c, user, roles); err != nil {
+		return responseDto.TokenResponse{}, err
+	}
+
+	signed, err := s.issueToken(c, user, roleNames, tokenOptions{ttl: cfg.SessionMaxLifetime, scope: scope})
+	if err != nil {
+		return responseDto.TokenResponse{}, err
+	}
+
+	return responseDto.TokenResponse{
+		AccessToken: signed,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(tokenLifetime(cfg, cfg.SessionMaxLifetime).Seconds()),
+		Scope:       strings.Join(scope, " "),
+	}, nil
+}
+
+// enforceSessionLimit makes room for one more session of the user, or rejects the login, depending on the configured policy
+func (s *authService) enforceSessionLimit(c *gin.Context, user model.User, roles []model.Role) error {
+	cfg := config.LoadConfig()
+
+	// Stateless tokens have no sessions to count
+	limit := sessionLimit(cfg.SessionMaxConcurrent, roles)
+	if limit == 0 || cfg.TokenMode == config.TokenModeStateless {
+		return nil
+	}
+
+	entries, err := s.sessions.ListByUser(c.Request.Context(), user.ID)
+	if err != nil {
+		return exception.ErrInternal
+	}
+	entries = signInSessions(entries)
+	if len(entries) < limit {
+		return nil
+	}
+
+	if cfg.SessionLimitPolicy == config.SessionLimitReject {
+		return exception.New(http.StatusConflict, "SESSION_LIMIT_EXCEEDED",
+			fmt.Sprintf("Maximum of %d concurrent sessions reached", limit))
+	}
+
+	// Evict the oldest sessions until the new one fits
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Record.Session.CreatedAt.Before(entries[j].Record.Session.CreatedAt)
+	})
+	for _, entry := range entries[:len(entries)-limit+1] {
+		if _, err := s.sessions.Delete(c.Request.Context(), entry.Key); err != nil {
+			return exception.ErrInternal
+		}
+
+		s.auditService.Record(c.Request.Context(), AuditSessionEvicted, &user.ID, AuditTargetUser, strconv.FormatUint(uint64(user.ID), 10), map[string]any{
+			"session_id": entry.Record.Session.ID,
+			"limit":      limit,
+		})
+	}
+
+	return nil
+}
+
+// signInSessions leaves out the sessions of exchanged tokens, which are delegations rather than sign-ins
+func signInSessions(entries []session.Entry) []session.Entry {
+	return slices.DeleteFunc(entries, func(entry session.Entry) bool {
+		return entry.Record.Session.Exchanged()
+	})
+}
+
+// sessionLimit resolves the concurrent session limit of a user: the strictest role override wins, otherwise the global limit applies
+func sessionLimit(global int, roles []model.Role) int {
+	limit := -1
+	for _, r := range roles {
+		if r.MaxSessions == nil {
+			continue
+		}
+		if *r.MaxSessions == 0 {
+			if limit < 0 {
+				limit = 0
+			}
+			continue
+		}
+		if limit <= 0 || *r.MaxSessions < limit {
+			limit = *r.MaxSessions
+		}
+	}
+	if limit < 0 {
+		return global
+	}
+	return limit
+}
+
+// Impersonate issues a short-lived token for the target user that carries the real admin in its "act" claim
+func (s *authService) Impersonate(c *gin.Context, actorEmail string, req requestDTO.ImpersonateRequest) (string, error) {
+	cfg := config.LoadConfig()
+
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return "", exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	target, err := s.userRepo.FindByID(req.UserID)
+	if err != nil {
+		return "", exception.NewNotFound("User not found")
+	}
+	if target.ID == actor.ID {
+		return "", exception.NewBadRequest("Cannot impersonate yourself")
+	}
+	if target.IsBreakGlass() {
+		return "", exception.New(http.StatusForbidden, "IMPERSONATION_FORBIDDEN", "Break-glass accounts cannot be impersonated")
+	}
+	if target.IsServiceAccount() {
+		return "", exception.New(http.StatusForbidden, "IMPERSONATION_FORBIDDEN", "Service accounts cannot be impersonated")
+	}
+
+	roles, err := s.roleRepo.FindActiveByUserID(target.ID, time.Now())
+	if err != nil {
+		return "", exception.ErrInternal
+	}
+
+	var roleNames []string
+	for _, r := range roles {
+		roleNames = append(roleNames, r.Name)
+		for _, protected := range cfg.ImpersonationProtectedRoles {
+			if strings.EqualFold(r.Name, protected) {
+				return "", exception.New(http.StatusForbidden, "IMPERSONATION_FORBIDDEN", "Administrators cannot be impersonated")
+			}
+		}
+	}
+
+	actorClaim := &responseDto.ActorResponse{
+		ID:    actor.ID,
+		Email: actor.Email,
+	}
+
+	signed, err := s.issueToken(c, target, roleNames, tokenOptions{ttl: cfg.ImpersonationTokenTTL, actor: actorClaim})
+	if err != nil {
+		return "", err
 	}
 
-	// Generate JWT
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"first_name": user.FirstName,
-		"last_name":  user.LastName,
-		"email":      user.Email,
-		"roles":      roleNamesString,
-		"exp":        time.Now().Add(12 * time.Hour).Unix(),
+	s.auditService.Record(c.Request.Context(), AuditImpersonationStarted, &actor.ID, AuditTargetUser, strconv.FormatUint(uint64(target.ID), 10), map[string]any{
+		"target_email": target.Email,
+		"reason":       req.Reason,
+		"expires_in":   cfg.ImpersonationTokenTTL.String(),
 	})
 
-	signed, err := token.SignedString([]byte(secret))
+	return signed, nil
+}
+
+// RecordImpersonatedRequest writes the audit trail entry for a request made with an impersonation token,
+// including tokens exchanged from one
+func (s *authService) RecordImpersonatedRequest(c *gin.Context, user responseDto.UserResponse, service string, path string, httpMethod string) {
+	impersonator := user.Actor.Impersonator()
+	if impersonator == nil {
+		return
+	}
+
+	slog.WarnContext(c.Request.Context(), "impersonated request",
+		"actorId", impersonator.ID,
+		"actorEmail", impersonator.Email,
+		"userEmail", user.Email,
+		"service", service,
+		"path", path,
+		"method", httpMethod,
+	)
+
+	s.auditService.Record(c.Request.Context(), AuditImpersonatedRequest, &impersonator.ID, AuditTargetUser, user.Email, map[string]any{
+		"service": service,
+		"path":    path,
+		"method":  httpMethod,
+	})
+}
+
+// tokenOptions shape an issued token
+type tokenOptions struct {
+	// ttl is the absolute lifetime of the session
+	ttl time.Duration
+	// actor marks the token as used on behalf of the user: by an impersonating admin, or by a service through token exchange
+	actor *responseDto.ActorResponse
+	// audience replaces the configured audiences
+	audience []string
+	// scope limits the token to these permissions
+	scope []string
+	// exchangedBy is the client a token exchange issues the token to
+	exchangedBy string
+}
+
+// issueToken issues a token for the user in the configured format and stores its session. The session additionally
+// ends after the configured idle timeout. Stateless JWTs are not stored and live at most the access token TTL;
+// opaque tokens are always stored since they carry no claims.
+func (s *authService) issueToken(c *gin.Context, user model.User, roleNames []string, opts tokenOptions) (string, error) {
+	roleNamesString := strings.Join(roleNames, "|") // e.g. "SUPERADMIN|ADMIN|etc"
+	cfg := config.LoadConfig()
+	ttl, actor := opts.ttl, opts.actor
+
+	audience := cfg.TokenAudiences
+	if opts.audience != nil {
+		audience = opts.audience
+	}
+
+	sessionID, err := newSessionID()
 	if err != nil {
-		return "", exception.NewInternal("Failed to sign token")
+		return "", exception.ErrInternal
 	}
 
-	value := gin.H{
-		"user": gin.H{
+	opaque := cfg.TokenFormat == config.TokenFormatOpaque
+	stateless := !opaque && cfg.TokenMode == config.TokenModeStateless
+	ttl = tokenLifetime(cfg, ttl)
+
+	// Attributes exposed in tokens travel in the "attributes" claim and with the session
+	attributes, err := s.attributes.Exposed(user)
+	if err != nil {
+		return "", err
+	}
+
+	// Tokens issued over mutual TLS may be bound to the client certificate
+	confirmation := certificateConfirmation(c, cfg)
+
+	now := time.Now()
+	var signed string
+	if opaque {
+		signed, err = newOpaqueToken()
+		if err != nil {
+			return "", exception.ErrInternal
+		}
+	} else {
+		tokenID, err := newTokenID()
+		if err != nil {
+			return "", exception.ErrInternal
+		}
+
+		claims := token.Claims{
+			"sub":        strconv.FormatUint(uint64(user.ID), 10),
+			"jti":        tokenID,
+			"iat":        now.Unix(),
+			"nbf":        now.Unix(),
 			"first_name": user.FirstName,
 			"last_name":  user.LastName,
 			"email":      user.Email,
-			"roles":      roleNamesString,
-		}}
+			"roles":      roleNameList(roleNames),
+			"sid":        sessionID,
+			"gen":        user.TokenGeneration,
+			"exp":        now.Add(ttl).Unix(),
+		}
+		if actor != nil {
+			claims["act"] = actor
+		}
+		if len(attributes) > 0 {
+			claims["attributes"] = attributes
+		}
+		if opts.audience != nil {
+			claims["aud"] = opts.audience
+		}
+		if opts.scope != nil {
+			claims["scope"] = strings.Join(opts.scope, " ")
+		}
+		if confirmation != nil {
+			claims["cnf"] = confirmation
+		}
 
-	jsonValue, err := json.Marshal(value)
-	if err != nil {
-		return "", exception.ErrInternal
+		// Custom claims for the audiences the token is issued for; they never replace the claims above
+		custom, err := s.claimMappings.Claims(c.Request.Context(), audience, responseDto.UserResponseV2{
+			ID:         user.ID,
+			FirstName:  user.FirstName,
+			LastName:   user.LastName,
+			Email:      user.Email,
+			Roles:      roleNameList(roleNames),
+			Attributes: attributes,
+		})
+		if err != nil {
+			return "", err
+		}
+		for name, value := range custom {
+			if _, ok := claims[name]; !ok {
+				claims[name] = value
+			}
+		}
+
+		signed, err = s.tokens.Issue(claims)
+		if err != nil {
+			return "", exception.NewInternal("Failed to sign token")
+		}
+
+		if stateless {
+			return signed, nil
+		}
 	}
 
-	if err := redis.Set(signed, string(jsonValue), 12*3600*time.Second); err != nil {
+	record := session.Record{
+		User: responseDto.UserResponse{
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Email:     user.Email,
+			Roles:     roleNamesString,
+			Scope:     opts.scope,
+			Actor:     actor,
+		},
+		Session: model.Session{
+			ID:          sessionID,
+			UserID:      user.ID,
+			UserAgent:   c.Request.UserAgent(),
+			IP:          c.ClientIP(),
+			CreatedAt:   now,
+			LastSeenAt:  now,
+			ExpiresAt:   now.Add(ttl),
+			Generation:  user.TokenGeneration,
+			ExchangedBy: opts.exchangedBy,
+		},
+		Attributes:   attributes,
+		Audience:     opts.audience,
+		Confirmation: confirmation,
+	}
+
+	if err := s.sessions.Save(c.Request.Context(), session.TokenKey(signed), record, record.Session.SlidingTTL(now, cfg.SessionIdleTimeout)); err != nil {
 		return "", exception.ErrInternal
 	}
 
 	return signed, nil
 }
 
-func (s *authService) Verify(c *gin.Context, authToken string) (string, error) {
+// tokenLifetime is how long a token issued for a session of the given ttl lives
+func tokenLifetime(cfg config.Config, ttl time.Duration) time.Duration {
+	stateless := cfg.TokenFormat != config.TokenFormatOpaque && cfg.TokenMode == config.TokenModeStateless
+	if stateless && cfg.AccessTokenTTL < ttl {
+		return cfg.AccessTokenTTL
+	}
+	return ttl
+}
+
+// Verify validates the token and returns its session record as JSON. A non-empty audience is the service the token
+// is presented to; self-contained tokens must have been issued for it. Opaque tokens never leave auth-service
+// readable, so they are only audience-scoped when exchanged for one. Personal access tokens are never audience-scoped.
+func (s *authService) Verify(c *gin.Context, authToken string, audience string) (string, error) {
 	authToken = strings.TrimSpace(authToken)
 	if authToken == "" {
 		return "", exception.NewUnauthorizedBusinessException("Authorization token is required")
 	}
+	if isPersonalAccessToken(authToken) {
+		return s.verifyPersonalAccessToken(c, authToken)
+	}
 
-	secret := config.LoadConfig().JwtSecret
-	_, err := verifyToken(authToken, secret)
-	if err != nil {
-		return "", err
+	cfg := config.LoadConfig()
+	opaque := isOpaqueToken(authToken)
+	if !opaque {
+		claims, err := s.tokens.Verify(authToken, audience)
+		if err != nil {
+			return "", err
+		}
+
+		if cfg.TokenMode == config.TokenModeStateless {
+			return s.verifyStateless(c, claims)
+		}
+
+		if err := s.checkTokenGeneration(claims); err != nil {
+			return "", err
+		}
 	}
 
-	data, err := redis.Rdb.Get(redis.Ctx, authToken).Result()
+	ctx := c.Request.Context()
+	data, record, err := s.loadSession(ctx, authToken)
 	if err != nil {
 		return "", exception.NewUnauthorizedBusinessException("Token not valid or expired")
 	}
 
+	// Opaque tokens carry no claims; the generation and audience they were issued under are kept with their session
+	if opaque {
+		if err := s.checkGeneration(record.User.Email, record.Session.Generation); err != nil {
+			return "", err
+		}
+		if audience != "" && record.Audience != nil && !slices.Contains(record.Audience, audience) {
+			return "", token.ErrTokenAudienceInvalid
+		}
+	}
+
+	if err := checkCertificateBinding(c, record); err != nil {
+		return "", err
+	}
+
+	// Tokens issued before sessions were tracked carry no session to check
+	current := record.Session
+	if current.ID == "" {
+		return data, nil
+	}
+
+	now := time.Now()
+	if current.LifetimeExpired(now) {
+		s.sessions.Delete(ctx, session.TokenKey(authToken))
+		return "", exception.New(http.StatusUnauthorized, "SESSION_EXPIRED", "Session has reached its maximum lifetime")
+	}
+	if current.IdleExpired(now, cfg.SessionIdleTimeout) {
+		s.sessions.Delete(ctx, session.TokenKey(authToken))
+		return "", exception.New(http.StatusUnauthorized, "SESSION_IDLE_TIMEOUT", "Session expired due to inactivity")
+	}
+
+	// Write activity back at most once per touch interval to keep Verify cheap
+	if now.Sub(current.LastSeenAt) >= cfg.SessionTouchInterval {
+		if touched, err := s.touchSession(ctx, authToken, record, now, current.SlidingTTL(now, cfg.SessionIdleTimeout)); err == nil {
+			data = touched
+		}
+	}
+
 	return data, nil
 }
 
+// VerifiedUser builds the v2 view of a session record returned by Verify, optionally with the permissions the token can use
+func (s *authService) VerifiedUser(c *gin.Context, data string, withPermissions bool) (responseDto.UserResponseV2, error) {
+	var record session.Record
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		return responseDto.UserResponseV2{}, exception.ErrInternal
+	}
+
+	user := responseDto.UserResponseV2{
+		ID:         record.Session.UserID,
+		FirstName:  record.User.FirstName,
+		LastName:   record.User.LastName,
+		Email:      record.User.Email,
+		Roles:      splitRoles(record.User.Roles),
+		Attributes: record.Attributes,
+		Scope:      record.User.Scope,
+		Actor:      record.User.Actor,
+	}
+
+	// Sessions from before sessions were tracked only know the email
+	if user.ID == 0 {
+		found, err := s.userRepo.FindByEmail(user.Email)
+		if err != nil {
+			return responseDto.UserResponseV2{}, exception.NewUnauthorizedBusinessException("User not found")
+		}
+		user.ID = found.ID
+	}
+
+	if withPermissions {
+		roles, err := s.roleRepo.FindActiveByUserID(user.ID, time.Now())
+		if err != nil {
+			return responseDto.UserResponseV2{}, exception.ErrInternal
+		}
+		permissions, err := s.roleRepo.GetPermissionsByRoleIds(extractRoleIDs(roles))
+		if err != nil {
+			return responseDto.UserResponseV2{}, exception.ErrInternal
+		}
+		user.Permissions = scopedPermissions(permissionNames(permissions), record.User.Scope)
+	}
+
+	return user, nil
+}
+
+// scopedPermissions are the permissions a token can use: those of the user, limited to the token's scope
+// when it is scoped, as EnforceAuthorization does
+func scopedPermissions(permissions []string, scope []string) []string {
+	if scope == nil || slices.Contains(scope, "ALL") {
+		return permissions
+	}
+	return grantedScope(scope, permissions, nil)
+}
+
+// CustomClaims resolves the custom claims mapped for the audience, e.g. the service an introspection is for
+func (s *authService) CustomClaims(c *gin.Context, user responseDto.UserResponseV2, audience string) (map[string]any, error) {
+	return s.claimMappings.Claims(c.Request.Context(), []string{audience}, user)
+}
+
+// IdentityAssertion signs the user for the service the request goes to, so the service can trust it without
+// trusting the network. It returns "" when no assertion key is configured.
+func (s *authService) IdentityAssertion(c *gin.Context, user responseDto.UserResponseV2, claims map[string]any, audience string) (string, error) {
+	if s.assertions == nil {
+		return "", nil
+	}
+
+	asserted := identity.Identity{
+		UserID:     strconv.FormatUint(uint64(user.ID), 10),
+		FirstName:  user.FirstName,
+		LastName:   user.LastName,
+		Email:      user.Email,
+		Roles:      user.Roles,
+		Scope:      user.Scope,
+		Actor:      assertedActor(user.Actor),
+		Attributes: user.Attributes,
+		Claims:     claims,
+	}
+
+	assertion, err := s.assertions.Sign(asserted, audience)
+	if err != nil {
+		return "", exception.NewInternal("Failed to sign identity assertion")
+	}
+	return assertion, nil
+}
+
+// assertedActor copies an actor chain into an assertion
+func assertedActor(actor *responseDto.ActorResponse) *identity.Actor {
+	if actor == nil {
+		return nil
+	}
+	return &identity.Actor{
+		ID:      actor.ID,
+		Email:   actor.Email,
+		Subject: actor.Subject,
+		Actor:   assertedActor(actor.Actor),
+	}
+}
+
+// AssertionKeys publishes the key identity assertions are verified with
+func (s *authService) AssertionKeys() identity.JWKS {
+	keys := identity.JWKS{Keys: []identity.JWK{}}
+	if s.assertions != nil {
+		keys.Keys = append(keys.Keys, identity.NewJWK(s.assertions.PublicKey(), s.assertions.KeyID()))
+	}
+	return keys
+}
+
 func (s *authService) Logout(c *gin.Context, authToken string) error {
 	authToken = strings.TrimSpace(authToken)
 	if authToken == "" {
 		return exception.NewUnauthorizedBusinessException("Authorization token is required")
 	}
 
-	secret := config.LoadConfig().JwtSecret
-	_, err := verifyToken(authToken, secret)
-	if err != nil {
-		return err
+	// Opaque tokens only exist as their session, which is deleted below
+	if !isOpaqueToken(authToken) {
+		cfg := config.LoadConfig()
+		claims, err := s.tokens.Verify(authToken, "")
+		if err != nil {
+			return err
+		}
+
+		// Deny the token everywhere, so instances verifying statelessly reject it too
+		if jti, _ := claims["jti"].(string); jti != "" {
+			exp, _ := claims["exp"].(float64)
+			if err := s.revocations.RevokeToken(c.Request.Context(), jti, time.Unix(int64(exp), 0)); err != nil {
+				return exception.NewInternal("Failed to revoke token")
+			}
+		}
+		if cfg.TokenMode == config.TokenModeStateless {
+			return nil
+		}
 	}
 
-	deleted, err := redis.Rdb.Del(redis.Ctx, authToken).Result()
+	// Loading first moves a session still stored under the raw token to where Delete looks
+	_, _, err := s.loadSession(c.Request.Context(), authToken)
+	if err != nil && err != session.ErrNotFound {
+		return exception.NewInternal("Failed to delete token")
+	}
+
+	deleted, err := s.sessions.Delete(c.Request.Context(), session.TokenKey(authToken))
 	if err != nil {
 		return exception.NewInternal("Failed to delete token")
 	}
 
-	if deleted == 0 {
+	if !deleted {
 		return exception.NewNotFound("Token not found")
 	}
 
 	return nil
 }
 
-func (s *authService) EnforceAuthorization(c *gin.Context, userEmail string, service string, path string, httpMethod string) error {
+// ListSessions returns the user's active sessions, without those of exchanged tokens
+func (s *authService) ListSessions(c *gin.Context, userEmail string) ([]model.Session, error) {
+	user, err := s.userRepo.FindByEmail(strings.TrimSpace(userEmail))
+	if err != nil {
+		return nil, exception.NewUnauthorizedBusinessException("User not found")
+	}
+
+	entries, err := s.sessions.ListByUser(c.Request.Context(), user.ID)
+	if err != nil {
+		return nil, exception.NewInternal("Failed to load sessions")
+	}
+	entries = signInSessions(entries)
+
+	sessions := make([]model.Session, len(entries))
+	for i, entry := range entries {
+		sessions[i] = entry.Record.Session
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+	return sessions, nil
+}
+
+// RevokeSession ends one of the user's sessions by its id
+func (s *authService) RevokeSession(c *gin.Context, userEmail string, sessionID string) error {
+	user, err := s.userRepo.FindByEmail(strings.TrimSpace(userEmail))
+	if err != nil {
+		return exception.NewUnauthorizedBusinessException("User not found")
+	}
+
+	notFound := exception.NewNotFound("Session not found")
+
+	ctx := c.Request.Context()
+	key, err := s.sessions.FindKey(ctx, sessionID)
+	if err == session.ErrNotFound {
+		return notFound
+	}
+	if err != nil {
+		return exception.NewInternal("Failed to revoke session")
+	}
+
+	record, err := s.sessions.Get(ctx, key)
+	if err == session.ErrNotFound {
+		return notFound
+	}
+	if err != nil {
+		return exception.NewInternal("Failed to revoke session")
+	}
+
+	// Never reveal whether a session id of another user exists
+	if record.Session.UserID != user.ID {
+		return notFound
+	}
+
+	if _, err := s.sessions.Delete(ctx, key); err != nil {
+		return exception.NewInternal("Failed to revoke session")
+	}
+
+	return nil
+}
+
+// LogoutAll signs the user out of every device, including the current one
+func (s *authService) LogoutAll(c *gin.Context, userEmail string) error {
+	user, err := s.userRepo.FindByEmail(strings.TrimSpace(userEmail))
+	if err != nil {
+		return exception.NewUnauthorizedBusinessException("User not found")
+	}
+
+	return s.RevokeAllTokens(c.Request.Context(), user.ID, &user.ID, "logout_all")
+}
+
+// ForceLogout lets an admin end every session of any user
+func (s *authService) ForceLogout(c *gin.Context, actorEmail string, userID uint, reason string) error {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return exception.NewNotFound("User not found")
+	}
+
+	if reason == "" {
+		reason = "force_logout"
+	}
+	return s.RevokeAllTokens(c.Request.Context(), userID, &actor.ID, reason)
+}
+
+// ChangePassword replaces the password, revokes every existing token and returns a fresh one for the caller
+func (s *authService) ChangePassword(c *gin.Context, userEmail string, req requestDTO.ChangePasswordRequest) (string, error) {
+	user, err := s.userRepo.FindByEmail(strings.TrimSpace(userEmail))
+	if err != nil {
+		return "", exception.NewUnauthorizedBusinessException("User not found")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)); err != nil {
+		return "", exception.NewUnauthorizedBusinessException("Current password is incorrect")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return "", exception.ErrInternal
+	}
+
+	if err := s.userRepo.UpdatePassword(user.ID, string(hashedPassword)); err != nil {
+		return "", exception.NewInternal("Failed to update password")
+	}
+
+	s.auditService.Record(c.Request.Context(), AuditPasswordChanged, &user.ID, AuditTargetUser, strconv.FormatUint(uint64(user.ID), 10), nil)
+
+	if err := s.RevokeAllTokens(c.Request.Context(), user.ID, &user.ID, "password_changed"); err != nil {
+		return "", err
+	}
+
+	// Reload to pick up the new token generation
+	user, err = s.userRepo.FindByID(user.ID)
+	if err != nil {
+		return "", exception.ErrInternal
+	}
+
+	roles, err := s.roleRepo.FindActiveByUserID(user.ID, time.Now())
+	if err != nil {
+		return "", exception.ErrInternal
+	}
+
+	var roleNames []string
+	for _, r := range roles {
+		roleNames = append(roleNames, r.Name)
+	}
+
+	return s.issueToken(c, user, roleNames, tokenOptions{ttl: config.LoadConfig().SessionMaxLifetime})
+}
+
+// RevokeAllTokens bumps the user's token generation, which invalidates every token issued so far, and deletes their sessions
+func (s *authService) RevokeAllTokens(ctx context.Context, userID uint, actorID *uint, reason string) error {
+	return NewTokenRevoker(s.userRepo, s.sessions, s.revocations, s.auditService).RevokeAllTokens(ctx, userID, actorID, reason)
+}
+
+// checkTokenGeneration rejects tokens issued before the user's last sign-out-everywhere, password or role change
+func (s *authService) checkTokenGeneration(claims token.Claims) error {
+	email, _ := claims["email"].(string)
+	tokenGeneration, _ := claims["gen"].(float64) // Absent in tokens issued before generations existed
+
+	return s.checkGeneration(email, int(tokenGeneration))
+}
+
+// checkGeneration rejects a token issued under an older token generation of the user
+func (s *authService) checkGeneration(email string, tokenGeneration int) error {
+	generation, err := s.userRepo.GetTokenGeneration(email)
+	if err != nil {
+		return exception.NewUnauthorizedBusinessException("Token not valid or expired")
+	}
+	if tokenGeneration != generation {
+		return exception.NewUnauthorizedBusinessException("Token has been revoked")
+	}
+	return nil
+}
+
+// verifyStateless accepts a valid token unless the denylist revoked it, and builds the session record from its claims
+func (s *authService) verifyStateless(c *gin.Context, claims token.Claims) (string, error) {
+	jti, _ := claims["jti"].(string)
+	subject, _ := claims["sub"].(string)
+	userID, err := strconv.ParseUint(subject, 10, 64)
+	if jti == "" || err != nil {
+		// Issued before stateless mode existed
+		return "", exception.NewUnauthorizedBusinessException("Token not valid or expired")
+	}
+
+	generation, _ := claims["gen"].(float64)
+	if s.revocations.IsRevoked(jti, uint(userID), int(generation)) {
+		return "", exception.NewUnauthorizedBusinessException("Token has been revoked")
+	}
+
+	record := recordFromClaims(claims, uint(userID))
+	if err := checkCertificateBinding(c, record); err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", exception.ErrInternal
+	}
+	return string(data), nil
+}
+
+// verifyPersonalAccessToken accepts an active personal access token and builds its session record with the owner's
+// current roles and the token's scope. Its last use is written back at most once per session touch interval.
+func (s *authService) verifyPersonalAccessToken(c *gin.Context, authToken string) (string, error) {
+	pat, err := s.personalAccessTokens.FindByTokenHash(hashSecret(authToken))
+	if err != nil {
+		return "", exception.NewUnauthorizedBusinessException("Token not valid or expired")
+	}
+
+	now := time.Now()
+	if pat.RevokedAt != nil {
+		return "", exception.NewUnauthorizedBusinessException("Token has been revoked")
+	}
+	if !pat.IsActiveAt(now) {
+		return "", exception.NewUnauthorizedBusinessException("Token not valid or expired")
+	}
+
+	user, err := s.userRepo.FindByID(pat.UserID)
+	if err != nil {
+		return "", exception.NewUnauthorizedBusinessException("Token not valid or expired")
+	}
+	// Signing out everywhere, changing the password or any other RevokeAllTokens ends personal access tokens too
+	if err := s.checkGeneration(user.Email, pat.Generation); err != nil {
+		return "", err
+	}
+	roles, err := s.roleRepo.FindActiveByUserID(user.ID, now)
+	if err != nil {
+		return "", exception.ErrInternal
+	}
+	var roleNames []string
+	for _, r := range roles {
+		roleNames = append(roleNames, r.Name)
+	}
+	attributes, err := s.attributes.Exposed(user)
+	if err != nil {
+		return "", err
+	}
+
+	if pat.LastUsedAt == nil || now.Sub(*pat.LastUsedAt) >= config.LoadConfig().SessionTouchInterval {
+		if err := s.personalAccessTokens.Touch(pat.PersonalAccessTokenID, now); err != nil {
+			slog.WarnContext(c.Request.Context(), "failed to record personal access token use",
+				"tokenId", pat.PersonalAccessTokenID,
+				"error", err,
+			)
+		}
+	}
+
+	data, err := json.Marshal(session.Record{
+		User: responseDto.UserResponse{
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Email:     user.Email,
+			Roles:     strings.Join(roleNames, "|"),
+			Scope:     pat.Permissions(),
+		},
+		Session: model.Session{
+			UserID:     user.ID,
+			CreatedAt:  pat.CreatedAt,
+			LastSeenAt: now,
+			ExpiresAt:  pat.ExpiresAt,
+		},
+		Attributes: attributes,
+	})
+	if err != nil {
+		return "", exception.ErrInternal
+	}
+	return string(data), nil
+}
+
+// EnforceAuthorization checks the user's permissions against the endpoint. A non-nil scope, the scope of the presented
+// token, additionally limits the user to the permissions it names.
+func (s *authService) EnforceAuthorization(c *gin.Context, userEmail string, scope []string, service string, path string, httpMethod string) error {
 	/*
 		Get user roles
 	*/
@@ -177,7 +1005,11 @@ func (s *authService) EnforceAuthorization(c *gin.Context, userEmail string, ser
 	if err != nil {
 		return exception.NewUnauthorizedBusinessException("Invalid email or password")
 	}
-	roleIds := extractRoleIDs(user.Roles)
+	roles, err := s.roleRepo.FindActiveByUserID(user.ID, time.Now())
+	if err != nil {
+		return exception.ErrInternal
+	}
+	roleIds := extractRoleIDs(roles)
 
 	/*
 		Check endpoint in DB and extract the needed permission to access the endpoint
@@ -200,36 +1032,44 @@ func (s *authService) EnforceAuthorization(c *gin.Context, userEmail string, ser
 	if !isAllowed {
 		return exception.NewUnauthorizedBusinessException("User has no permission to access this endpoint")
 	}
+
+	/*
+		Scoped tokens, e.g. exchanged ones, only reach endpoints within their scope
+	*/
+	if scope != nil && !slices.Contains(scope, "ALL") && !slices.Contains(scope, requiredPermission.Name) {
+		return exception.New(http.StatusForbidden, "INSUFFICIENT_SCOPE", "Token scope does not cover this endpoint")
+	}
 	return nil
 }
 
-func verifyToken(tokenString string, secret string) (jwt.MapClaims, error) {
-	// Parse token
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// Make sure the signing method is HMAC
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, exception.NewUnauthorizedBusinessException(
-				fmt.Sprintf("Unexpected signing method: %v", token.Header["alg"]),
-			)
-		}
-		return []byte(secret), nil
-	})
+// roleNameList never returns nil, so tokens of users without roles carry an empty list
+func roleNameList(roleNames []string) []string {
+	if roleNames == nil {
+		return []string{}
+	}
+	return roleNames
+}
 
-	if err != nil {
-		return nil, err
+// splitRoles turns the "|"-joined roles of the v1 user response into a list
+func splitRoles(roles string) []string {
+	if roles == "" {
+		return []string{}
 	}
+	return strings.Split(roles, "|")
+}
 
-	// Validate token
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		if exp, ok := claims["exp"].(float64); ok {
-			if time.Unix(int64(exp), 0).Before(time.Now()) {
-				return nil, exception.NewUnauthorizedBusinessException("Token expired")
-			}
+// permissionNames returns the sorted, de-duplicated names of the permissions
+func permissionNames(permissions []model.Permission) []string {
+	seen := make(map[string]bool, len(permissions))
+	names := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		if !seen[permission.Name] {
+			seen[permission.Name] = true
+			names = append(names, permission.Name)
 		}
-		return claims, nil
 	}
-
-	return nil, exception.NewUnauthorizedBusinessException("Token invalid")
+	sort.Strings(names)
+	return names
 }
 
 func extractRoleIDs(roles []model.Role) []int {
@@ -0,0 +1,780 @@
+package service
+
+import (
+	"auth-service/internal/clientcert"
+	"auth-service/internal/config"
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/request"
+	"auth-service/internal/model/dto/response"
+	"auth-service/internal/repository"
+	"auth-service/internal/session"
+	"auth-service/internal/token"
+	"auth-service/pkg/identity"
+	"auth-service/pkg/utils/exception"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// fakeRoleRepository returns the active roles configured per user
+type fakeRoleRepository struct {
+	repository.RoleRepository
+	roles map[uint][]model.Role
+}
+
+func (r *fakeRoleRepository) FindActiveByUserID(userID uint, at time.Time) ([]model.Role, error) {
+	return r.roles[userID], nil
+}
+
+func (r *fakeRoleRepository) FindByID(id uint) (model.Role, error) {
+	for _, roles := range r.roles {
+		for _, role := range roles {
+			if role.RoleID == id {
+				return role, nil
+			}
+		}
+	}
+	return model.Role{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeRoleRepository) GetPermissionsByRoleIds(ids []int) ([]model.Permission, error) {
+	var permissions []model.Permission
+	for _, roles := range r.roles {
+		for _, role := range roles {
+			if slices.Contains(ids, int(role.RoleID)) {
+				permissions = append(permissions, role.Permissions...)
+			}
+		}
+	}
+	return permissions, nil
+}
+
+// newTestAuthService wires the service to an in-memory session store and denylist, issuing JWTs
+func newTestAuthService(userRepo repository.UserRepository, roleRepo repository.RoleRepository, auditService AuditService) AuthService {
+	revocations := NewRevocationService(&fakeRevocationRepository{})
+	return NewAuthService(userRepo, roleRepo, nil, nil, session.NewMemoryStore(time.Minute), token.NewIssuer(token.Validator{}, token.NewJWTFormat("secret")), revocations, NewClaimMappingService(userRepo, &fakeClaimMappingRepository{}, auditService), NewAttributeService(userRepo, &fakeAttributeRepository{}, auditService), nil, auditService)
+}
+
+// errorCode returns the code of an application error, the message of any other error, or "" for nil
+func errorCode(err error) string {
+	var appErr *exception.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+func TestAuthServiceImpersonateRefused(t *testing.T) {
+	users := &fakeUserRepository{users: []model.User{
+		{ID: 1, Email: "admin@example.com"},
+		{ID: 2, Email: "other-admin@example.com"},
+		{ID: 3, Email: "user@example.com"},
+	}}
+	roles := &fakeRoleRepository{roles: map[uint][]model.Role{
+		1: {{Name: "ADMIN"}},
+		2: {{Name: "USER"}, {Name: "admin"}},
+		3: {{Name: "USER"}},
+	}}
+
+	tests := []struct {
+		name       string
+		actorEmail string
+		userID     uint
+		wantCode   string
+	}{
+		{"unknown actor", "ghost@example.com", 3, "UNAUTHORIZED"},
+		{"unknown target", "admin@example.com", 9, "NOT_FOUND"},
+		{"self", "admin@example.com", 1, "BAD_REQUEST"},
+		{"protected role in any case", "admin@example.com", 2, "IMPERSONATION_FORBIDDEN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audit := &fakeAuditService{}
+			s := newTestAuthService(users, roles, audit)
+
+			_, err := s.Impersonate(newTestContext(), tt.actorEmail, requestDTO.ImpersonateRequest{UserID: tt.userID, Reason: "support ticket"})
+			if got := errorCode(err); got != tt.wantCode {
+				t.Fatalf("Impersonate() = %q, want %q", got, tt.wantCode)
+			}
+			if len(audit.actions) != 0 {
+				t.Errorf("audited %v for a refused impersonation", audit.actions)
+			}
+		})
+	}
+}
+
+func TestAuthServiceRecordImpersonatedRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		user responseDto.UserResponse
+		want []string
+	}{
+		{"regular token", responseDto.UserResponse{Email: "user@example.com"}, nil},
+		{"impersonation token", responseDto.UserResponse{Email: "user@example.com", Actor: &responseDto.ActorResponse{ID: 1, Email: "admin@example.com"}}, []string{AuditImpersonatedRequest}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audit := &fakeAuditService{}
+			s := newTestAuthService(nil, nil, audit)
+
+			s.RecordImpersonatedRequest(newTestContext(), tt.user, "orders-service", "/orders", "GET")
+			if !slices.Equal(audit.actions, tt.want) {
+				t.Errorf("audited %v, want %v", audit.actions, tt.want)
+			}
+		})
+	}
+}
+
+// newTestUsers returns users with the password "secret", numbered from 1
+func newTestUsers(t *testing.T, emails ...string) []model.User {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users := make([]model.User, len(emails))
+	for i, email := range emails {
+		users[i] = model.User{ID: uint(i + 1), Email: email, Password: string(hash)}
+	}
+	return users
+}
+
+func loginAs(t *testing.T, s AuthService, email string, userAgent string) string {
+	t.Helper()
+
+	c := newTestContext()
+	c.Request.Header.Set("User-Agent", userAgent)
+	token, err := s.Login(c, email, "secret")
+	if err != nil {
+		t.Fatalf("Login(%s) error = %v", email, err)
+	}
+	return token
+}
+
+func TestAuthServiceSessions(t *testing.T) {
+	t.Setenv("SESSION_TOUCH_INTERVAL", "0s")
+
+	users := newTestUsers(t, "ada@example.com", "bob@example.com")
+	s := newTestAuthService(&fakeUserRepository{users: users}, &fakeRoleRepository{}, &fakeAuditService{})
+
+	laptop := loginAs(t, s, "ada@example.com", "laptop")
+	phone := loginAs(t, s, "ada@example.com", "phone")
+	loginAs(t, s, "bob@example.com", "desktop")
+
+	// Using the laptop token makes it the most recently seen session
+	if _, err := s.Verify(newTestContext(), laptop, ""); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	sessions, err := s.ListSessions(newTestContext(), "ada@example.com")
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+	var userAgents []string
+	for _, session := range sessions {
+		if session.UserID != users[0].ID {
+			t.Errorf("ListSessions() returned a session of user %d", session.UserID)
+		}
+		userAgents = append(userAgents, session.UserAgent)
+	}
+	if want := []string{"laptop", "phone"}; !slices.Equal(userAgents, want) {
+		t.Fatalf("ListSessions() user agents = %v, want %v", userAgents, want)
+	}
+	phoneSession := sessions[1].ID
+
+	tests := []struct {
+		name      string
+		userEmail string
+		sessionID string
+		wantCode  string
+	}{
+		{"unknown session", "ada@example.com", "0123abcd", "NOT_FOUND"},
+		{"session of another user", "bob@example.com", phoneSession, "NOT_FOUND"},
+		{"own session", "ada@example.com", phoneSession, ""},
+		{"already revoked session", "ada@example.com", phoneSession, "NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.RevokeSession(newTestContext(), tt.userEmail, tt.sessionID)
+			if got := errorCode(err); got != tt.wantCode {
+				t.Errorf("RevokeSession() = %q, want %q", got, tt.wantCode)
+			}
+		})
+	}
+
+	if _, err := s.Verify(newTestContext(), phone, ""); err == nil {
+		t.Error("Verify() accepted the token of a revoked session")
+	}
+	if _, err := s.Verify(newTestContext(), laptop, ""); err != nil {
+		t.Errorf("Verify() rejected the token of a live session: %v", err)
+	}
+}
+
+func TestAuthServiceCheckTokenGeneration(t *testing.T) {
+	users := &fakeUserRepository{users: []model.User{
+		{ID: 1, Email: "ada@example.com", TokenGeneration: 2},
+		{ID: 2, Email: "bob@example.com"},
+	}}
+	s := newTestAuthService(users, nil, &fakeAuditService{}).(*authService)
+
+	tests := []struct {
+		name   string
+		claims token.Claims
+		valid  bool
+	}{
+		{"current generation", token.Claims{"email": "ada@example.com", "gen": float64(2)}, true},
+		{"older generation", token.Claims{"email": "ada@example.com", "gen": float64(1)}, false},
+		{"token issued before generations of a user who never signed out", token.Claims{"email": "bob@example.com"}, true},
+		{"token issued before generations of a user who signed out", token.Claims{"email": "ada@example.com"}, false},
+		{"unknown user", token.Claims{"email": "ghost@example.com", "gen": float64(0)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.checkTokenGeneration(tt.claims); (err == nil) != tt.valid {
+				t.Errorf("checkTokenGeneration() error = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestAuthServiceRevokeAllTokens(t *testing.T) {
+	tests := []struct {
+		name   string
+		revoke func(s AuthService, userID uint) (string, error)
+	}{
+		{"logout everywhere", func(s AuthService, userID uint) (string, error) {
+			return "", s.LogoutAll(newTestContext(), "ada@example.com")
+		}},
+		{"forced by an admin", func(s AuthService, userID uint) (string, error) {
+			return "", s.ForceLogout(newTestContext(), "admin@example.com", userID, "")
+		}},
+		{"password change", func(s AuthService, userID uint) (string, error) {
+			return s.ChangePassword(newTestContext(), "ada@example.com", requestDTO.ChangePasswordRequest{CurrentPassword: "secret", NewPassword: "new secret"})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := newTestUsers(t, "ada@example.com", "admin@example.com")
+			audit := &fakeAuditService{}
+			s := newTestAuthService(&fakeUserRepository{users: users}, &fakeRoleRepository{}, audit)
+
+			laptop := loginAs(t, s, "ada@example.com", "laptop")
+			phone := loginAs(t, s, "ada@example.com", "phone")
+
+			fresh, err := tt.revoke(s, users[0].ID)
+			if err != nil {
+				t.Fatalf("revoke error = %v", err)
+			}
+
+			for _, token := range []string{laptop, phone} {
+				if _, err := s.Verify(newTestContext(), token, ""); err == nil {
+					t.Error("Verify() accepted a token issued before the revocation")
+				}
+			}
+			if !slices.Contains(audit.actions, AuditTokensRevoked) {
+				t.Errorf("audited %v, want %s", audit.actions, AuditTokensRevoked)
+			}
+
+			if fresh != "" {
+				if _, err := s.Verify(newTestContext(), fresh, ""); err != nil {
+					t.Errorf("Verify() rejected the token issued with the new password: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthServiceIdleTimeout(t *testing.T) {
+	t.Setenv("SESSION_IDLE_TIMEOUT", "400ms")
+	t.Setenv("SESSION_TOUCH_INTERVAL", "0s")
+
+	s := newTestAuthService(&fakeUserRepository{users: newTestUsers(t, "ada@example.com")}, &fakeRoleRepository{}, &fakeAuditService{})
+	token := loginAs(t, s, "ada@example.com", "laptop")
+
+	// Each use slides the idle deadline, so the session outlives the timeout while it stays active
+	for range 2 {
+		time.Sleep(250 * time.Millisecond)
+		if _, err := s.Verify(newTestContext(), token, ""); err != nil {
+			t.Fatalf("Verify() of an active session error = %v", err)
+		}
+	}
+
+	time.Sleep(500 * time.Millisecond)
+	if _, err := s.Verify(newTestContext(), token, ""); err == nil {
+		t.Error("Verify() accepted an idle session")
+	}
+}
+
+func TestSessionLimit(t *testing.T) {
+	limit := func(n int) *int { return &n }
+
+	tests := []struct {
+		name  string
+		roles []model.Role
+		want  int
+	}{
+		{"no roles", nil, 5},
+		{"roles without override", []model.Role{{Name: "USER"}}, 5},
+		{"override", []model.Role{{Name: "USER"}, {Name: "KIOSK", MaxSessions: limit(1)}}, 1},
+		{"override above the global limit", []model.Role{{Name: "SUPPORT", MaxSessions: limit(10)}}, 10},
+		{"strictest override", []model.Role{{Name: "SUPPORT", MaxSessions: limit(10)}, {Name: "KIOSK", MaxSessions: limit(2)}}, 2},
+		{"unlimited override", []model.Role{{Name: "SERVICE", MaxSessions: limit(0)}}, 0},
+		{"limited override beats unlimited", []model.Role{{Name: "SERVICE", MaxSessions: limit(0)}, {Name: "KIOSK", MaxSessions: limit(2)}}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sessionLimit(5, tt.roles); got != tt.want {
+				t.Errorf("sessionLimit() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthServiceSessionLimit(t *testing.T) {
+	t.Setenv("SESSION_MAX_CONCURRENT", "2")
+
+	tests := []struct {
+		name        string
+		policy      string
+		wantCode    string
+		wantEvicted []string
+	}{
+		{"evict oldest", config.SessionLimitEvictOldest, "", []string{AuditSessionEvicted}},
+		{"reject", config.SessionLimitReject, "SESSION_LIMIT_EXCEEDED", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SESSION_LIMIT_POLICY", tt.policy)
+
+			audit := &fakeAuditService{}
+			s := newTestAuthService(&fakeUserRepository{users: newTestUsers(t, "ada@example.com")}, &fakeRoleRepository{}, audit)
+
+			oldest := loginAs(t, s, "ada@example.com", "laptop")
+			newer := loginAs(t, s, "ada@example.com", "phone")
+
+			token, err := s.Login(newTestContext(), "ada@example.com", "secret")
+			if got := errorCode(err); got != tt.wantCode {
+				t.Fatalf("Login() = %q, want %q", got, tt.wantCode)
+			}
+			if err == nil && token == "" {
+				t.Error("Login() returned no token")
+			}
+
+			_, err = s.Verify(newTestContext(), oldest, "")
+			if evicted := err != nil; evicted != (tt.wantEvicted != nil) {
+				t.Errorf("oldest session evicted = %v", evicted)
+			}
+			if _, err := s.Verify(newTestContext(), newer, ""); err != nil {
+				t.Errorf("Verify() rejected the newer session: %v", err)
+			}
+			if !slices.Equal(audit.actions, tt.wantEvicted) {
+				t.Errorf("audited %v, want %v", audit.actions, tt.wantEvicted)
+			}
+		})
+	}
+}
+
+func TestAuthServiceStatelessRevocation(t *testing.T) {
+	t.Setenv("TOKEN_MODE", config.TokenModeStateless)
+
+	s := newTestAuthService(&fakeUserRepository{users: newTestUsers(t, "ada@example.com")}, &fakeRoleRepository{}, &fakeAuditService{})
+
+	laptop := loginAs(t, s, "ada@example.com", "laptop")
+	phone := loginAs(t, s, "ada@example.com", "phone")
+	tablet := loginAs(t, s, "ada@example.com", "tablet")
+
+	if err := s.Logout(newTestContext(), laptop); err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+	if _, err := s.Verify(newTestContext(), laptop, ""); err == nil {
+		t.Error("Verify() accepted a logged out token")
+	}
+	if _, err := s.Verify(newTestContext(), phone, ""); err != nil {
+		t.Errorf("Verify() rejected a token that was not logged out: %v", err)
+	}
+
+	if err := s.LogoutAll(newTestContext(), "ada@example.com"); err != nil {
+		t.Fatalf("LogoutAll() error = %v", err)
+	}
+	for _, token := range []string{phone, tablet} {
+		if _, err := s.Verify(newTestContext(), token, ""); err == nil {
+			t.Error("Verify() accepted a token issued before LogoutAll()")
+		}
+	}
+
+	// Tokens of the next generation are not affected by the cutoff
+	fresh := loginAs(t, s, "ada@example.com", "laptop")
+	if _, err := s.Verify(newTestContext(), fresh, ""); err != nil {
+		t.Errorf("Verify() rejected a token issued after LogoutAll(): %v", err)
+	}
+}
+
+// legacySessionStore is a memory store that also holds sessions under their raw token, recording every lookup there
+type legacySessionStore struct {
+	session.Store
+	legacy  map[string]session.Record
+	lookups []string
+}
+
+func (s *legacySessionStore) MigrateToken(ctx context.Context, authToken string) (session.Record, error) {
+	s.lookups = append(s.lookups, authToken)
+	record, ok := s.legacy[authToken]
+	if !ok {
+		return session.Record{}, session.ErrNotFound
+	}
+	delete(s.legacy, authToken)
+	return record, s.Save(ctx, session.TokenKey(authToken), record, time.Minute)
+}
+
+func TestAuthServiceLoadSessionMigratesLegacyTokens(t *testing.T) {
+	const legacyToken = "eyJhbGciOiJIUzI1NiJ9.eyJlbWFpbCI6ImFkYUBleGFtcGxlLmNvbSJ9.sig"
+	record := session.Record{User: responseDto.UserResponse{Email: "ada@example.com"}}
+
+	store := &legacySessionStore{Store: session.NewMemoryStore(time.Minute), legacy: map[string]session.Record{legacyToken: record}}
+	s := &authService{sessions: store}
+
+	_, got, err := s.loadSession(context.Background(), legacyToken)
+	if err != nil || got.User.Email != "ada@example.com" {
+		t.Fatalf("loadSession() = %+v, %v", got, err)
+	}
+	if _, err := store.Get(context.Background(), session.TokenKey(legacyToken)); err != nil {
+		t.Errorf("legacy session not moved to the hashed key: %v", err)
+	}
+
+	opaque, err := newOpaqueToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.loadSession(context.Background(), opaque); err != session.ErrNotFound {
+		t.Errorf("loadSession() of an unknown opaque token error = %v, want ErrNotFound", err)
+	}
+	if !slices.Equal(store.lookups, []string{legacyToken}) {
+		t.Errorf("raw-token lookups = %v, want only the legacy JWT", store.lookups)
+	}
+}
+
+func TestOpaqueToken(t *testing.T) {
+	token, err := newOpaqueToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := newOpaqueToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !isOpaqueToken(token) || len(token) != len(opaqueTokenPrefix)+43 {
+		t.Errorf("newOpaqueToken() = %q, want %s followed by 256 bits in base64url", token, opaqueTokenPrefix)
+	}
+	if token == other {
+		t.Error("newOpaqueToken() returned the same token twice")
+	}
+	if isOpaqueToken("eyJhbGciOiJIUzI1NiJ9.e30.sig") {
+		t.Error("isOpaqueToken() = true for a JWT")
+	}
+}
+
+func TestAuthServiceOpaqueTokens(t *testing.T) {
+	for _, mode := range []string{config.TokenModeSession, config.TokenModeStateless} {
+		t.Run(mode, func(t *testing.T) {
+			t.Setenv("TOKEN_MODE", mode)
+			s := newTestAuthService(&fakeUserRepository{users: newTestUsers(t, "ada@example.com")}, &fakeRoleRepository{}, &fakeAuditService{})
+
+			// A JWT issued before switching formats stays valid
+			jwtToken := loginAs(t, s, "ada@example.com", "laptop")
+			t.Setenv("TOKEN_FORMAT", config.TokenFormatOpaque)
+			opaque := loginAs(t, s, "ada@example.com", "phone")
+			other := loginAs(t, s, "ada@example.com", "tablet")
+
+			if !isOpaqueToken(opaque) {
+				t.Fatalf("Login() = %q, want an opaque token", opaque)
+			}
+			for _, token := range []string{jwtToken, opaque} {
+				data, err := s.Verify(newTestContext(), token, "")
+				if err != nil {
+					t.Fatalf("Verify() error = %v", err)
+				}
+				if !strings.Contains(data, `"email":"ada@example.com"`) {
+					t.Errorf("Verify() = %s, want the user of the token", data)
+				}
+			}
+
+			if err := s.Logout(newTestContext(), opaque); err != nil {
+				t.Fatalf("Logout() error = %v", err)
+			}
+			if _, err := s.Verify(newTestContext(), opaque, ""); err == nil {
+				t.Error("Verify() accepted a logged out opaque token")
+			}
+
+			if err := s.LogoutAll(newTestContext(), "ada@example.com"); err != nil {
+				t.Fatalf("LogoutAll() error = %v", err)
+			}
+			if _, err := s.Verify(newTestContext(), other, ""); err == nil {
+				t.Error("Verify() accepted an opaque token issued before LogoutAll()")
+			}
+		})
+	}
+}
+
+func TestAuthServiceVerifiedUser(t *testing.T) {
+	users := newTestUsers(t, "ada@example.com", "bob@example.com")
+	users[0].FirstName, users[0].LastName = "Ada", "Lovelace"
+	roles := &fakeRoleRepository{roles: map[uint][]model.Role{
+		1: {
+			{RoleID: 1, Name: "ADMIN", Permissions: []model.Permission{{Name: "WRITE_ORDERS"}, {Name: "READ_ORDERS"}}},
+			{RoleID: 2, Name: "USER", Permissions: []model.Permission{{Name: "READ_ORDERS"}}},
+		},
+	}}
+
+	tests := []struct {
+		name            string
+		mode            string
+		email           string
+		withPermissions bool
+		want            string
+	}{
+		{
+			name:  "session token",
+			mode:  config.TokenModeSession,
+			email: "ada@example.com",
+			want:  `{"id":1,"first_name":"Ada","last_name":"Lovelace","email":"ada@example.com","roles":["ADMIN","USER"]}`,
+		},
+		{
+			name:            "session token with permissions",
+			mode:            config.TokenModeSession,
+			email:           "ada@example.com",
+			withPermissions: true,
+			want:            `{"id":1,"first_name":"Ada","last_name":"Lovelace","email":"ada@example.com","roles":["ADMIN","USER"],"permissions":["READ_ORDERS","WRITE_ORDERS"]}`,
+		},
+		{
+			name:  "stateless token",
+			mode:  config.TokenModeStateless,
+			email: "ada@example.com",
+			want:  `{"id":1,"first_name":"Ada","last_name":"Lovelace","email":"ada@example.com","roles":["ADMIN","USER"]}`,
+		},
+		{
+			name:  "user without roles",
+			mode:  config.TokenModeSession,
+			email: "bob@example.com",
+			want:  `{"id":2,"first_name":"","last_name":"","email":"bob@example.com","roles":[]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TOKEN_MODE", tt.mode)
+			s := newTestAuthService(&fakeUserRepository{users: users}, roles, &fakeAuditService{})
+
+			data, err := s.Verify(newTestContext(), loginAs(t, s, tt.email, "laptop"), "")
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			user, err := s.VerifiedUser(newTestContext(), data, tt.withPermissions)
+			if err != nil {
+				t.Fatalf("VerifiedUser() error = %v", err)
+			}
+
+			got, err := json.Marshal(user)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("VerifiedUser() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaimRoles(t *testing.T) {
+	tests := []struct {
+		name  string
+		roles any
+		want  string
+	}{
+		{"list", []any{"ADMIN", "USER"}, "ADMIN|USER"},
+		{"empty list", []any{}, ""},
+		{"joined string of older tokens", "ADMIN|USER", "ADMIN|USER"},
+		{"missing", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := claimRoles(tt.roles); got != tt.want {
+				t.Errorf("claimRoles() = %q, want %q", got, tt.want)
+			}
+			if got := strings.Join(splitRoles(tt.want), "|"); got != tt.want {
+				t.Errorf("splitRoles() does not reverse claimRoles(): %q", got)
+			}
+		})
+	}
+}
+
+func TestAuthServiceIdentityAssertion(t *testing.T) {
+	user := responseDto.UserResponseV2{
+		ID:         7,
+		FirstName:  "Ada",
+		LastName:   "Lovelace",
+		Email:      "ada@example.com",
+		Roles:      []string{"ADMIN"},
+		Attributes: map[string]any{"department": "Finance"},
+		Actor:      &responseDto.ActorResponse{ID: 1, Email: "admin@example.com"},
+	}
+	claims := map[string]any{"tenant": "acme"}
+
+	unsigned := newTestAuthService(&fakeUserRepository{}, &fakeRoleRepository{}, &fakeAuditService{})
+	if assertion, err := unsigned.IdentityAssertion(newTestContext(), user, claims, "orders-service"); assertion != "" || err != nil {
+		t.Fatalf("IdentityAssertion() without a key = %q, %v; want no assertion", assertion, err)
+	}
+	if keys := unsigned.AssertionKeys(); len(keys.Keys) != 0 {
+		t.Errorf("AssertionKeys() without a key = %v", keys)
+	}
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := identity.NewSigner(privateKey, "k1", "auth-service", time.Minute)
+	revocations := NewRevocationService(&fakeRevocationRepository{})
+	s := NewAuthService(&fakeUserRepository{}, &fakeRoleRepository{}, nil, nil, session.NewMemoryStore(time.Minute), token.NewIssuer(token.Validator{}, token.NewJWTFormat("secret")), revocations, nil, nil, signer, &fakeAuditService{})
+
+	assertion, err := s.IdentityAssertion(newTestContext(), user, claims, "orders-service")
+	if err != nil {
+		t.Fatalf("IdentityAssertion() error = %v", err)
+	}
+
+	keys := s.AssertionKeys()
+	data, err := json.Marshal(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKeys, err := identity.KeysFromJWKS(data)
+	if err != nil {
+		t.Fatalf("KeysFromJWKS() error = %v", err)
+	}
+	verifier, err := identity.NewVerifier(identity.Config{Audience: "orders-service", Issuer: "auth-service", PublicKeys: publicKeys})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issued, err := verifier.Verify(assertion)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	want := identity.Identity{
+		UserID:     "7",
+		FirstName:  "Ada",
+		LastName:   "Lovelace",
+		Email:      "ada@example.com",
+		Roles:      []string{"ADMIN"},
+		Actor:      &identity.Actor{ID: 1, Email: "admin@example.com"},
+		Attributes: map[string]any{"department": "Finance"},
+		Claims:     map[string]any{"tenant": "acme"},
+	}
+	if !reflect.DeepEqual(issued.Identity, want) {
+		t.Errorf("asserted identity = %+v, want %+v", issued.Identity, want)
+	}
+
+	other, err := identity.NewVerifier(identity.Config{Audience: "billing-service", Issuer: "auth-service", PublicKeys: publicKeys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Verify(assertion); err == nil {
+		t.Error("assertion for orders-service verified for billing-service")
+	}
+}
+
+func TestAuthServiceCertificateBoundTokens(t *testing.T) {
+	t.Setenv("CERTIFICATE_BOUND_TOKENS", "true")
+
+	billing := clientcert.Certificate{Fingerprint: strings.Repeat("ab", 32)}
+	reports := clientcert.Certificate{Fingerprint: strings.Repeat("cd", 32)}
+	withCertificate := func(cert *clientcert.Certificate) *gin.Context {
+		c := newTestContext()
+		if cert != nil {
+			c.Set(clientcert.ContextKey, *cert)
+		}
+		return c
+	}
+
+	for _, mode := range []string{config.TokenModeSession, config.TokenModeStateless} {
+		t.Run(mode, func(t *testing.T) {
+			t.Setenv("TOKEN_MODE", mode)
+			s := newTestAuthService(&fakeUserRepository{users: newTestUsers(t, "ada@example.com")}, &fakeRoleRepository{}, &fakeAuditService{})
+
+			bound, err := s.Login(withCertificate(&billing), "ada@example.com", "secret")
+			if err != nil {
+				t.Fatalf("Login() error = %v", err)
+			}
+			unbound := loginAs(t, s, "ada@example.com", "laptop")
+
+			tests := []struct {
+				name     string
+				token    string
+				cert     *clientcert.Certificate
+				wantCode string
+			}{
+				{"bound token with its certificate", bound, &billing, ""},
+				{"bound token with another certificate", bound, &reports, "CERTIFICATE_MISMATCH"},
+				{"bound token without a certificate", bound, nil, "CERTIFICATE_MISMATCH"},
+				{"unbound token without a certificate", unbound, nil, ""},
+				{"unbound token with a certificate", unbound, &reports, ""},
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					_, err := s.Verify(withCertificate(tt.cert), tt.token, "")
+					if code := errorCode(err); code != tt.wantCode {
+						t.Errorf("Verify() code = %q, want %q", code, tt.wantCode)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestScopedPermissions(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []string
+		scope       []string
+		want        []string
+	}{
+		{"unscoped token", []string{"VIEW_REPORTS", "EDIT_REPORTS"}, nil, []string{"VIEW_REPORTS", "EDIT_REPORTS"}},
+		{"scoped token", []string{"VIEW_REPORTS", "EDIT_REPORTS"}, []string{"VIEW_REPORTS"}, []string{"VIEW_REPORTS"}},
+		{"scope no longer held", []string{"VIEW_REPORTS"}, []string{"VIEW_REPORTS", "EDIT_REPORTS"}, []string{"VIEW_REPORTS"}},
+		{"scope covering everything", []string{"VIEW_REPORTS"}, []string{"ALL"}, []string{"VIEW_REPORTS"}},
+		{"user holding everything", []string{"ALL"}, []string{"VIEW_REPORTS"}, []string{"VIEW_REPORTS"}},
+		{"empty scope", []string{"VIEW_REPORTS"}, []string{}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scopedPermissions(tt.permissions, tt.scope); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scopedPermissions(%v, %v) = %v, want %v", tt.permissions, tt.scope, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,377 @@
+package service
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/request"
+	"auth-service/internal/repository"
+	"auth-service/pkg/utils/exception"
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type BreakGlassService interface {
+	CreateAccount(ctx context.Context, email string, firstName string, lastName string) (string, error)
+	SealCode(ctx context.Context, email string) (string, error)
+	Unseal(c *gin.Context, req requestDTO.UnsealBreakGlassRequest) (model.BreakGlassActivation, error)
+	RequestActivation(c *gin.Context, actorEmail string, req requestDTO.BreakGlassActivationRequest) (model.BreakGlassActivation, error)
+	ApproveActivation(c *gin.Context, actorEmail string, id uint) (model.BreakGlassActivation, error)
+	ListActivations(c *gin.Context) ([]model.BreakGlassActivation, error)
+	Disable(c *gin.Context, actorEmail string, userID uint) error
+	SyncActivations(ctx context.Context) error
+}
+
+type breakGlassService struct {
+	userRepo       repository.UserRepository
+	breakGlassRepo repository.BreakGlassRepository
+	auditService   AuditService
+	tokenRevoker   TokenRevoker
+	window         time.Duration
+}
+
+// NewBreakGlassService creates the service. window is how long an activation keeps the account enabled.
+func NewBreakGlassService(userRepo repository.UserRepository, breakGlassRepo repository.BreakGlassRepository, auditService AuditService, tokenRevoker TokenRevoker, window time.Duration) BreakGlassService {
+	return &breakGlassService{userRepo, breakGlassRepo, auditService, tokenRevoker, window}
+}
+
+// CreateAccount creates a disabled break-glass account and returns its generated password
+func (s *breakGlassService) CreateAccount(ctx context.Context, email string, firstName string, lastName string) (string, error) {
+	email = strings.TrimSpace(email)
+	if _, err := s.userRepo.FindByEmail(email); err == nil {
+		return "", exception.NewConflictBusinessException("User already exists")
+	}
+
+	password, err := randomToken(24)
+	if err != nil {
+		return "", exception.ErrInternal
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", exception.ErrInternal
+	}
+
+	user, err := s.userRepo.Create(model.User{
+		FirstName:   firstName,
+		LastName:    lastName,
+		Email:       email,
+		Password:    string(hashedPassword),
+		AccountType: model.AccountTypeBreakGlass,
+	})
+	if err != nil {
+		return "", exception.NewInternal("Failed to save user")
+	}
+
+	s.auditService.Alert(ctx, AuditBreakGlassCreated, nil, AuditTargetUser, strconv.FormatUint(uint64(user.ID), 10), map[string]any{
+		"email": user.Email,
+	})
+
+	return password, nil
+}
+
+// SealCode generates a one-time code that enables the account once, replacing any unused code
+func (s *breakGlassService) SealCode(ctx context.Context, email string) (string, error) {
+	user, err := s.findBreakGlassAccount(strings.TrimSpace(email))
+	if err != nil {
+		return "", err
+	}
+
+	raw := make([]byte, 20)
+	if _, err := rand.Read(raw); err != nil {
+		return "", exception.ErrInternal
+	}
+	code := formatSealedCode(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw))
+
+	codeHash, err := bcrypt.GenerateFromPassword([]byte(normalizeSealedCode(code)), bcrypt.DefaultCost)
+	if err != nil {
+		return "", exception.ErrInternal
+	}
+
+	if _, err := s.breakGlassRepo.ReplaceCode(model.BreakGlassCode{UserID: user.ID, CodeHash: string(codeHash)}); err != nil {
+		return "", exception.NewInternal("Failed to save sealed code")
+	}
+
+	s.auditService.Alert(ctx, AuditBreakGlassCodeSealed, nil, AuditTargetUser, strconv.FormatUint(uint64(user.ID), 10), map[string]any{
+		"email": user.Email,
+	})
+
+	return code, nil
+}
+
+// Unseal enables the account with a sealed one-time code. It needs no authentication: it is the path used when
+// regular sign-in is broken. Unknown accounts cost as much as wrong codes, and too many wrong codes lock unsealing
+// the account for a while; locked accounts answer like wrong codes, so neither tells which accounts exist.
+func (s *breakGlassService) Unseal(c *gin.Context, req requestDTO.UnsealBreakGlassRequest) (model.BreakGlassActivation, error) {
+	cfg := config.LoadConfig()
+	invalid := exception.NewUnauthorizedBusinessException("Invalid break-glass account or code")
+	code := []byte(normalizeSealedCode(req.Code))
+
+	user, err := s.findBreakGlassAccount(strings.TrimSpace(req.Email))
+	if err != nil {
+		_ = bcrypt.CompareHashAndPassword(dummySealedCodeHash(), code)
+		return model.BreakGlassActivation{}, invalid
+	}
+
+	now := time.Now()
+	lockedUntil, err := s.breakGlassRepo.FindUnsealLock(user.ID)
+	if err != nil {
+		return model.BreakGlassActivation{}, exception.ErrInternal
+	}
+	if lockedUntil != nil && now.Before(*lockedUntil) {
+		_ = bcrypt.CompareHashAndPassword(dummySealedCodeHash(), code)
+		s.auditService.Alert(c.Request.Context(), AuditBreakGlassUnsealLocked, nil, AuditTargetUser, strconv.FormatUint(uint64(user.ID), 10), map[string]any{
+			"client_ip":    c.ClientIP(),
+			"locked_until": *lockedUntil,
+		})
+		return model.BreakGlassActivation{}, invalid
+	}
+
+	codes, err := s.breakGlassRepo.FindUnusedCodes(user.ID)
+	if err != nil {
+		return model.BreakGlassActivation{}, exception.ErrInternal
+	}
+	if len(codes) == 0 {
+		_ = bcrypt.CompareHashAndPassword(dummySealedCodeHash(), code)
+	}
+
+	for _, sealed := range codes {
+		if bcrypt.CompareHashAndPassword([]byte(sealed.CodeHash), code) != nil {
+			continue
+		}
+
+		used, err := s.breakGlassRepo.MarkCodeUsed(sealed.CodeID, now)
+		if err != nil {
+			return model.BreakGlassActivation{}, exception.ErrInternal
+		}
+		if !used {
+			break
+		}
+
+		if err := s.breakGlassRepo.ResetUnsealFailures(user.ID); err != nil {
+			slog.ErrorContext(c.Request.Context(), "failed to reset break-glass unseal failures",
+				"userId", user.ID,
+				"error", err,
+			)
+		}
+		return s.enable(c, user, model.BreakGlassActivation{
+			UserID: user.ID,
+			Method: model.BreakGlassMethodSealedCode,
+			Reason: req.Reason,
+		}, nil)
+	}
+
+	locked, err := s.breakGlassRepo.RecordUnsealFailure(user.ID, cfg.BreakGlassUnsealMaxAttempts, now.Add(cfg.BreakGlassUnsealLockout))
+	if err != nil {
+		return model.BreakGlassActivation{}, exception.ErrInternal
+	}
+
+	s.auditService.Alert(c.Request.Context(), AuditBreakGlassUnsealFailed, nil, AuditTargetUser, strconv.FormatUint(uint64(user.ID), 10), map[string]any{
+		"client_ip": c.ClientIP(),
+		"locked":    locked,
+	})
+	return model.BreakGlassActivation{}, invalid
+}
+
+// RequestActivation is the first half of the two-person activation
+func (s *breakGlassService) RequestActivation(c *gin.Context, actorEmail string, req requestDTO.BreakGlassActivationRequest) (model.BreakGlassActivation, error) {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return model.BreakGlassActivation{}, exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	user, err := s.userRepo.FindByID(req.UserID)
+	if err != nil || !user.IsBreakGlass() {
+		return model.BreakGlassActivation{}, exception.NewNotFound("Break-glass account not found")
+	}
+
+	activation, err := s.breakGlassRepo.CreateActivation(model.BreakGlassActivation{
+		UserID:      user.ID,
+		Method:      model.BreakGlassMethodTwoPerson,
+		Status:      model.BreakGlassPending,
+		Reason:      req.Reason,
+		RequestedBy: &actor.ID,
+	})
+	if err != nil {
+		return model.BreakGlassActivation{}, exception.NewInternal("Failed to save break-glass activation")
+	}
+
+	s.auditService.Alert(c.Request.Context(), AuditBreakGlassRequested, &actor.ID, AuditTargetUser, strconv.FormatUint(uint64(user.ID), 10), map[string]any{
+		"activation_id": activation.ActivationID,
+		"reason":        req.Reason,
+	})
+
+	activation.User = user
+	return activation, nil
+}
+
+// ApproveActivation is the second half of the two-person activation; the approver must differ from the requester
+func (s *breakGlassService) ApproveActivation(c *gin.Context, actorEmail string, id uint) (model.BreakGlassActivation, error) {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return model.BreakGlassActivation{}, exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	activation, err := s.breakGlassRepo.FindActivationByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.BreakGlassActivation{}, exception.NewNotFound("Break-glass activation not found")
+		}
+		return model.BreakGlassActivation{}, exception.ErrInternal
+	}
+	if activation.Status != model.BreakGlassPending {
+		return model.BreakGlassActivation{}, exception.NewConflictBusinessException("Break-glass activation is not pending")
+	}
+	if activation.RequestedBy != nil && *activation.RequestedBy == actor.ID {
+		return model.BreakGlassActivation{}, exception.NewConflictBusinessException("A second person must approve the activation")
+	}
+
+	return s.enable(c, activation.User, activation, &actor.ID)
+}
+
+func (s *breakGlassService) ListActivations(c *gin.Context) ([]model.BreakGlassActivation, error) {
+	activations, err := s.breakGlassRepo.FindActivations()
+	if err != nil {
+		return nil, exception.ErrInternal
+	}
+	return activations, nil
+}
+
+func (s *breakGlassService) Disable(c *gin.Context, actorEmail string, userID uint) error {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || !user.IsBreakGlass() {
+		return exception.NewNotFound("Break-glass account not found")
+	}
+
+	return s.disable(c.Request.Context(), user, &actor.ID, model.BreakGlassDisabled)
+}
+
+// SyncActivations disables break-glass accounts whose window has elapsed
+func (s *breakGlassService) SyncActivations(ctx context.Context) error {
+	users, err := s.userRepo.FindExpiredBreakGlass(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if err := s.disable(ctx, user, nil, model.BreakGlassExpired); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *breakGlassService) enable(c *gin.Context, user model.User, activation model.BreakGlassActivation, approverID *uint) (model.BreakGlassActivation, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.window)
+
+	if activation.ActivationID == 0 {
+		activation.Status = model.BreakGlassActive
+		activation.ActivatedAt = &now
+		activation.ExpiresAt = &expiresAt
+
+		created, err := s.breakGlassRepo.CreateActivation(activation)
+		if err != nil {
+			return model.BreakGlassActivation{}, exception.NewInternal("Failed to save break-glass activation")
+		}
+		activation = created
+	} else {
+		approved, err := s.breakGlassRepo.ApproveActivation(activation.ActivationID, *approverID, now, expiresAt)
+		if err != nil {
+			return model.BreakGlassActivation{}, exception.ErrInternal
+		}
+		if !approved {
+			return model.BreakGlassActivation{}, exception.NewConflictBusinessException("Break-glass activation is not pending")
+		}
+		activation.Status = model.BreakGlassActive
+		activation.ApprovedBy = approverID
+		activation.ActivatedAt = &now
+		activation.ExpiresAt = &expiresAt
+	}
+
+	if err := s.userRepo.SetEnabledUntil(user.ID, &expiresAt); err != nil {
+		return model.BreakGlassActivation{}, exception.ErrInternal
+	}
+
+	s.auditService.Alert(c.Request.Context(), AuditBreakGlassEnabled, approverID, AuditTargetUser, strconv.FormatUint(uint64(user.ID), 10), map[string]any{
+		"activation_id": activation.ActivationID,
+		"method":        activation.Method,
+		"reason":        activation.Reason,
+		"requested_by":  activation.RequestedBy,
+		"expires_at":    expiresAt,
+		"client_ip":     c.ClientIP(),
+	})
+
+	activation.User = user
+	return activation, nil
+}
+
+func (s *breakGlassService) disable(ctx context.Context, user model.User, actorID *uint, status string) error {
+	if err := s.userRepo.SetEnabledUntil(user.ID, nil); err != nil {
+		return exception.ErrInternal
+	}
+	if err := s.breakGlassRepo.CloseActiveActivations(user.ID, status); err != nil {
+		return exception.ErrInternal
+	}
+	if err := s.tokenRevoker.RevokeAllTokens(ctx, user.ID, actorID, "break_glass_disabled"); err != nil {
+		return err
+	}
+
+	s.auditService.Alert(ctx, AuditBreakGlassDisabled, actorID, AuditTargetUser, strconv.FormatUint(uint64(user.ID), 10), map[string]any{
+		"email":  user.Email,
+		"status": status,
+	})
+	return nil
+}
+
+func (s *breakGlassService) findBreakGlassAccount(email string) (model.User, error) {
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil || !user.IsBreakGlass() {
+		return model.User{}, exception.NewNotFound("Break-glass account not found")
+	}
+	return user, nil
+}
+
+// dummySealedCodeHash is compared against when there is no sealed code to compare with, at the cost of real codes
+var dummySealedCodeHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy-sealed-code"), bcrypt.DefaultCost)
+	return hash
+})
+
+// formatSealedCode groups a code in blocks of four characters so it can be read out and typed safely
+func formatSealedCode(code string) string {
+	var groups []string
+	for len(code) > 4 {
+		groups = append(groups, code[:4])
+		code = code[4:]
+	}
+	return strings.Join(append(groups, code), "-")
+}
+
+func normalizeSealedCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// randomToken returns n random bytes encoded as unpadded base64url
+func randomToken(n int) (string, error) {
+	raw := make([]byte, n)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/request"
+	"auth-service/internal/repository"
+	"slices"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeBreakGlassRepository keeps the sealed codes and unseal failures of the accounts in memory
+type fakeBreakGlassRepository struct {
+	repository.BreakGlassRepository
+	codes       []model.BreakGlassCode
+	failures    map[uint]int
+	lockedUntil map[uint]time.Time
+	activations []model.BreakGlassActivation
+}
+
+func (r *fakeBreakGlassRepository) FindUnusedCodes(userID uint) ([]model.BreakGlassCode, error) {
+	var codes []model.BreakGlassCode
+	for _, code := range r.codes {
+		if code.UserID == userID && code.UsedAt == nil {
+			codes = append(codes, code)
+		}
+	}
+	return codes, nil
+}
+
+func (r *fakeBreakGlassRepository) MarkCodeUsed(codeID uint, at time.Time) (bool, error) {
+	for i := range r.codes {
+		if r.codes[i].CodeID == codeID && r.codes[i].UsedAt == nil {
+			r.codes[i].UsedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeBreakGlassRepository) FindUnsealLock(userID uint) (*time.Time, error) {
+	if lockedUntil, ok := r.lockedUntil[userID]; ok {
+		return &lockedUntil, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeBreakGlassRepository) RecordUnsealFailure(userID uint, maxAttempts int, lockedUntil time.Time) (bool, error) {
+	r.failures[userID]++
+	if r.failures[userID] < maxAttempts {
+		return false, nil
+	}
+	r.failures[userID] = 0
+	r.lockedUntil[userID] = lockedUntil
+	return true, nil
+}
+
+func (r *fakeBreakGlassRepository) ResetUnsealFailures(userID uint) error {
+	delete(r.failures, userID)
+	return nil
+}
+
+func (r *fakeBreakGlassRepository) CreateActivation(activation model.BreakGlassActivation) (model.BreakGlassActivation, error) {
+	activation.ActivationID = uint(len(r.activations) + 1)
+	r.activations = append(r.activations, activation)
+	return activation, nil
+}
+
+func (r *fakeUserRepository) SetEnabledUntil(id uint, enabledUntil *time.Time) error {
+	for i := range r.users {
+		if r.users[i].ID == id {
+			r.users[i].EnabledUntil = enabledUntil
+			return nil
+		}
+	}
+	return nil
+}
+
+// newTestBreakGlassService returns a service for the break-glass account emergency@example.com, sealed with code
+func newTestBreakGlassService(t *testing.T, code string) (BreakGlassService, *fakeUserRepository, *fakeBreakGlassRepository, *fakeAuditService) {
+	t.Helper()
+
+	codeHash, err := bcrypt.GenerateFromPassword([]byte(normalizeSealedCode(code)), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users := &fakeUserRepository{users: []model.User{{ID: 1, Email: "emergency@example.com", AccountType: model.AccountTypeBreakGlass}}}
+	repo := &fakeBreakGlassRepository{
+		codes:       []model.BreakGlassCode{{CodeID: 1, UserID: 1, CodeHash: string(codeHash)}},
+		failures:    map[uint]int{},
+		lockedUntil: map[uint]time.Time{},
+	}
+	audit := &fakeAuditService{}
+	return NewBreakGlassService(users, repo, audit, &fakeTokenRevoker{}, time.Hour), users, repo, audit
+}
+
+func TestBreakGlassServiceUnseal(t *testing.T) {
+	t.Setenv("BREAK_GLASS_UNSEAL_MAX_ATTEMPTS", "3")
+	t.Setenv("BREAK_GLASS_UNSEAL_LOCKOUT", "1h")
+
+	const code = "ABCD-EFGH"
+	unseal := func(email string, code string) requestDTO.UnsealBreakGlassRequest {
+		return requestDTO.UnsealBreakGlassRequest{Email: email, Code: code, Reason: "identity provider outage"}
+	}
+
+	t.Run("unknown account answers like a wrong code", func(t *testing.T) {
+		s, _, repo, audit := newTestBreakGlassService(t, code)
+
+		if _, err := s.Unseal(newTestContext(), unseal("nobody@example.com", code)); errorCode(err) != "UNAUTHORIZED" {
+			t.Errorf("Unseal() error = %v, want UNAUTHORIZED", err)
+		}
+		if len(repo.failures) != 0 || len(audit.actions) != 0 {
+			t.Errorf("unknown account recorded failures %v, audited %v", repo.failures, audit.actions)
+		}
+	})
+
+	t.Run("wrong codes lock the account out", func(t *testing.T) {
+		s, users, repo, audit := newTestBreakGlassService(t, code)
+
+		for range 3 {
+			if _, err := s.Unseal(newTestContext(), unseal("emergency@example.com", "WRONG-CODE")); errorCode(err) != "UNAUTHORIZED" {
+				t.Fatalf("Unseal() error = %v, want UNAUTHORIZED", err)
+			}
+		}
+		if _, ok := repo.lockedUntil[1]; !ok {
+			t.Fatal("account not locked after the maximum number of wrong codes")
+		}
+
+		if _, err := s.Unseal(newTestContext(), unseal("emergency@example.com", code)); errorCode(err) != "UNAUTHORIZED" {
+			t.Errorf("Unseal() of a locked account error = %v, want UNAUTHORIZED", err)
+		}
+		if users.users[0].EnabledUntil != nil || repo.codes[0].UsedAt != nil {
+			t.Error("locked account was enabled")
+		}
+
+		want := []string{AuditBreakGlassUnsealFailed, AuditBreakGlassUnsealFailed, AuditBreakGlassUnsealFailed, AuditBreakGlassUnsealLocked}
+		if !slices.Equal(audit.actions, want) {
+			t.Errorf("audited %v, want %v", audit.actions, want)
+		}
+	})
+
+	t.Run("the right code enables the account and resets failures", func(t *testing.T) {
+		s, users, repo, _ := newTestBreakGlassService(t, code)
+
+		if _, err := s.Unseal(newTestContext(), unseal("emergency@example.com", "WRONG-CODE")); errorCode(err) != "UNAUTHORIZED" {
+			t.Fatalf("Unseal() error = %v, want UNAUTHORIZED", err)
+		}
+
+		activation, err := s.Unseal(newTestContext(), unseal("emergency@example.com", "abcd efgh"))
+		if err != nil {
+			t.Fatalf("Unseal() error = %v", err)
+		}
+		if activation.Status != model.BreakGlassActive || users.users[0].EnabledUntil == nil {
+			t.Errorf("Unseal() = %+v, account enabled until %v", activation, users.users[0].EnabledUntil)
+		}
+		if repo.failures[1] != 0 {
+			t.Errorf("failures = %d after a successful unseal, want 0", repo.failures[1])
+		}
+
+		if _, err := s.Unseal(newTestContext(), unseal("emergency@example.com", code)); errorCode(err) != "UNAUTHORIZED" {
+			t.Errorf("Unseal() reused a sealed code, error = %v", err)
+		}
+	})
+}
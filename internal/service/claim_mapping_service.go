@@ -0,0 +1,253 @@
+package service
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/request"
+	"auth-service/internal/model/dto/response"
+	"auth-service/internal/repository"
+	"auth-service/pkg/utils/exception"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClaimMappingService manages the custom claims issued per audience and resolves them for a user.
+// Mappings are cached and reloaded at most every ClaimMappingRefreshInterval, so token issuance and
+// introspection don't query them on every request.
+type ClaimMappingService interface {
+	List(c *gin.Context) ([]model.ClaimMapping, error)
+	Create(c *gin.Context, actorEmail string, req requestDTO.CreateClaimMappingRequest) (model.ClaimMapping, error)
+	Delete(c *gin.Context, actorEmail string, id uint) error
+	Claims(ctx context.Context, audiences []string, user responseDto.UserResponseV2) (map[string]any, error)
+}
+
+type claimMappingService struct {
+	userRepo         repository.UserRepository
+	claimMappingRepo repository.ClaimMappingRepository
+	auditService     AuditService
+
+	mu       sync.Mutex
+	cached   []model.ClaimMapping
+	loadedAt time.Time
+}
+
+func NewClaimMappingService(userRepo repository.UserRepository, claimMappingRepo repository.ClaimMappingRepository, auditService AuditService) ClaimMappingService {
+	return &claimMappingService{
+		userRepo:         userRepo,
+		claimMappingRepo: claimMappingRepo,
+		auditService:     auditService,
+	}
+}
+
+// reservedClaims are set by auth-service itself and can't be mapped
+var reservedClaims = map[string]bool{
+	"sub": true, "iss": true, "aud": true, "exp": true, "nbf": true, "iat": true, "jti": true,
+	"sid": true, "gen": true, "act": true, "cnf": true, "scope": true,
+	"first_name": true, "last_name": true, "email": true, "roles": true, "attributes": true,
+}
+
+var claimNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.:-]*$`)
+
+func (s *claimMappingService) List(c *gin.Context) ([]model.ClaimMapping, error) {
+	mappings, err := s.claimMappingRepo.FindAll()
+	if err != nil {
+		return nil, exception.ErrInternal
+	}
+	return mappings, nil
+}
+
+func (s *claimMappingService) Create(c *gin.Context, actorEmail string, req requestDTO.CreateClaimMappingRequest) (model.ClaimMapping, error) {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return model.ClaimMapping{}, exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	claim := strings.TrimSpace(req.Claim)
+	if !claimNamePattern.MatchString(claim) {
+		return model.ClaimMapping{}, exception.NewBadRequest("Invalid claim name")
+	}
+	if reservedClaims[claim] {
+		return model.ClaimMapping{}, exception.NewBadRequest(fmt.Sprintf("Claim %q is reserved", claim))
+	}
+	if _, err := parseClaimSource(req.Source); err != nil {
+		return model.ClaimMapping{}, exception.NewBadRequest(err.Error())
+	}
+
+	audience := strings.TrimSpace(req.Audience)
+	if _, err := s.claimMappingRepo.FindByAudienceAndClaim(audience, claim); err == nil {
+		return model.ClaimMapping{}, exception.NewConflictBusinessException("Claim is already mapped for this audience")
+	}
+
+	mapping, err := s.claimMappingRepo.Create(model.ClaimMapping{
+		Audience:  audience,
+		Claim:     claim,
+		Source:    req.Source,
+		CreatedBy: &actor.ID,
+	})
+	if err != nil {
+		return model.ClaimMapping{}, exception.NewInternal("Failed to save claim mapping")
+	}
+	s.invalidate()
+
+	s.auditService.Record(c.Request.Context(), AuditClaimMappingCreated, &actor.ID, AuditTargetClaimMapping, strconv.FormatUint(uint64(mapping.ClaimMappingID), 10), map[string]any{
+		"audience": mapping.Audience,
+		"claim":    mapping.Claim,
+		"source":   mapping.Source,
+	})
+
+	return mapping, nil
+}
+
+func (s *claimMappingService) Delete(c *gin.Context, actorEmail string, id uint) error {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	deleted, err := s.claimMappingRepo.Delete(id)
+	if err != nil {
+		return exception.NewInternal("Failed to delete claim mapping")
+	}
+	if !deleted {
+		return exception.NewNotFound("Claim mapping not found")
+	}
+	s.invalidate()
+
+	s.auditService.Record(c.Request.Context(), AuditClaimMappingDeleted, &actor.ID, AuditTargetClaimMapping, strconv.FormatUint(uint64(id), 10), nil)
+
+	return nil
+}
+
+// Claims resolves the custom claims of the user for the given audiences. Mappings for every audience ("*")
+// apply first; audience-specific mappings override them, later audiences overriding earlier ones.
+// It returns nil when no mapping applies.
+func (s *claimMappingService) Claims(ctx context.Context, audiences []string, user responseDto.UserResponseV2) (map[string]any, error) {
+	mappings, err := s.mappings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims map[string]any
+	apply := func(audience string) {
+		for _, mapping := range mappings {
+			if mapping.Audience != audience {
+				continue
+			}
+			source, err := parseClaimSource(mapping.Source)
+			if err != nil {
+				// Validated on create; a mapping edited in the database by hand is skipped
+				slog.WarnContext(ctx, "skipping invalid claim mapping",
+					"claimMappingId", mapping.ClaimMappingID,
+					"error", err,
+				)
+				continue
+			}
+			if claims == nil {
+				claims = map[string]any{}
+			}
+			claims[mapping.Claim] = source(user)
+		}
+	}
+
+	apply(model.ClaimMappingAllAudiences)
+	for _, audience := range audiences {
+		if audience != "" && audience != model.ClaimMappingAllAudiences {
+			apply(audience)
+		}
+	}
+	return claims, nil
+}
+
+// mappings returns the cached mappings, reloading them once they are older than the refresh interval.
+// A failed reload keeps serving the previous mappings.
+func (s *claimMappingService) mappings(ctx context.Context) ([]model.ClaimMapping, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loadedAt.IsZero() && time.Since(s.loadedAt) < config.LoadConfig().ClaimMappingRefreshInterval {
+		return s.cached, nil
+	}
+
+	mappings, err := s.claimMappingRepo.FindAll()
+	if err != nil {
+		if s.loadedAt.IsZero() {
+			return nil, exception.New(http.StatusServiceUnavailable, "CLAIM_MAPPINGS_UNAVAILABLE", "Claim mappings could not be loaded")
+		}
+		slog.ErrorContext(ctx, "failed to reload claim mappings",
+			"error", err,
+		)
+		return s.cached, nil
+	}
+
+	s.cached = mappings
+	s.loadedAt = time.Now()
+	return mappings, nil
+}
+
+func (s *claimMappingService) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadedAt = time.Time{}
+}
+
+// claimSource resolves a claim value for a user
+type claimSource func(user responseDto.UserResponseV2) any
+
+// parseClaimSource compiles a mapping's source expression
+func parseClaimSource(source string) (claimSource, error) {
+	switch source {
+	case "user.id":
+		return func(user responseDto.UserResponseV2) any { return user.ID }, nil
+	case "user.first_name":
+		return func(user responseDto.UserResponseV2) any { return user.FirstName }, nil
+	case "user.last_name":
+		return func(user responseDto.UserResponseV2) any { return user.LastName }, nil
+	case "user.email":
+		return func(user responseDto.UserResponseV2) any { return user.Email }, nil
+	case "user.full_name":
+		return func(user responseDto.UserResponseV2) any {
+			return strings.TrimSpace(user.FirstName + " " + user.LastName)
+		}, nil
+	case "roles":
+		return func(user responseDto.UserResponseV2) any { return user.Roles }, nil
+	case "roles.joined":
+		return func(user responseDto.UserResponseV2) any { return strings.Join(user.Roles, "|") }, nil
+	}
+
+	// Only attributes exposed in tokens are known here
+	if name, ok := strings.CutPrefix(source, "attribute:"); ok && name != "" {
+		return func(user responseDto.UserResponseV2) any { return user.Attributes[name] }, nil
+	}
+
+	if role, ok := strings.CutPrefix(source, "role:"); ok && role != "" {
+		return func(user responseDto.UserResponseV2) any {
+			for _, name := range user.Roles {
+				if strings.EqualFold(name, role) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+
+	if raw, ok := strings.CutPrefix(source, "const:"); ok {
+		var value any = raw
+		var parsed any
+		if err := json.Unmarshal([]byte(raw), &parsed); err == nil {
+			value = parsed
+		}
+		return func(responseDto.UserResponseV2) any { return value }, nil
+	}
+
+	return nil, fmt.Errorf("unknown claim source %q", source)
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/request"
+	"auth-service/internal/model/dto/response"
+	"context"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// fakeClaimMappingRepository keeps mappings in a slice
+type fakeClaimMappingRepository struct {
+	mappings []model.ClaimMapping
+	loads    int
+}
+
+func (r *fakeClaimMappingRepository) FindAll() ([]model.ClaimMapping, error) {
+	r.loads++
+	return append([]model.ClaimMapping{}, r.mappings...), nil
+}
+
+func (r *fakeClaimMappingRepository) FindByAudienceAndClaim(audience string, claim string) (model.ClaimMapping, error) {
+	for _, mapping := range r.mappings {
+		if mapping.Audience == audience && mapping.Claim == claim {
+			return mapping, nil
+		}
+	}
+	return model.ClaimMapping{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeClaimMappingRepository) Create(mapping model.ClaimMapping) (model.ClaimMapping, error) {
+	mapping.ClaimMappingID = uint(len(r.mappings) + 1)
+	r.mappings = append(r.mappings, mapping)
+	return mapping, nil
+}
+
+func (r *fakeClaimMappingRepository) Delete(id uint) (bool, error) {
+	for i, mapping := range r.mappings {
+		if mapping.ClaimMappingID == id {
+			r.mappings = append(r.mappings[:i], r.mappings[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestClaimMappingServiceClaims(t *testing.T) {
+	repo := &fakeClaimMappingRepository{mappings: []model.ClaimMapping{
+		{ClaimMappingID: 1, Audience: model.ClaimMappingAllAudiences, Claim: "tenant", Source: `const:"acme"`},
+		{ClaimMappingID: 2, Audience: model.ClaimMappingAllAudiences, Claim: "name", Source: "user.full_name"},
+		{ClaimMappingID: 3, Audience: "billing", Claim: "tenant", Source: "const:billing"},
+		{ClaimMappingID: 4, Audience: "billing", Claim: "is_admin", Source: "role:admin"},
+		{ClaimMappingID: 5, Audience: "reports", Claim: "groups", Source: "roles.joined"},
+		{ClaimMappingID: 6, Audience: "reports", Claim: "limit", Source: "const:10"},
+		{ClaimMappingID: 7, Audience: "reports", Claim: "broken", Source: "user.password"},
+		{ClaimMappingID: 8, Audience: "hr", Claim: "dept", Source: "attribute:department"},
+	}}
+	s := NewClaimMappingService(nil, repo, nil)
+	user := responseDto.UserResponseV2{ID: 7, FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", Roles: []string{"ADMIN", "USER"}, Attributes: model.Attributes{"department": "Finance"}}
+
+	tests := []struct {
+		name      string
+		audiences []string
+		want      map[string]any
+	}{
+		{"every audience only", nil, map[string]any{"tenant": "acme", "name": "Ada Lovelace"}},
+		{"audience overrides every audience", []string{"billing"}, map[string]any{"tenant": "billing", "name": "Ada Lovelace", "is_admin": true}},
+		{"invalid source is skipped", []string{"reports"}, map[string]any{"tenant": "acme", "name": "Ada Lovelace", "groups": "ADMIN|USER", "limit": float64(10)}},
+		{"exposed attribute", []string{"hr"}, map[string]any{"tenant": "acme", "name": "Ada Lovelace", "dept": "Finance"}},
+		{"later audience wins", []string{"reports", "billing"}, map[string]any{"tenant": "billing", "name": "Ada Lovelace", "groups": "ADMIN|USER", "limit": float64(10), "is_admin": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Claims(context.Background(), tt.audiences, user)
+			if err != nil {
+				t.Fatalf("Claims() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Claims() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if repo.loads != 1 {
+		t.Errorf("mappings loaded %d times, want 1 within the refresh interval", repo.loads)
+	}
+}
+
+func TestClaimMappingServiceClaimsWithoutMappings(t *testing.T) {
+	s := NewClaimMappingService(nil, &fakeClaimMappingRepository{}, nil)
+
+	got, err := s.Claims(context.Background(), []string{"billing"}, responseDto.UserResponseV2{ID: 1})
+	if err != nil || got != nil {
+		t.Errorf("Claims() = %v, %v; want nil, nil", got, err)
+	}
+}
+
+func TestClaimMappingServiceCreate(t *testing.T) {
+	users := &fakeUserRepository{users: []model.User{{ID: 1, Email: "admin@example.com"}}}
+
+	tests := []struct {
+		name       string
+		actorEmail string
+		req        requestDTO.CreateClaimMappingRequest
+		wantCode   string
+	}{
+		{"unknown actor", "ghost@example.com", requestDTO.CreateClaimMappingRequest{Audience: "billing", Claim: "tenant", Source: "const:acme"}, "UNAUTHORIZED"},
+		{"invalid claim name", "admin@example.com", requestDTO.CreateClaimMappingRequest{Audience: "billing", Claim: "1tenant", Source: "const:acme"}, "BAD_REQUEST"},
+		{"reserved claim", "admin@example.com", requestDTO.CreateClaimMappingRequest{Audience: "billing", Claim: "sub", Source: "user.id"}, "BAD_REQUEST"},
+		{"unknown source", "admin@example.com", requestDTO.CreateClaimMappingRequest{Audience: "billing", Claim: "tenant", Source: "user.password"}, "BAD_REQUEST"},
+		{"already mapped", "admin@example.com", requestDTO.CreateClaimMappingRequest{Audience: "billing", Claim: "plan", Source: "const:gold"}, "CONFLICT"},
+		{"created", "admin@example.com", requestDTO.CreateClaimMappingRequest{Audience: " billing ", Claim: " tenant ", Source: "const:acme"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeClaimMappingRepository{mappings: []model.ClaimMapping{{ClaimMappingID: 1, Audience: "billing", Claim: "plan", Source: "const:silver"}}}
+			audit := &fakeAuditService{}
+			s := NewClaimMappingService(users, repo, audit)
+
+			mapping, err := s.Create(newTestContext(), tt.actorEmail, tt.req)
+			if code := errorCode(err); code != tt.wantCode {
+				t.Fatalf("Create() code = %q, want %q", code, tt.wantCode)
+			}
+			if tt.wantCode != "" {
+				if len(repo.mappings) != 1 || len(audit.actions) != 0 {
+					t.Errorf("refused mapping was saved or audited: %v, %v", repo.mappings, audit.actions)
+				}
+				return
+			}
+			if mapping.Audience != "billing" || mapping.Claim != "tenant" || *mapping.CreatedBy != 1 {
+				t.Errorf("Create() = %+v", mapping)
+			}
+			if !reflect.DeepEqual(audit.actions, []string{AuditClaimMappingCreated}) {
+				t.Errorf("audited %v", audit.actions)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/response"
+	"auth-service/internal/repository"
+	"auth-service/pkg/utils/exception"
+	"crypto/rand"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DeviceAuthorizationService runs the device authorization grant (RFC 8628) for CLIs and other devices that
+// can't take the user's password themselves: the device gets a device code and a user code, the user approves
+// the user code on the verification page, and the device polls the token endpoint until it gets its token.
+type DeviceAuthorizationService interface {
+	Authorize(c *gin.Context, client model.OAuthClient, scope string) (responseDto.DeviceAuthorizationResponse, error)
+	// Pending returns the authorization awaiting a decision for the user code
+	Pending(c *gin.Context, userCode string) (model.DeviceAuthorization, error)
+	// Decide approves or denies the device after checking the user's credentials
+	Decide(c *gin.Context, userCode string, email string, password string, approve bool) (model.DeviceAuthorization, error)
+	Token(c *gin.Context, client model.OAuthClient, deviceCode string) (responseDto.TokenResponse, error)
+}
+
+type deviceAuthorizationService struct {
+	userRepo                repository.UserRepository
+	deviceAuthorizationRepo repository.DeviceAuthorizationRepository
+	authService             AuthService
+	auditService            AuditService
+}
+
+func NewDeviceAuthorizationService(userRepo repository.UserRepository, deviceAuthorizationRepo repository.DeviceAuthorizationRepository, authService AuthService, auditService AuditService) DeviceAuthorizationService {
+	return &deviceAuthorizationService{userRepo, deviceAuthorizationRepo, authService, auditService}
+}
+
+// slowDownStep is added to the poll interval of a device polling too fast (RFC 8628 section 3.5)
+const slowDownStep = 5
+
+// userCodeAlphabet leaves out vowels, so user codes never spell words, and characters easily mistaken for others
+const userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+func (s *deviceAuthorizationService) Authorize(c *gin.Context, client model.OAuthClient, scope string) (responseDto.DeviceAuthorizationResponse, error) {
+	cfg := config.LoadConfig()
+	now := time.Now()
+
+	// Expired authorizations are only kept for a while, to answer late polls with expired_token
+	if err := s.deviceAuthorizationRepo.DeleteExpired(now.Add(-time.Hour)); err != nil {
+		return responseDto.DeviceAuthorizationResponse{}, exception.ErrInternal
+	}
+
+	deviceCode, err := newSecret()
+	if err != nil {
+		return responseDto.DeviceAuthorizationResponse{}, exception.ErrInternal
+	}
+	userCode, err := newUserCode()
+	if err != nil {
+		return responseDto.DeviceAuthorizationResponse{}, exception.ErrInternal
+	}
+
+	interval := int(cfg.DevicePollInterval.Seconds())
+	if interval < 1 {
+		interval = 1
+	}
+
+	authorization, err := s.deviceAuthorizationRepo.Create(model.DeviceAuthorization{
+		DeviceCodeHash: hashSecret(deviceCode),
+		UserCode:       userCode,
+		OAuthClientID:  client.OAuthClientID,
+		Scope:          strings.Join(strings.Fields(scope), " "),
+		Status:         model.DeviceAuthorizationPending,
+		PollInterval:   interval,
+		ExpiresAt:      now.Add(cfg.DeviceCodeTTL),
+	})
+	if err != nil {
+		return responseDto.DeviceAuthorizationResponse{}, exception.NewInternal("Failed to save device authorization")
+	}
+
+	verificationURI := cfg.DeviceVerificationURI
+	if verificationURI == "" {
+		scheme := "http"
+		if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+			scheme = "https"
+		}
+		verificationURI = scheme + "://" + c.Request.Host + "/device"
+	}
+
+	return responseDto.DeviceAuthorizationResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                authorization.UserCode,
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: verificationURI + "?user_code=" + url.QueryEscape(authorization.UserCode),
+		ExpiresIn:               int64(cfg.DeviceCodeTTL.Seconds()),
+		Interval:                int64(interval),
+	}, nil
+}
+
+func (s *deviceAuthorizationService) Pending(c *gin.Context, userCode string) (model.DeviceAuthorization, error) {
+	authorization, err := s.deviceAuthorizationRepo.FindByUserCode(normalizeUserCode(userCode))
+	if err != nil || authorization.Status != model.DeviceAuthorizationPending || authorization.ExpiredAt(time.Now()) {
+		return model.DeviceAuthorization{}, exception.NewNotFound("The code is invalid or has expired")
+	}
+	return authorization, nil
+}
+
+func (s *deviceAuthorizationService) Decide(c *gin.Context, userCode string, email string, password string, approve bool) (model.DeviceAuthorization, error) {
+	authorization, err := s.Pending(c, userCode)
+	if err != nil {
+		return model.DeviceAuthorization{}, err
+	}
+
+	user, err := s.userRepo.FindByEmail(strings.TrimSpace(email))
+	if err != nil || user.ID == 0 {
+		return model.DeviceAuthorization{}, exception.NewUnauthorizedBusinessException("Invalid email or password")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return model.DeviceAuthorization{}, exception.NewUnauthorizedBusinessException("Invalid email or password")
+	}
+	if user.IsBreakGlass() {
+		return model.DeviceAuthorization{}, exception.New(http.StatusForbidden, "DEVICE_AUTHORIZATION_FORBIDDEN", "Break-glass accounts cannot approve devices")
+	}
+
+	status, action := model.DeviceAuthorizationDenied, AuditDeviceDenied
+	if approve {
+		status, action = model.DeviceAuthorizationApproved, AuditDeviceApproved
+	}
+	decided, err := s.deviceAuthorizationRepo.Decide(authorization.DeviceAuthorizationID, status, user.ID)
+	if err != nil {
+		return model.DeviceAuthorization{}, exception.ErrInternal
+	}
+	if !decided {
+		return model.DeviceAuthorization{}, exception.NewNotFound("The code is invalid or has expired")
+	}
+	authorization.Status = status
+	authorization.UserID = &user.ID
+
+	s.auditService.Record(c.Request.Context(), action, &user.ID, AuditTargetDevice, strconv.FormatUint(uint64(authorization.DeviceAuthorizationID), 10), map[string]any{
+		"client":    authorization.OAuthClient.Name,
+		"scope":     authorization.Scope,
+		"client_ip": c.ClientIP(),
+	})
+
+	return authorization, nil
+}
+
+// Token redeems the device code once the user approved it. Until then it answers authorization_pending, or
+// slow_down, raising the device's poll interval, when the device polls faster than it was told to.
+func (s *deviceAuthorizationService) Token(c *gin.Context, client model.OAuthClient, deviceCode string) (responseDto.TokenResponse, error) {
+	if deviceCode == "" {
+		return responseDto.TokenResponse{}, oauthError(OAuthInvalidRequest, "device_code is required")
+	}
+
+	authorization, err := s.deviceAuthorizationRepo.FindByDeviceCodeHash(hashSecret(deviceCode))
+	if err != nil || authorization.OAuthClientID != client.OAuthClientID {
+		return responseDto.TokenResponse{}, oauthError(OAuthInvalidGrant, "Device code is invalid")
+	}
+
+	now := time.Now()
+	if authorization.ExpiredAt(now) {
+		return responseDto.TokenResponse{}, oauthError(OAuthExpiredToken, "Device code has expired")
+	}
+
+	switch authorization.Status {
+	case model.DeviceAuthorizationPending:
+		interval := authorization.PollInterval
+		tooFast := authorization.LastPolledAt != nil && now.Sub(*authorization.LastPolledAt) < time.Duration(interval)*time.Second
+		if tooFast {
+			interval += slowDownStep
+		}
+		if err := s.deviceAuthorizationRepo.RecordPoll(authorization.DeviceAuthorizationID, now, interval); err != nil {
+			return responseDto.TokenResponse{}, exception.ErrInternal
+		}
+		if tooFast {
+			return responseDto.TokenResponse{}, oauthError(OAuthSlowDown, "Polling too fast, wait "+strconv.Itoa(interval)+" seconds between requests")
+		}
+		return responseDto.TokenResponse{}, oauthError(OAuthAuthorizationPending, "The user has not approved the device yet")
+	case model.DeviceAuthorizationDenied:
+		return responseDto.TokenResponse{}, oauthError(OAuthAccessDenied, "The user denied the device")
+	case model.DeviceAuthorizationApproved:
+		used, err := s.deviceAuthorizationRepo.MarkUsed(authorization.DeviceAuthorizationID)
+		if err != nil {
+			return responseDto.TokenResponse{}, exception.ErrInternal
+		}
+		if used && authorization.UserID != nil {
+			var scope []string
+			if authorization.Scope != "" {
+				scope = strings.Fields(authorization.Scope)
+			}
+			return s.authService.IssueDeviceToken(c, *authorization.UserID, scope)
+		}
+	}
+
+	return responseDto.TokenResponse{}, oauthError(OAuthInvalidGrant, "Device code was already used")
+}
+
+// newUserCode returns a code like "BDFG-HJKL" from userCodeAlphabet
+func newUserCode() (string, error) {
+	var code strings.Builder
+	for i := 0; i < 8; i++ {
+		if i == 4 {
+			code.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(userCodeAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		code.WriteByte(userCodeAlphabet[n.Int64()])
+	}
+	return code.String(), nil
+}
+
+// normalizeUserCode accepts user codes typed in lower case, without or with other separators
+func normalizeUserCode(userCode string) string {
+	var code strings.Builder
+	for _, r := range strings.ToUpper(userCode) {
+		if strings.ContainsRune(userCodeAlphabet, r) {
+			code.WriteRune(r)
+		}
+	}
+	normalized := code.String()
+	if len(normalized) != 8 {
+		return normalized
+	}
+	return normalized[:4] + "-" + normalized[4:]
+}
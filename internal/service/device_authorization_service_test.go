@@ -0,0 +1,40 @@
+package service
+
+import "testing"
+
+func TestNormalizeUserCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		userCode string
+		want     string
+	}{
+		{"as issued", "BCDF-GHJK", "BCDF-GHJK"},
+		{"lower case", "bcdf-ghjk", "BCDF-GHJK"},
+		{"without separator", "bcdfghjk", "BCDF-GHJK"},
+		{"other separators", " bcd f.gh jk ", "BCDF-GHJK"},
+		{"characters outside the alphabet", "BCDF-GHJA", "BCDFGHJ"},
+		{"too short", "BCD", "BCD"},
+		{"too long", "BCDF-GHJK-L", "BCDFGHJKL"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeUserCode(tt.userCode); got != tt.want {
+				t.Errorf("normalizeUserCode(%q) = %q, want %q", tt.userCode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewUserCodeIsNormalized(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		userCode, err := newUserCode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := normalizeUserCode(userCode); got != userCode {
+			t.Fatalf("normalizeUserCode(%q) = %q, want it unchanged", userCode, got)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/request"
+	"auth-service/internal/repository"
+	"auth-service/pkg/utils/exception"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Grant and token types of the token endpoint
+const (
+	GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	GrantTypeDeviceCode    = "urn:ietf:params:oauth:grant-type:device_code"
+	TokenTypeAccessToken   = "urn:ietf:params:oauth:token-type:access_token"
+)
+
+// Error codes of the token endpoint (RFC 6749 section 5.2, RFC 8693 section 2.2.2). They are returned as
+// AppError codes and rendered as {"error", "error_description"} by the OAuth controller.
+const (
+	OAuthInvalidRequest       = "invalid_request"
+	OAuthInvalidClient        = "invalid_client"
+	OAuthInvalidGrant         = "invalid_grant"
+	OAuthUnsupportedGrantType = "unsupported_grant_type"
+	OAuthInvalidScope         = "invalid_scope"
+	OAuthInvalidTarget        = "invalid_target"
+	OAuthUnauthorizedClient   = "unauthorized_client"
+	// Device authorization grant (RFC 8628 section 3.5)
+	OAuthAuthorizationPending = "authorization_pending"
+	OAuthSlowDown             = "slow_down"
+	OAuthAccessDenied         = "access_denied"
+	OAuthExpiredToken         = "expired_token"
+)
+
+func oauthError(code string, description string) error {
+	status := http.StatusBadRequest
+	if code == OAuthInvalidClient {
+		status = http.StatusUnauthorized
+	}
+	return exception.New(status, code, description)
+}
+
+// OAuthClientService manages the services allowed to call the token endpoint and authenticates them.
+// Public clients, such as CLIs, have no secret and may only use the device authorization grant.
+type OAuthClientService interface {
+	List(c *gin.Context) ([]model.OAuthClient, error)
+	// Create registers a client and returns its secret, which is only ever shown this once; public clients get none
+	Create(c *gin.Context, actorEmail string, req requestDTO.CreateOAuthClientRequest) (model.OAuthClient, string, error)
+	Delete(c *gin.Context, actorEmail string, id uint) error
+	Authenticate(c *gin.Context, clientID string, clientSecret string) (model.OAuthClient, error)
+}
+
+type oauthClientService struct {
+	userRepo        repository.UserRepository
+	oauthClientRepo repository.OAuthClientRepository
+	auditService    AuditService
+}
+
+func NewOAuthClientService(userRepo repository.UserRepository, oauthClientRepo repository.OAuthClientRepository, auditService AuditService) OAuthClientService {
+	return &oauthClientService{userRepo, oauthClientRepo, auditService}
+}
+
+func (s *oauthClientService) List(c *gin.Context) ([]model.OAuthClient, error) {
+	clients, err := s.oauthClientRepo.FindAll()
+	if err != nil {
+		return nil, exception.ErrInternal
+	}
+	return clients, nil
+}
+
+func (s *oauthClientService) Create(c *gin.Context, actorEmail string, req requestDTO.CreateOAuthClientRequest) (model.OAuthClient, string, error) {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return model.OAuthClient{}, "", exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if _, err := s.oauthClientRepo.FindByName(name); err == nil {
+		return model.OAuthClient{}, "", exception.NewConflictBusinessException("A client already exists for this service")
+	}
+
+	audiences := make([]string, 0, len(req.AllowedAudiences))
+	for _, audience := range req.AllowedAudiences {
+		audience = strings.TrimSpace(audience)
+		if audience == "" || strings.Contains(audience, ",") {
+			return model.OAuthClient{}, "", exception.NewBadRequest("Invalid audience")
+		}
+		audiences = append(audiences, audience)
+	}
+	if req.Public && len(audiences) > 0 {
+		return model.OAuthClient{}, "", exception.NewBadRequest("Public clients cannot exchange tokens")
+	}
+	if !req.Public && len(audiences) == 0 {
+		return model.OAuthClient{}, "", exception.NewBadRequest("allowed_audiences is required")
+	}
+
+	clientID, err := randomHex(16)
+	if err != nil {
+		return model.OAuthClient{}, "", exception.ErrInternal
+	}
+	var secret, secretHash string
+	if !req.Public {
+		if secret, err = newSecret(); err != nil {
+			return model.OAuthClient{}, "", exception.ErrInternal
+		}
+		secretHash = hashSecret(secret)
+	}
+
+	client, err := s.oauthClientRepo.Create(model.OAuthClient{
+		ClientID:         clientID,
+		Name:             name,
+		SecretHash:       secretHash,
+		AllowedAudiences: strings.Join(audiences, ","),
+		Public:           req.Public,
+		CreatedBy:        &actor.ID,
+	})
+	if err != nil {
+		return model.OAuthClient{}, "", exception.NewInternal("Failed to save OAuth client")
+	}
+
+	s.auditService.Record(c.Request.Context(), AuditOAuthClientCreated, &actor.ID, AuditTargetOAuthClient, strconv.FormatUint(uint64(client.OAuthClientID), 10), map[string]any{
+		"client_id":         client.ClientID,
+		"name":              client.Name,
+		"allowed_audiences": audiences,
+		"public":            client.Public,
+	})
+
+	return client, secret, nil
+}
+
+func (s *oauthClientService) Delete(c *gin.Context, actorEmail string, id uint) error {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	deleted, err := s.oauthClientRepo.Delete(id)
+	if err != nil {
+		return exception.NewInternal("Failed to delete OAuth client")
+	}
+	if !deleted {
+		return exception.NewNotFound("OAuth client not found")
+	}
+
+	s.auditService.Record(c.Request.Context(), AuditOAuthClientDeleted, &actor.ID, AuditTargetOAuthClient, strconv.FormatUint(uint64(id), 10), nil)
+
+	return nil
+}
+
+// Authenticate checks the client's credentials; public clients only name themselves. Every failure is the same
+// invalid_client error.
+func (s *oauthClientService) Authenticate(c *gin.Context, clientID string, clientSecret string) (model.OAuthClient, error) {
+	if clientID == "" {
+		return model.OAuthClient{}, oauthError(OAuthInvalidClient, "Client authentication failed")
+	}
+
+	client, err := s.oauthClientRepo.FindByClientID(clientID)
+	if err != nil {
+		return model.OAuthClient{}, oauthError(OAuthInvalidClient, "Client authentication failed")
+	}
+	if client.Public {
+		if clientSecret != "" {
+			return model.OAuthClient{}, oauthError(OAuthInvalidClient, "Client authentication failed")
+		}
+		return client, nil
+	}
+	if clientSecret == "" || subtle.ConstantTimeCompare([]byte(client.SecretHash), []byte(hashSecret(clientSecret))) != 1 {
+		return model.OAuthClient{}, oauthError(OAuthInvalidClient, "Client authentication failed")
+	}
+	return client, nil
+}
+
+// newSecret returns 256 random bits, for client secrets and device codes. Being random, a plain SHA-256
+// is enough to store them.
+func newSecret() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/request"
+	"auth-service/internal/model/dto/response"
+	"auth-service/internal/repository"
+	"auth-service/pkg/utils/exception"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// personalAccessTokenPrefix marks personal access tokens, so Verify can tell them from the other formats
+const personalAccessTokenPrefix = "pat_"
+
+func isPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, personalAccessTokenPrefix)
+}
+
+// PersonalAccessTokenService manages the long-lived, scoped tokens users create for their scripts.
+// The tokens themselves are verified by AuthService.Verify like any other token. actor is the actor of the token
+// the request was made with; tokens used on behalf of the user, such as impersonation tokens, cannot manage them.
+type PersonalAccessTokenService interface {
+	List(c *gin.Context, userEmail string, actor *responseDto.ActorResponse) ([]model.PersonalAccessToken, error)
+	// Create returns the new token, which is only ever shown this once
+	Create(c *gin.Context, userEmail string, actor *responseDto.ActorResponse, req requestDTO.CreatePersonalAccessTokenRequest) (model.PersonalAccessToken, string, error)
+	Revoke(c *gin.Context, userEmail string, actor *responseDto.ActorResponse, id uint) error
+}
+
+type personalAccessTokenService struct {
+	userRepo                repository.UserRepository
+	roleRepo                repository.RoleRepository
+	personalAccessTokenRepo repository.PersonalAccessTokenRepository
+	auditService            AuditService
+}
+
+func NewPersonalAccessTokenService(userRepo repository.UserRepository, roleRepo repository.RoleRepository, personalAccessTokenRepo repository.PersonalAccessTokenRepository, auditService AuditService) PersonalAccessTokenService {
+	return &personalAccessTokenService{userRepo, roleRepo, personalAccessTokenRepo, auditService}
+}
+
+func (s *personalAccessTokenService) List(c *gin.Context, userEmail string, actor *responseDto.ActorResponse) ([]model.PersonalAccessToken, error) {
+	user, err := s.userRepo.FindByEmail(strings.TrimSpace(userEmail))
+	if err != nil {
+		return nil, exception.NewUnauthorizedBusinessException("User not found")
+	}
+	if err := s.rejectDelegated(c, user, actor, "list"); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.personalAccessTokenRepo.FindByUserID(user.ID)
+	if err != nil {
+		return nil, exception.ErrInternal
+	}
+	return tokens, nil
+}
+
+func (s *personalAccessTokenService) Create(c *gin.Context, userEmail string, actor *responseDto.ActorResponse, req requestDTO.CreatePersonalAccessTokenRequest) (model.PersonalAccessToken, string, error) {
+	cfg := config.LoadConfig()
+	now := time.Now()
+
+	user, err := s.userRepo.FindByEmail(strings.TrimSpace(userEmail))
+	if err != nil {
+		return model.PersonalAccessToken{}, "", exception.NewUnauthorizedBusinessException("User not found")
+	}
+	if err := s.rejectDelegated(c, user, actor, "create"); err != nil {
+		return model.PersonalAccessToken{}, "", err
+	}
+	if user.IsBreakGlass() {
+		return model.PersonalAccessToken{}, "", exception.New(http.StatusForbidden, "PERSONAL_ACCESS_TOKEN_FORBIDDEN", "Break-glass accounts cannot create personal access tokens")
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return model.PersonalAccessToken{}, "", exception.NewBadRequest("Name is required")
+	}
+	exists, err := s.personalAccessTokenRepo.ExistsActiveName(user.ID, name, now)
+	if err != nil {
+		return model.PersonalAccessToken{}, "", exception.ErrInternal
+	}
+	if exists {
+		return model.PersonalAccessToken{}, "", exception.NewConflictBusinessException("A token with this name already exists")
+	}
+
+	if !req.ExpiresAt.After(now) {
+		return model.PersonalAccessToken{}, "", exception.NewBadRequest("expires_at must be in the future")
+	}
+	if req.ExpiresAt.After(now.Add(cfg.PersonalAccessTokenMaxLifetime)) {
+		return model.PersonalAccessToken{}, "", exception.NewBadRequest("expires_at exceeds the maximum lifetime of " + cfg.PersonalAccessTokenMaxLifetime.String())
+	}
+
+	/*
+		The scope must be a subset of the owner's current permissions
+	*/
+	roles, err := s.roleRepo.FindActiveByUserID(user.ID, now)
+	if err != nil {
+		return model.PersonalAccessToken{}, "", exception.ErrInternal
+	}
+	permissions, err := s.roleRepo.GetPermissionsByRoleIds(extractRoleIDs(roles))
+	if err != nil {
+		return model.PersonalAccessToken{}, "", exception.ErrInternal
+	}
+	scope := grantedScope(req.Scope, permissionNames(permissions), nil)
+	for _, permission := range req.Scope {
+		if !slices.Contains(scope, permission) {
+			return model.PersonalAccessToken{}, "", exception.NewBadRequest(fmt.Sprintf("You don't hold the permission %q", permission))
+		}
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return model.PersonalAccessToken{}, "", exception.ErrInternal
+	}
+	plain := personalAccessTokenPrefix + secret
+
+	token, err := s.personalAccessTokenRepo.Create(model.PersonalAccessToken{
+		UserID:      user.ID,
+		Name:        name,
+		TokenHash:   hashSecret(plain),
+		TokenPrefix: plain[:len(personalAccessTokenPrefix)+8],
+		Scope:       strings.Join(scope, " "),
+		Generation:  user.TokenGeneration,
+		ExpiresAt:   req.ExpiresAt,
+	})
+	if err != nil {
+		return model.PersonalAccessToken{}, "", exception.NewInternal("Failed to save personal access token")
+	}
+
+	s.auditService.Record(c.Request.Context(), AuditPersonalTokenCreated, &user.ID, AuditTargetPersonalToken, strconv.FormatUint(uint64(token.PersonalAccessTokenID), 10), map[string]any{
+		"name":       token.Name,
+		"scope":      scope,
+		"expires_at": token.ExpiresAt,
+	})
+
+	return token, plain, nil
+}
+
+func (s *personalAccessTokenService) Revoke(c *gin.Context, userEmail string, actor *responseDto.ActorResponse, id uint) error {
+	user, err := s.userRepo.FindByEmail(strings.TrimSpace(userEmail))
+	if err != nil {
+		return exception.NewUnauthorizedBusinessException("User not found")
+	}
+	if err := s.rejectDelegated(c, user, actor, "revoke"); err != nil {
+		return err
+	}
+
+	revoked, err := s.personalAccessTokenRepo.Revoke(id, user.ID, time.Now())
+	if err != nil {
+		return exception.NewInternal("Failed to revoke personal access token")
+	}
+	if !revoked {
+		return exception.NewNotFound("Personal access token not found")
+	}
+
+	s.auditService.Record(c.Request.Context(), AuditPersonalTokenRevoked, &user.ID, AuditTargetPersonalToken, strconv.FormatUint(uint64(id), 10), nil)
+
+	return nil
+}
+
+// rejectDelegated refuses to manage personal access tokens with a token used on behalf of the user, which would let
+// an impersonating admin or a service mint credentials that outlive it, and audits the attempt
+func (s *personalAccessTokenService) rejectDelegated(c *gin.Context, user model.User, actor *responseDto.ActorResponse, operation string) error {
+	if actor == nil {
+		return nil
+	}
+
+	var actorID *uint
+	metadata := map[string]any{"operation": operation}
+	if impersonator := actor.Impersonator(); impersonator != nil {
+		actorID = &impersonator.ID
+		metadata["actor_email"] = impersonator.Email
+	}
+	if actor.Subject != "" {
+		metadata["actor_sub"] = actor.Subject
+	}
+	s.auditService.Record(c.Request.Context(), AuditPersonalTokenDenied, actorID, AuditTargetUser, strconv.FormatUint(uint64(user.ID), 10), metadata)
+
+	return exception.New(http.StatusForbidden, "PERSONAL_ACCESS_TOKEN_FORBIDDEN", "Personal access tokens cannot be managed on behalf of the user")
+}
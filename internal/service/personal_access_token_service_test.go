@@ -0,0 +1,250 @@
+package service
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/request"
+	"auth-service/internal/model/dto/response"
+	"auth-service/internal/session"
+	"auth-service/internal/token"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fakePersonalAccessTokenRepository keeps tokens in a slice
+type fakePersonalAccessTokenRepository struct {
+	tokens []model.PersonalAccessToken
+}
+
+func (r *fakePersonalAccessTokenRepository) FindByUserID(userID uint) ([]model.PersonalAccessToken, error) {
+	var tokens []model.PersonalAccessToken
+	for _, pat := range r.tokens {
+		if pat.UserID == userID {
+			tokens = append(tokens, pat)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *fakePersonalAccessTokenRepository) FindByTokenHash(tokenHash string) (model.PersonalAccessToken, error) {
+	for _, pat := range r.tokens {
+		if pat.TokenHash == tokenHash {
+			return pat, nil
+		}
+	}
+	return model.PersonalAccessToken{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakePersonalAccessTokenRepository) ExistsActiveName(userID uint, name string, at time.Time) (bool, error) {
+	for _, pat := range r.tokens {
+		if pat.UserID == userID && pat.Name == name && pat.IsActiveAt(at) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakePersonalAccessTokenRepository) Create(pat model.PersonalAccessToken) (model.PersonalAccessToken, error) {
+	pat.PersonalAccessTokenID = uint(len(r.tokens) + 1)
+	pat.CreatedAt = time.Now()
+	r.tokens = append(r.tokens, pat)
+	return pat, nil
+}
+
+func (r *fakePersonalAccessTokenRepository) Revoke(id uint, userID uint, at time.Time) (bool, error) {
+	for i, pat := range r.tokens {
+		if pat.PersonalAccessTokenID == id && pat.UserID == userID && pat.RevokedAt == nil {
+			r.tokens[i].RevokedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakePersonalAccessTokenRepository) Touch(id uint, at time.Time) error {
+	for i, pat := range r.tokens {
+		if pat.PersonalAccessTokenID == id {
+			r.tokens[i].LastUsedAt = &at
+		}
+	}
+	return nil
+}
+
+// newTestPersonalAccessTokens returns a token service and an auth service sharing the token repository, for a user
+// ada@example.com holding READ_ORDERS and WRITE_ORDERS
+func newTestPersonalAccessTokens(t *testing.T) (PersonalAccessTokenService, AuthService, *fakePersonalAccessTokenRepository) {
+	t.Helper()
+
+	users := &fakeUserRepository{users: newTestUsers(t, "ada@example.com")}
+	roles := &fakeRoleRepository{roles: map[uint][]model.Role{
+		1: {{RoleID: 1, Name: "CLERK", Permissions: []model.Permission{{Name: "READ_ORDERS"}, {Name: "WRITE_ORDERS"}}}},
+	}}
+	repo := &fakePersonalAccessTokenRepository{}
+	audit := &fakeAuditService{}
+
+	revocations := NewRevocationService(&fakeRevocationRepository{})
+	auth := NewAuthService(users, roles, nil, repo, session.NewMemoryStore(time.Minute), token.NewIssuer(token.Validator{}, token.NewJWTFormat("secret")), revocations, NewClaimMappingService(users, &fakeClaimMappingRepository{}, audit), NewAttributeService(users, &fakeAttributeRepository{}, audit), nil, audit)
+	return NewPersonalAccessTokenService(users, roles, repo, audit), auth, repo
+}
+
+func TestPersonalAccessTokenServiceCreate(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		req      requestDTO.CreatePersonalAccessTokenRequest
+		wantCode string
+	}{
+		{"blank name", requestDTO.CreatePersonalAccessTokenRequest{Name: " ", Scope: []string{"READ_ORDERS"}, ExpiresAt: now.Add(time.Hour)}, "BAD_REQUEST"},
+		{"expired", requestDTO.CreatePersonalAccessTokenRequest{Name: "ci", Scope: []string{"READ_ORDERS"}, ExpiresAt: now.Add(-time.Hour)}, "BAD_REQUEST"},
+		{"beyond the maximum lifetime", requestDTO.CreatePersonalAccessTokenRequest{Name: "ci", Scope: []string{"READ_ORDERS"}, ExpiresAt: now.AddDate(10, 0, 0)}, "BAD_REQUEST"},
+		{"permission not held", requestDTO.CreatePersonalAccessTokenRequest{Name: "ci", Scope: []string{"READ_ORDERS", "DELETE_USERS"}, ExpiresAt: now.Add(time.Hour)}, "BAD_REQUEST"},
+		{"name taken", requestDTO.CreatePersonalAccessTokenRequest{Name: "backup", Scope: []string{"READ_ORDERS"}, ExpiresAt: now.Add(time.Hour)}, "CONFLICT"},
+		{"created", requestDTO.CreatePersonalAccessTokenRequest{Name: "ci", Scope: []string{"READ_ORDERS"}, ExpiresAt: now.Add(time.Hour)}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pats, _, repo := newTestPersonalAccessTokens(t)
+			if _, _, err := pats.Create(newTestContext(), "ada@example.com", nil, requestDTO.CreatePersonalAccessTokenRequest{Name: "backup", Scope: []string{"WRITE_ORDERS"}, ExpiresAt: now.Add(time.Hour)}); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			pat, plain, err := pats.Create(newTestContext(), "ada@example.com", nil, tt.req)
+			if code := errorCode(err); code != tt.wantCode {
+				t.Fatalf("Create() code = %q, want %q", code, tt.wantCode)
+			}
+			if tt.wantCode != "" {
+				if len(repo.tokens) != 1 {
+					t.Errorf("refused token was saved: %v", repo.tokens)
+				}
+				return
+			}
+			if !isPersonalAccessToken(plain) || !strings.HasPrefix(plain, pat.TokenPrefix) || pat.TokenHash != hashSecret(plain) {
+				t.Errorf("Create() = %+v, %q", pat, plain)
+			}
+			if pat.Scope != "READ_ORDERS" {
+				t.Errorf("scope = %q, want READ_ORDERS", pat.Scope)
+			}
+		})
+	}
+}
+
+func TestAuthServiceVerifyPersonalAccessToken(t *testing.T) {
+	pats, auth, _ := newTestPersonalAccessTokens(t)
+
+	pat, plain, err := pats.Create(newTestContext(), "ada@example.com", nil, requestDTO.CreatePersonalAccessTokenRequest{Name: "ci", Scope: []string{"READ_ORDERS"}, ExpiresAt: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	data, err := auth.Verify(newTestContext(), plain, "")
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	var record session.Record
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.User.Email != "ada@example.com" || record.User.Roles != "CLERK" || !slices.Equal(record.User.Scope, []string{"READ_ORDERS"}) {
+		t.Errorf("Verify() = %+v", record.User)
+	}
+
+	if _, err := auth.Verify(newTestContext(), plain+"x", ""); err == nil {
+		t.Error("Verify() accepted an unknown personal access token")
+	}
+
+	if err := pats.Revoke(newTestContext(), "ada@example.com", nil, pat.PersonalAccessTokenID); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if _, err := auth.Verify(newTestContext(), plain, ""); err == nil {
+		t.Error("Verify() accepted a revoked personal access token")
+	}
+}
+
+func TestPersonalAccessTokenServiceRejectsDelegatedTokens(t *testing.T) {
+	users := &fakeUserRepository{users: newTestUsers(t, "ada@example.com")}
+	roles := &fakeRoleRepository{roles: map[uint][]model.Role{
+		1: {{RoleID: 1, Name: "CLERK", Permissions: []model.Permission{{Name: "READ_ORDERS"}}}},
+	}}
+	req := requestDTO.CreatePersonalAccessTokenRequest{Name: "ci", Scope: []string{"READ_ORDERS"}, ExpiresAt: time.Now().Add(time.Hour)}
+
+	tests := []struct {
+		name  string
+		actor *responseDto.ActorResponse
+	}{
+		{"impersonating admin", &responseDto.ActorResponse{ID: 9, Email: "admin@example.com"}},
+		{"exchanged by a service", &responseDto.ActorResponse{Subject: "billing-service"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePersonalAccessTokenRepository{}
+			audit := &fakeAuditService{}
+			s := NewPersonalAccessTokenService(users, roles, repo, audit)
+
+			if _, _, err := s.Create(newTestContext(), "ada@example.com", tt.actor, req); errorCode(err) != "PERSONAL_ACCESS_TOKEN_FORBIDDEN" {
+				t.Errorf("Create() error = %v, want PERSONAL_ACCESS_TOKEN_FORBIDDEN", err)
+			}
+			if _, err := s.List(newTestContext(), "ada@example.com", tt.actor); errorCode(err) != "PERSONAL_ACCESS_TOKEN_FORBIDDEN" {
+				t.Errorf("List() error = %v, want PERSONAL_ACCESS_TOKEN_FORBIDDEN", err)
+			}
+			if err := s.Revoke(newTestContext(), "ada@example.com", tt.actor, 1); errorCode(err) != "PERSONAL_ACCESS_TOKEN_FORBIDDEN" {
+				t.Errorf("Revoke() error = %v, want PERSONAL_ACCESS_TOKEN_FORBIDDEN", err)
+			}
+			if len(repo.tokens) != 0 {
+				t.Errorf("tokens created on behalf of the user: %v", repo.tokens)
+			}
+			if !slices.Equal(audit.actions, []string{AuditPersonalTokenDenied, AuditPersonalTokenDenied, AuditPersonalTokenDenied}) {
+				t.Errorf("audited %v", audit.actions)
+			}
+		})
+	}
+}
+
+func TestAuthServiceVerifyPersonalAccessTokenAfterSignOut(t *testing.T) {
+	tests := []struct {
+		name    string
+		signOut func(AuthService) error
+	}{
+		{"sign out everywhere", func(auth AuthService) error {
+			return auth.LogoutAll(newTestContext(), "ada@example.com")
+		}},
+		{"password change", func(auth AuthService) error {
+			_, err := auth.ChangePassword(newTestContext(), "ada@example.com", requestDTO.ChangePasswordRequest{CurrentPassword: "secret", NewPassword: "n3w-secret"})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pats, auth, _ := newTestPersonalAccessTokens(t)
+			req := requestDTO.CreatePersonalAccessTokenRequest{Name: "ci", Scope: []string{"READ_ORDERS"}, ExpiresAt: time.Now().Add(time.Hour)}
+
+			_, before, err := pats.Create(newTestContext(), "ada@example.com", nil, req)
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if err := tt.signOut(auth); err != nil {
+				t.Fatalf("sign out error = %v", err)
+			}
+
+			if _, err := auth.Verify(newTestContext(), before, ""); err == nil {
+				t.Error("Verify() accepted a personal access token created before signing out")
+			}
+
+			req.Name = "ci-again"
+			_, after, err := pats.Create(newTestContext(), "ada@example.com", nil, req)
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if _, err := auth.Verify(newTestContext(), after, ""); err != nil {
+				t.Errorf("Verify() rejected a personal access token created after signing out: %v", err)
+			}
+		})
+	}
+}
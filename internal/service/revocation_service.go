@@ -0,0 +1,139 @@
+package service
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// RevocationService keeps the token denylist. Writes go to the database and the local copy at once; Sync
+// replicates entries written by other instances, so IsRevoked never leaves the process.
+type RevocationService interface {
+	RevokeToken(ctx context.Context, jti string, until time.Time) error
+	RevokeUser(ctx context.Context, userID uint, minGeneration int, until time.Time) error
+	IsRevoked(jti string, userID uint, generation int) bool
+	Sync(ctx context.Context) error
+}
+
+type revocationService struct {
+	revocationRepo repository.RevocationRepository
+
+	mu     sync.RWMutex
+	tokens map[string]time.Time // jti -> until
+	users  map[uint]userCutoff
+}
+
+// userCutoff revokes every token of a user issued under a generation below minGeneration
+type userCutoff struct {
+	minGeneration int
+	until         time.Time
+}
+
+func NewRevocationService(revocationRepo repository.RevocationRepository) RevocationService {
+	return &revocationService{
+		revocationRepo: revocationRepo,
+		tokens:         map[string]time.Time{},
+		users:          map[uint]userCutoff{},
+	}
+}
+
+// RevokeToken denies a single token until it expires
+func (s *revocationService) RevokeToken(ctx context.Context, jti string, until time.Time) error {
+	if _, err := s.revocationRepo.Create(model.RevokedToken{JTI: &jti, ExpiresAt: until}); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addToken(jti, until)
+	return nil
+}
+
+// RevokeUser denies every token of the user issued under a generation below minGeneration, until the newest of them expires
+func (s *revocationService) RevokeUser(ctx context.Context, userID uint, minGeneration int, until time.Time) error {
+	if _, err := s.revocationRepo.Create(model.RevokedToken{UserID: &userID, MinGeneration: &minGeneration, ExpiresAt: until}); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addUser(userID, minGeneration, until)
+	return nil
+}
+
+func (s *revocationService) IsRevoked(jti string, userID uint, generation int) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	if until, ok := s.tokens[jti]; ok && now.Before(until) {
+		return true
+	}
+	if cutoff, ok := s.users[userID]; ok && now.Before(cutoff.until) && generation < cutoff.minGeneration {
+		return true
+	}
+	return false
+}
+
+// Sync merges the live entries of every instance into the local copy, drops expired ones and purges them from the
+// database. Merging rather than replacing keeps entries revoked locally while the snapshot was being read.
+func (s *revocationService) Sync(ctx context.Context) error {
+	now := time.Now()
+
+	entries, err := s.revocationRepo.FindLive(now)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	for jti, until := range s.tokens {
+		if !now.Before(until) {
+			delete(s.tokens, jti)
+		}
+	}
+	for userID, cutoff := range s.users {
+		if !now.Before(cutoff.until) {
+			delete(s.users, userID)
+		}
+	}
+	for _, entry := range entries {
+		if entry.JTI != nil {
+			s.addToken(*entry.JTI, entry.ExpiresAt)
+		}
+		if entry.UserID != nil && entry.MinGeneration != nil {
+			s.addUser(*entry.UserID, *entry.MinGeneration, entry.ExpiresAt)
+		}
+	}
+	s.mu.Unlock()
+
+	purged, err := s.revocationRepo.DeleteExpired(now)
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		slog.DebugContext(ctx, "purged expired denylist entries", "count", purged)
+	}
+	return nil
+}
+
+// addToken records a token entry; the caller holds the lock
+func (s *revocationService) addToken(jti string, until time.Time) {
+	if until.After(s.tokens[jti]) {
+		s.tokens[jti] = until
+	}
+}
+
+// addUser merges a user cutoff, keeping the highest generation and the latest end; the caller holds the lock
+func (s *revocationService) addUser(userID uint, minGeneration int, until time.Time) {
+	cutoff := s.users[userID]
+	if minGeneration > cutoff.minGeneration {
+		cutoff.minGeneration = minGeneration
+	}
+	if until.After(cutoff.until) {
+		cutoff.until = until
+	}
+	s.users[userID] = cutoff
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeRevocationRepository keeps denylist entries in memory
+type fakeRevocationRepository struct {
+	repository.RevocationRepository
+	entries []model.RevokedToken
+}
+
+func (r *fakeRevocationRepository) Create(entry model.RevokedToken) (model.RevokedToken, error) {
+	r.entries = append(r.entries, entry)
+	return entry, nil
+}
+
+func (r *fakeRevocationRepository) FindLive(at time.Time) ([]model.RevokedToken, error) {
+	var live []model.RevokedToken
+	for _, entry := range r.entries {
+		if entry.ExpiresAt.After(at) {
+			live = append(live, entry)
+		}
+	}
+	return live, nil
+}
+
+func (r *fakeRevocationRepository) DeleteExpired(at time.Time) (int64, error) {
+	live, _ := r.FindLive(at)
+	purged := int64(len(r.entries) - len(live))
+	r.entries = live
+	return purged, nil
+}
+
+func TestRevocationServiceIsRevoked(t *testing.T) {
+	now := time.Now()
+
+	s := NewRevocationService(nil).(*revocationService)
+	s.addToken("revoked", now.Add(time.Hour))
+	s.addToken("lapsed", now.Add(-time.Minute))
+	s.addUser(1, 3, now.Add(time.Hour))
+	s.addUser(2, 5, now.Add(-time.Minute))
+
+	tests := []struct {
+		name       string
+		jti        string
+		userID     uint
+		generation int
+		want       bool
+	}{
+		{"live token", "live", 9, 0, false},
+		{"denied token", "revoked", 9, 0, true},
+		{"token denied until it expired", "lapsed", 9, 0, false},
+		{"older generation of a cut off user", "live", 1, 2, true},
+		{"cutoff generation of a cut off user", "live", 1, 3, false},
+		{"newer generation of a cut off user", "live", 1, 4, false},
+		{"older generation after the cutoff lapsed", "live", 2, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.IsRevoked(tt.jti, tt.userID, tt.generation); got != tt.want {
+				t.Errorf("IsRevoked(%q, %d, %d) = %v, want %v", tt.jti, tt.userID, tt.generation, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevocationServiceAddUser(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		cutoffs []userCutoff
+		want    userCutoff
+	}{
+		{
+			name:    "single cutoff",
+			cutoffs: []userCutoff{{3, now.Add(time.Hour)}},
+			want:    userCutoff{3, now.Add(time.Hour)},
+		},
+		{
+			name:    "newer cutoff raises the generation and extends the end",
+			cutoffs: []userCutoff{{3, now.Add(time.Hour)}, {4, now.Add(2 * time.Hour)}},
+			want:    userCutoff{4, now.Add(2 * time.Hour)},
+		},
+		{
+			name:    "older cutoff synced late keeps the highest generation",
+			cutoffs: []userCutoff{{4, now.Add(time.Hour)}, {3, now.Add(2 * time.Hour)}},
+			want:    userCutoff{4, now.Add(2 * time.Hour)},
+		},
+		{
+			name:    "shorter cutoff keeps the latest end",
+			cutoffs: []userCutoff{{3, now.Add(2 * time.Hour)}, {4, now.Add(time.Hour)}},
+			want:    userCutoff{4, now.Add(2 * time.Hour)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewRevocationService(nil).(*revocationService)
+			for _, cutoff := range tt.cutoffs {
+				s.addUser(7, cutoff.minGeneration, cutoff.until)
+			}
+			if got := s.users[7]; got.minGeneration != tt.want.minGeneration || !got.until.Equal(tt.want.until) {
+				t.Errorf("addUser() cutoff = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevocationServiceSync(t *testing.T) {
+	now := time.Now()
+	jti := func(s string) *string { return &s }
+	id := func(n uint) *uint { return &n }
+	generation := func(n int) *int { return &n }
+
+	// Entries written by other instances
+	repo := &fakeRevocationRepository{entries: []model.RevokedToken{
+		{JTI: jti("revoked elsewhere"), ExpiresAt: now.Add(time.Hour)},
+		{JTI: jti("expired"), ExpiresAt: now.Add(-time.Minute)},
+		{UserID: id(1), MinGeneration: generation(3), ExpiresAt: now.Add(time.Hour)},
+	}}
+	s := NewRevocationService(repo)
+
+	if s.IsRevoked("revoked elsewhere", 9, 0) {
+		t.Fatal("IsRevoked() before the first sync = true")
+	}
+	if err := s.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	if !s.IsRevoked("revoked elsewhere", 9, 0) {
+		t.Error("token revoked by another instance is not denied after Sync()")
+	}
+	if !s.IsRevoked("live", 1, 2) {
+		t.Error("user cut off by another instance is not denied after Sync()")
+	}
+	if len(repo.entries) != 2 {
+		t.Errorf("Sync() left %d entries, want the expired one purged", len(repo.entries))
+	}
+}
+
+func TestRevocationServiceSyncKeepsLocalEntries(t *testing.T) {
+	now := time.Now()
+
+	// The snapshot was read before this instance revoked anything
+	s := NewRevocationService(&fakeRevocationRepository{}).(*revocationService)
+	s.addToken("revoked meanwhile", now.Add(time.Hour))
+	s.addToken("lapsed", now.Add(-time.Minute))
+	s.addUser(1, 3, now.Add(time.Hour))
+	s.addUser(2, 5, now.Add(-time.Minute))
+
+	if err := s.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	if !s.IsRevoked("revoked meanwhile", 9, 0) {
+		t.Error("token revoked locally is no longer denied after Sync()")
+	}
+	if !s.IsRevoked("live", 1, 2) {
+		t.Error("user cut off locally is no longer denied after Sync()")
+	}
+	if _, ok := s.tokens["lapsed"]; ok {
+		t.Error("Sync() kept an expired token entry")
+	}
+	if _, ok := s.users[2]; ok {
+		t.Error("Sync() kept an expired user cutoff")
+	}
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/request"
+	"auth-service/internal/repository"
+	"auth-service/pkg/utils/exception"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RoleGrantService interface {
+	GrantRole(c *gin.Context, actorEmail string, req requestDTO.GrantRoleRequest) (model.UserRole, error)
+	RevokeRole(c *gin.Context, actorEmail string, req requestDTO.RevokeRoleRequest) error
+	ListGrants(c *gin.Context, userID uint) ([]model.UserRole, error)
+	SyncGrants(ctx context.Context) error
+}
+
+type roleGrantService struct {
+	userRepo     repository.UserRepository
+	roleRepo     repository.RoleRepository
+	userRoleRepo repository.UserRoleRepository
+	auditService AuditService
+	tokenRevoker TokenRevoker
+}
+
+func NewRoleGrantService(userRepo repository.UserRepository, roleRepo repository.RoleRepository, userRoleRepo repository.UserRoleRepository, auditService AuditService, tokenRevoker TokenRevoker) RoleGrantService {
+	return &roleGrantService{userRepo, roleRepo, userRoleRepo, auditService, tokenRevoker}
+}
+
+func (s *roleGrantService) GrantRole(c *gin.Context, actorEmail string, req requestDTO.GrantRoleRequest) (model.UserRole, error) {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return model.UserRole{}, exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	if _, err := s.userRepo.FindByID(req.UserID); err != nil {
+		return model.UserRole{}, exception.NewNotFound("User not found")
+	}
+
+	role, err := s.roleRepo.FindByID(req.RoleID)
+	if err != nil {
+		return model.UserRole{}, exception.NewNotFound("Role not found")
+	}
+
+	now := time.Now()
+	validFrom, validUntil, err := resolveGrantWindow(now, req.ValidFrom, req.ValidUntil, req.Duration)
+	if err != nil {
+		return model.UserRole{}, err
+	}
+
+	/*
+		A grant the user still holds is only ever widened, never shortened; an expired one not removed yet is replaced
+	*/
+	var activatedAt *time.Time
+	existing, err := s.userRoleRepo.Find(req.UserID, req.RoleID)
+	switch {
+	case err == nil && (existing.ValidUntil == nil || existing.ValidUntil.After(now)):
+		validFrom, validUntil, err = widenGrantWindow(existing, validFrom, validUntil)
+		if err != nil {
+			return model.UserRole{}, err
+		}
+		activatedAt = existing.ActivatedAt
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		return model.UserRole{}, exception.NewInternal("Failed to load role grant")
+	}
+
+	grant := model.UserRole{
+		UserID:     req.UserID,
+		RoleID:     req.RoleID,
+		ValidFrom:  validFrom,
+		ValidUntil: validUntil,
+		GrantedBy:  &actor.ID,
+	}
+	activated := false
+	if grant.IsActiveAt(now) {
+		if activatedAt == nil {
+			activatedAt = &now
+			activated = true
+		}
+		grant.ActivatedAt = activatedAt
+	}
+
+	grant, err = s.userRoleRepo.Upsert(grant)
+	if err != nil {
+		return model.UserRole{}, exception.NewInternal("Failed to save role grant")
+	}
+	grant.Role = role
+
+	metadata := grantMetadata(grant)
+	metadata["reason"] = req.Reason
+	s.auditService.Record(c.Request.Context(), AuditRoleGranted, &actor.ID, AuditTargetUserRole, grantTargetID(grant), metadata)
+	if activated {
+		s.auditService.Record(c.Request.Context(), AuditRoleGrantActivated, &actor.ID, AuditTargetUserRole, grantTargetID(grant), grantMetadata(grant))
+		s.revokeTokens(c.Request.Context(), grant, &actor.ID, AuditRoleGrantActivated)
+	}
+
+	return grant, nil
+}
+
+func (s *roleGrantService) RevokeRole(c *gin.Context, actorEmail string, req requestDTO.RevokeRoleRequest) error {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	grant, err := s.userRoleRepo.Find(req.UserID, req.RoleID)
+	if err != nil {
+		return exception.NewNotFound("Role grant not found")
+	}
+
+	if err := s.userRoleRepo.Delete(req.UserID, req.RoleID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exception.NewNotFound("Role grant not found")
+		}
+		return exception.NewInternal("Failed to revoke role grant")
+	}
+
+	metadata := grantMetadata(grant)
+	metadata["reason"] = req.Reason
+	s.auditService.Record(c.Request.Context(), AuditRoleRevoked, &actor.ID, AuditTargetUserRole, grantTargetID(grant), metadata)
+	s.revokeTokens(c.Request.Context(), grant, &actor.ID, AuditRoleRevoked)
+
+	return nil
+}
+
+func (s *roleGrantService) ListGrants(c *gin.Context, userID uint) ([]model.UserRole, error) {
+	grants, err := s.userRoleRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, exception.NewInternal("Failed to load role grants")
+	}
+	return grants, nil
+}
+
+// SyncGrants records activations of grants whose window has opened and removes grants whose window has closed.
+// It is safe to run concurrently from several instances: each transition is claimed in the database first.
+func (s *roleGrantService) SyncGrants(ctx context.Context) error {
+	now := time.Now()
+
+	pending, err := s.userRoleRepo.FindPendingActivation(now)
+	if err != nil {
+		return err
+	}
+	for _, grant := range pending {
+		claimed, err := s.userRoleRepo.MarkActivated(grant.UserID, grant.RoleID, now)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+		grant.ActivatedAt = &now
+		s.auditService.Record(ctx, AuditRoleGrantActivated, nil, AuditTargetUserRole, grantTargetID(grant), grantMetadata(grant))
+		s.revokeTokens(ctx, grant, nil, AuditRoleGrantActivated)
+	}
+
+	expired, err := s.userRoleRepo.FindExpired(now)
+	if err != nil {
+		return err
+	}
+	for _, grant := range expired {
+		if err := s.userRoleRepo.Delete(grant.UserID, grant.RoleID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return err
+		}
+		s.auditService.Record(ctx, AuditRoleGrantExpired, nil, AuditTargetUserRole, grantTargetID(grant), grantMetadata(grant))
+		s.revokeTokens(ctx, grant, nil, AuditRoleGrantExpired)
+	}
+
+	if len(pending) > 0 || len(expired) > 0 {
+		slog.InfoContext(ctx, "role grants synchronized",
+			"activated", len(pending),
+			"expired", len(expired),
+		)
+	}
+
+	return nil
+}
+
+// revokeTokens ends the user's sessions so that their next token carries the changed roles
+func (s *roleGrantService) revokeTokens(ctx context.Context, grant model.UserRole, actorID *uint, reason string) {
+	if err := s.tokenRevoker.RevokeAllTokens(ctx, grant.UserID, actorID, strings.ToLower(reason)); err != nil {
+		slog.ErrorContext(ctx, "failed to revoke tokens after role change",
+			"userId", grant.UserID,
+			"roleId", grant.RoleID,
+			"error", err,
+		)
+	}
+}
+
+// resolveGrantWindow validates the requested window. A duration is counted from validFrom, or from now when validFrom is empty.
+func resolveGrantWindow(now time.Time, validFrom *time.Time, validUntil *time.Time, duration string) (*time.Time, *time.Time, error) {
+	if duration != "" {
+		if validUntil != nil {
+			return nil, nil, exception.NewBadRequest("Specify either valid_until or duration, not both")
+		}
+		d, err := time.ParseDuration(duration)
+		if err != nil || d <= 0 {
+			return nil, nil, exception.NewBadRequest("Invalid duration")
+		}
+		start := now
+		if validFrom != nil {
+			start = *validFrom
+		}
+		end := start.Add(d)
+		validUntil = &end
+	}
+
+	if validUntil != nil {
+		if !validUntil.After(now) {
+			return nil, nil, exception.NewBadRequest("valid_until must be in the future")
+		}
+		if validFrom != nil && !validUntil.After(*validFrom) {
+			return nil, nil, exception.NewBadRequest("valid_until must be after valid_from")
+		}
+	}
+
+	return validFrom, validUntil, nil
+}
+
+// widenGrantWindow merges the requested window into the existing grant's, keeping the earlier start and the later end
+// (a nil end being permanent). It refuses requests the existing grant already covers, and windows that don't overlap
+// it, whose union would grant the role in between.
+func widenGrantWindow(existing model.UserRole, validFrom *time.Time, validUntil *time.Time) (*time.Time, *time.Time, error) {
+	startsFirst := existing.ValidFrom == nil || (validFrom != nil && !existing.ValidFrom.After(*validFrom))
+	endsLast := existing.ValidUntil == nil || (validUntil != nil && !existing.ValidUntil.Before(*validUntil))
+	if startsFirst && endsLast {
+		if existing.ValidUntil == nil {
+			return nil, nil, exception.NewConflictBusinessException("User already holds this role permanently")
+		}
+		return nil, nil, exception.NewConflictBusinessException("User already holds this role until " + existing.ValidUntil.Format(time.RFC3339))
+	}
+
+	if (validUntil != nil && existing.ValidFrom != nil && validUntil.Before(*existing.ValidFrom)) ||
+		(existing.ValidUntil != nil && validFrom != nil && existing.ValidUntil.Before(*validFrom)) {
+		return nil, nil, exception.NewConflictBusinessException("User holds this role in another window; revoke it first")
+	}
+
+	if startsFirst {
+		validFrom = existing.ValidFrom
+	}
+	if endsLast {
+		validUntil = existing.ValidUntil
+	}
+	return validFrom, validUntil, nil
+}
+
+func grantTargetID(grant model.UserRole) string {
+	return fmt.Sprintf("%d:%d", grant.UserID, grant.RoleID)
+}
+
+func grantMetadata(grant model.UserRole) map[string]any {
+	return map[string]any{
+		"user_id":     grant.UserID,
+		"role_id":     grant.RoleID,
+		"role_name":   grant.Role.Name,
+		"valid_from":  grant.ValidFrom,
+		"valid_until": grant.ValidUntil,
+		"activated":   grant.ActivatedAt != nil,
+	}
+}
@@ -0,0 +1,356 @@
+package service
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/request"
+	"auth-service/internal/repository"
+	"auth-service/pkg/utils/exception"
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fakeAuditService records the actions it is asked to audit
+type fakeAuditService struct {
+	AuditService
+	actions []string
+}
+
+func (s *fakeAuditService) Record(ctx context.Context, action string, actorID *uint, targetType string, targetID string, metadata map[string]any) {
+	s.actions = append(s.actions, action)
+}
+
+func (s *fakeAuditService) Alert(ctx context.Context, action string, actorID *uint, targetType string, targetID string, metadata map[string]any) {
+	s.actions = append(s.actions, action)
+}
+
+// fakeTokenRevoker records the users whose tokens it was asked to revoke
+type fakeTokenRevoker struct {
+	userIDs []uint
+}
+
+func (r *fakeTokenRevoker) RevokeAllTokens(ctx context.Context, userID uint, actorID *uint, reason string) error {
+	r.userIDs = append(r.userIDs, userID)
+	return nil
+}
+
+// fakeUserRoleRepository keeps grants in memory, keyed by user and role
+type fakeUserRoleRepository struct {
+	repository.UserRoleRepository
+	grants map[[2]uint]model.UserRole
+}
+
+func newFakeUserRoleRepository(grants ...model.UserRole) *fakeUserRoleRepository {
+	r := &fakeUserRoleRepository{grants: map[[2]uint]model.UserRole{}}
+	for _, grant := range grants {
+		r.grants[[2]uint{grant.UserID, grant.RoleID}] = grant
+	}
+	return r
+}
+
+func (r *fakeUserRoleRepository) Upsert(userRole model.UserRole) (model.UserRole, error) {
+	r.grants[[2]uint{userRole.UserID, userRole.RoleID}] = userRole
+	return userRole, nil
+}
+
+func (r *fakeUserRoleRepository) Find(userID uint, roleID uint) (model.UserRole, error) {
+	grant, ok := r.grants[[2]uint{userID, roleID}]
+	if !ok {
+		return model.UserRole{}, gorm.ErrRecordNotFound
+	}
+	return grant, nil
+}
+
+func (r *fakeUserRoleRepository) FindByUserID(userID uint) ([]model.UserRole, error) {
+	var grants []model.UserRole
+	for _, grant := range r.grants {
+		if grant.UserID == userID {
+			grants = append(grants, grant)
+		}
+	}
+	return grants, nil
+}
+
+func (r *fakeUserRoleRepository) FindPendingActivation(at time.Time) ([]model.UserRole, error) {
+	var pending []model.UserRole
+	for _, grant := range r.grants {
+		if grant.ActivatedAt == nil && grant.IsActiveAt(at) {
+			pending = append(pending, grant)
+		}
+	}
+	return pending, nil
+}
+
+func (r *fakeUserRoleRepository) FindExpired(at time.Time) ([]model.UserRole, error) {
+	var expired []model.UserRole
+	for _, grant := range r.grants {
+		if grant.ValidUntil != nil && !grant.ValidUntil.After(at) {
+			expired = append(expired, grant)
+		}
+	}
+	return expired, nil
+}
+
+func (r *fakeUserRoleRepository) MarkActivated(userID uint, roleID uint, at time.Time) (bool, error) {
+	grant, ok := r.grants[[2]uint{userID, roleID}]
+	if !ok || grant.ActivatedAt != nil {
+		return false, nil
+	}
+	grant.ActivatedAt = &at
+	r.grants[[2]uint{userID, roleID}] = grant
+	return true, nil
+}
+
+func (r *fakeUserRoleRepository) Delete(userID uint, roleID uint) error {
+	if _, ok := r.grants[[2]uint{userID, roleID}]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.grants, [2]uint{userID, roleID})
+	return nil
+}
+
+func at(t time.Time) *time.Time {
+	return &t
+}
+
+func TestResolveGrantWindow(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		validFrom  *time.Time
+		validUntil *time.Time
+		duration   string
+		wantFrom   *time.Time
+		wantUntil  *time.Time
+		wantErr    bool
+	}{
+		{name: "permanent grant"},
+		{name: "scheduled permanent grant", validFrom: at(now.Add(time.Hour)), wantFrom: at(now.Add(time.Hour))},
+		{name: "duration from now", duration: "2h", wantUntil: at(now.Add(2 * time.Hour))},
+		{name: "duration from valid_from", validFrom: at(now.Add(time.Hour)), duration: "30m", wantFrom: at(now.Add(time.Hour)), wantUntil: at(now.Add(90 * time.Minute))},
+		{name: "explicit window", validFrom: at(now.Add(-time.Hour)), validUntil: at(now.Add(time.Hour)), wantFrom: at(now.Add(-time.Hour)), wantUntil: at(now.Add(time.Hour))},
+		{name: "duration and valid_until", validUntil: at(now.Add(time.Hour)), duration: "1h", wantErr: true},
+		{name: "unparsable duration", duration: "soon", wantErr: true},
+		{name: "zero duration", duration: "0s", wantErr: true},
+		{name: "negative duration", duration: "-1h", wantErr: true},
+		{name: "valid_until in the past", validUntil: at(now.Add(-time.Minute)), wantErr: true},
+		{name: "valid_until now", validUntil: at(now), wantErr: true},
+		{name: "valid_until before valid_from", validFrom: at(now.Add(2 * time.Hour)), validUntil: at(now.Add(time.Hour)), wantErr: true},
+		{name: "duration ending in the past", validFrom: at(now.Add(-2 * time.Hour)), duration: "1h", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, until, err := resolveGrantWindow(now, tt.validFrom, tt.validUntil, tt.duration)
+			if tt.wantErr {
+				var appErr *exception.AppError
+				if !errors.As(err, &appErr) || appErr.StatusCode != 400 {
+					t.Fatalf("resolveGrantWindow() error = %v, want a bad request", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveGrantWindow() error = %v", err)
+			}
+			if !reflect.DeepEqual(from, tt.wantFrom) || !reflect.DeepEqual(until, tt.wantUntil) {
+				t.Errorf("resolveGrantWindow() = %v, %v, want %v, %v", from, until, tt.wantFrom, tt.wantUntil)
+			}
+		})
+	}
+}
+
+func TestRoleGrantServiceSyncGrants(t *testing.T) {
+	now := time.Now()
+
+	repo := newFakeUserRoleRepository(
+		model.UserRole{UserID: 1, RoleID: 1, ActivatedAt: at(now.Add(-time.Hour))},
+		model.UserRole{UserID: 2, RoleID: 2, ValidFrom: at(now.Add(-time.Minute)), ValidUntil: at(now.Add(time.Hour))},
+		model.UserRole{UserID: 3, RoleID: 3, ValidFrom: at(now.Add(time.Hour))},
+		model.UserRole{UserID: 4, RoleID: 4, ValidUntil: at(now.Add(-time.Minute)), ActivatedAt: at(now.Add(-time.Hour))},
+		model.UserRole{UserID: 5, RoleID: 5, ValidFrom: at(now.Add(-time.Minute)), ActivatedAt: at(now.Add(-time.Minute))},
+	)
+	audit := &fakeAuditService{}
+	revoker := &fakeTokenRevoker{}
+	s := NewRoleGrantService(nil, nil, repo, audit, revoker)
+
+	if err := s.SyncGrants(context.Background()); err != nil {
+		t.Fatalf("SyncGrants() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		roleID    uint
+		kept      bool
+		activated bool
+	}{
+		{"permanent grant is left alone", 1, true, true},
+		{"opened window is activated", 2, true, true},
+		{"scheduled grant waits", 3, true, false},
+		{"closed window is removed", 4, false, false},
+		{"activated grant stays activated", 5, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grant, kept := repo.grants[[2]uint{tt.roleID, tt.roleID}]
+			if kept != tt.kept {
+				t.Fatalf("grant kept = %v, want %v", kept, tt.kept)
+			}
+			if kept && (grant.ActivatedAt != nil) != tt.activated {
+				t.Errorf("grant activated = %v, want %v", grant.ActivatedAt != nil, tt.activated)
+			}
+		})
+	}
+
+	want := []string{AuditRoleGrantActivated, AuditRoleGrantExpired}
+	if !reflect.DeepEqual(audit.actions, want) {
+		t.Errorf("audited %v, want %v", audit.actions, want)
+	}
+	// Users whose roles changed must sign in again to pick them up
+	if want := []uint{2, 4}; !reflect.DeepEqual(revoker.userIDs, want) {
+		t.Errorf("revoked tokens of users %v, want %v", revoker.userIDs, want)
+	}
+
+	// A second run finds nothing left to do
+	audit.actions = nil
+	if err := s.SyncGrants(context.Background()); err != nil {
+		t.Fatalf("SyncGrants() error = %v", err)
+	}
+	if len(audit.actions) != 0 {
+		t.Errorf("second run audited %v, want nothing", audit.actions)
+	}
+}
+
+func TestWidenGrantWindow(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	hours := func(n int) *time.Time { return at(now.Add(time.Duration(n) * time.Hour)) }
+
+	tests := []struct {
+		name       string
+		existing   model.UserRole
+		validFrom  *time.Time
+		validUntil *time.Time
+		wantFrom   *time.Time
+		wantUntil  *time.Time
+		wantErr    bool
+	}{
+		{name: "permanent grant not shortened", existing: model.UserRole{}, validUntil: hours(2), wantErr: true},
+		{name: "later end not shortened", existing: model.UserRole{ValidUntil: hours(48)}, validUntil: hours(2), wantErr: true},
+		{name: "same window", existing: model.UserRole{ValidUntil: hours(2)}, validUntil: hours(2), wantErr: true},
+		{name: "extended", existing: model.UserRole{ValidUntil: hours(2)}, validUntil: hours(48), wantUntil: hours(48)},
+		{name: "made permanent", existing: model.UserRole{ValidUntil: hours(2)}, wantUntil: nil},
+		{name: "scheduled grant brought forward", existing: model.UserRole{ValidFrom: hours(24), ValidUntil: hours(48)}, validUntil: hours(30), wantUntil: hours(48)},
+		{name: "earlier start keeps the later end", existing: model.UserRole{ValidFrom: hours(24)}, validFrom: hours(12), validUntil: hours(30), wantFrom: hours(12)},
+		{name: "window before the existing one", existing: model.UserRole{ValidFrom: hours(24), ValidUntil: hours(48)}, validUntil: hours(2), wantErr: true},
+		{name: "window after the existing one", existing: model.UserRole{ValidUntil: hours(2)}, validFrom: hours(24), validUntil: hours(48), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, until, err := widenGrantWindow(tt.existing, tt.validFrom, tt.validUntil)
+			if tt.wantErr {
+				if errorCode(err) != "CONFLICT" {
+					t.Fatalf("widenGrantWindow() error = %v, want a conflict", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("widenGrantWindow() error = %v", err)
+			}
+			if !reflect.DeepEqual(from, tt.wantFrom) || !reflect.DeepEqual(until, tt.wantUntil) {
+				t.Errorf("widenGrantWindow() = %v, %v; want %v, %v", from, until, tt.wantFrom, tt.wantUntil)
+			}
+		})
+	}
+}
+
+func TestRoleGrantServiceGrantRoleNeverDowngrades(t *testing.T) {
+	now := time.Now()
+	users := &fakeUserRepository{users: []model.User{{ID: 1, Email: "admin@example.com"}, {ID: 2, Email: "ada@example.com"}}}
+	roles := &fakeRoleRepository{roles: map[uint][]model.Role{2: {{RoleID: 5, Name: "AUDITOR"}}}}
+
+	tests := []struct {
+		name          string
+		existing      *model.UserRole
+		req           requestDTO.GrantRoleRequest
+		wantCode      string
+		wantUntil     *time.Time
+		wantActivated bool
+	}{
+		{
+			name:          "first grant",
+			req:           requestDTO.GrantRoleRequest{UserID: 2, RoleID: 5, Duration: "1h"},
+			wantUntil:     at(now.Add(time.Hour)),
+			wantActivated: true,
+		},
+		{
+			name:     "permanent grant kept",
+			existing: &model.UserRole{UserID: 2, RoleID: 5, ActivatedAt: at(now.Add(-time.Hour))},
+			req:      requestDTO.GrantRoleRequest{UserID: 2, RoleID: 5, Duration: "1h"},
+			wantCode: "CONFLICT",
+		},
+		{
+			name:     "longer grant kept",
+			existing: &model.UserRole{UserID: 2, RoleID: 5, ValidUntil: at(now.Add(48 * time.Hour)), ActivatedAt: at(now.Add(-time.Hour))},
+			req:      requestDTO.GrantRoleRequest{UserID: 2, RoleID: 5, Duration: "1h"},
+			wantCode: "CONFLICT",
+		},
+		{
+			name:      "active grant extended without reactivation",
+			existing:  &model.UserRole{UserID: 2, RoleID: 5, ValidUntil: at(now.Add(time.Hour)), ActivatedAt: at(now.Add(-time.Hour))},
+			req:       requestDTO.GrantRoleRequest{UserID: 2, RoleID: 5, Duration: "48h"},
+			wantUntil: at(now.Add(48 * time.Hour)),
+		},
+		{
+			name:          "expired grant replaced",
+			existing:      &model.UserRole{UserID: 2, RoleID: 5, ValidUntil: at(now.Add(-time.Minute)), ActivatedAt: at(now.Add(-time.Hour))},
+			req:           requestDTO.GrantRoleRequest{UserID: 2, RoleID: 5, Duration: "1h"},
+			wantUntil:     at(now.Add(time.Hour)),
+			wantActivated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeUserRoleRepository()
+			if tt.existing != nil {
+				repo = newFakeUserRoleRepository(*tt.existing)
+			}
+			audit := &fakeAuditService{}
+			revoker := &fakeTokenRevoker{}
+			s := NewRoleGrantService(users, roles, repo, audit, revoker)
+
+			_, err := s.GrantRole(newTestContext(), "admin@example.com", tt.req)
+			if code := errorCode(err); code != tt.wantCode {
+				t.Fatalf("GrantRole() code = %q, want %q", code, tt.wantCode)
+			}
+
+			stored := repo.grants[[2]uint{2, 5}]
+			if tt.wantCode != "" {
+				if !reflect.DeepEqual(stored, *tt.existing) || len(audit.actions) != 0 {
+					t.Errorf("refused grant changed %+v or audited %v", stored, audit.actions)
+				}
+				return
+			}
+			if stored.ValidUntil == nil || stored.ValidUntil.Sub(*tt.wantUntil).Abs() > time.Second {
+				t.Errorf("valid_until = %v, want %v", stored.ValidUntil, tt.wantUntil)
+			}
+
+			wantActions := []string{AuditRoleGranted}
+			var wantRevoked []uint
+			if tt.wantActivated {
+				wantActions = append(wantActions, AuditRoleGrantActivated)
+				wantRevoked = []uint{2}
+			}
+			if !slices.Equal(audit.actions, wantActions) || !slices.Equal(revoker.userIDs, wantRevoked) {
+				t.Errorf("audited %v and revoked %v, want %v and %v", audit.actions, revoker.userIDs, wantActions, wantRevoked)
+			}
+		})
+	}
+}
@@ -0,0 +1,458 @@
+package service
+
+import (
+	"auth-service/internal/clientcert"
+	"auth-service/internal/config"
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/request"
+	responseDto "auth-service/internal/model/dto/response"
+	"auth-service/internal/nonce"
+	"auth-service/internal/repository"
+	"auth-service/internal/session"
+	"auth-service/pkg/apikey"
+	"auth-service/pkg/utils/exception"
+	"crypto/hmac"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serviceAccountDomain is the reserved domain of service account addresses, which can never receive mail
+const serviceAccountDomain = "@service-accounts.invalid"
+
+// serviceAccountKeyPrefix marks API key IDs
+const serviceAccountKeyPrefix = "ak_"
+
+// SignedRequest is a request signed with a service account API key, as forwarded to introspection by the gateway.
+// Service and Endpoint are the target the request is authorized for; BodySHA256 is the hex-encoded SHA-256 of the body.
+type SignedRequest struct {
+	Authorization string
+	Method        string
+	Service       string
+	Endpoint      string
+	RequestURI    string
+	Timestamp     string
+	BodySHA256    string
+}
+
+// ServiceAccountService manages service accounts, the non-human principals of integrations and internal services,
+// with their API keys and client certificates. Roles are granted to service accounts through the role grants like to any user.
+type ServiceAccountService interface {
+	List(c *gin.Context) ([]model.User, error)
+	Create(c *gin.Context, actorEmail string, req requestDTO.CreateServiceAccountRequest) (model.User, error)
+	Delete(c *gin.Context, actorEmail string, id uint) error
+	ListKeys(c *gin.Context, id uint) ([]model.ServiceAccountKey, error)
+	// CreateKey returns the new key and its secret, which is only ever shown this once
+	CreateKey(c *gin.Context, actorEmail string, id uint, req requestDTO.CreateServiceAccountKeyRequest) (model.ServiceAccountKey, string, error)
+	RevokeKey(c *gin.Context, actorEmail string, id uint, keyID uint) error
+	ListCertificates(c *gin.Context, id uint) ([]model.ServiceAccountCertificate, error)
+	AddCertificate(c *gin.Context, actorEmail string, id uint, req requestDTO.AddServiceAccountCertificateRequest) (model.ServiceAccountCertificate, error)
+	RemoveCertificate(c *gin.Context, actorEmail string, id uint, certificateID uint) error
+	// Authenticate verifies a signed request and returns the session record of its service account, like AuthService.Verify
+	Authenticate(c *gin.Context, req SignedRequest) (string, error)
+	// AuthenticateCertificate returns the session record of the service account a client certificate is mapped to
+	AuthenticateCertificate(c *gin.Context, cert clientcert.Certificate) (string, error)
+}
+
+type serviceAccountService struct {
+	userRepo                      repository.UserRepository
+	roleRepo                      repository.RoleRepository
+	serviceAccountKeyRepo         repository.ServiceAccountKeyRepository
+	serviceAccountCertificateRepo repository.ServiceAccountCertificateRepository
+	auditService                  AuditService
+	nonces                        nonce.Store
+}
+
+func NewServiceAccountService(userRepo repository.UserRepository, roleRepo repository.RoleRepository, serviceAccountKeyRepo repository.ServiceAccountKeyRepository, serviceAccountCertificateRepo repository.ServiceAccountCertificateRepository, auditService AuditService, nonces nonce.Store) ServiceAccountService {
+	return &serviceAccountService{userRepo, roleRepo, serviceAccountKeyRepo, serviceAccountCertificateRepo, auditService, nonces}
+}
+
+func (s *serviceAccountService) List(c *gin.Context) ([]model.User, error) {
+	accounts, err := s.userRepo.FindByAccountType(model.AccountTypeService)
+	if err != nil {
+		return nil, exception.ErrInternal
+	}
+	return accounts, nil
+}
+
+func (s *serviceAccountService) Create(c *gin.Context, actorEmail string, req requestDTO.CreateServiceAccountRequest) (model.User, error) {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return model.User{}, exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	name := strings.ToLower(strings.TrimSpace(req.Name))
+	if !validServiceAccountName(name) {
+		return model.User{}, exception.NewBadRequest("Name may only contain letters, digits, '.', '-' and '_'")
+	}
+	email := name + serviceAccountDomain
+	if _, err := s.userRepo.FindByEmail(email); err == nil {
+		return model.User{}, exception.NewConflictBusinessException("Service account already exists")
+	}
+
+	// Without a password the account can never sign in
+	account, err := s.userRepo.Create(model.User{
+		FirstName:   name,
+		Email:       email,
+		AccountType: model.AccountTypeService,
+	})
+	if err != nil {
+		return model.User{}, exception.NewInternal("Failed to save service account")
+	}
+
+	s.auditService.Record(c.Request.Context(), AuditServiceAccountCreated, &actor.ID, AuditTargetUser, strconv.FormatUint(uint64(account.ID), 10), map[string]any{
+		"name": name,
+	})
+
+	return account, nil
+}
+
+func (s *serviceAccountService) Delete(c *gin.Context, actorEmail string, id uint) error {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	account, err := s.findServiceAccount(id)
+	if err != nil {
+		return err
+	}
+
+	// Its keys and role grants go with it
+	deleted, err := s.userRepo.Delete(account.ID)
+	if err != nil {
+		return exception.NewInternal("Failed to delete service account")
+	}
+	if !deleted {
+		return exception.NewNotFound("Service account not found")
+	}
+
+	s.auditService.Record(c.Request.Context(), AuditServiceAccountDeleted, &actor.ID, AuditTargetUser, strconv.FormatUint(uint64(account.ID), 10), map[string]any{
+		"name": account.FirstName,
+	})
+
+	return nil
+}
+
+func (s *serviceAccountService) ListKeys(c *gin.Context, id uint) ([]model.ServiceAccountKey, error) {
+	account, err := s.findServiceAccount(id)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.serviceAccountKeyRepo.FindByUserID(account.ID)
+	if err != nil {
+		return nil, exception.ErrInternal
+	}
+	return keys, nil
+}
+
+func (s *serviceAccountService) CreateKey(c *gin.Context, actorEmail string, id uint, req requestDTO.CreateServiceAccountKeyRequest) (model.ServiceAccountKey, string, error) {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return model.ServiceAccountKey{}, "", exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	masterSecret, err := serviceAccountMasterSecret()
+	if err != nil {
+		return model.ServiceAccountKey{}, "", err
+	}
+
+	account, err := s.findServiceAccount(id)
+	if err != nil {
+		return model.ServiceAccountKey{}, "", err
+	}
+
+	random, err := randomHex(12)
+	if err != nil {
+		return model.ServiceAccountKey{}, "", exception.ErrInternal
+	}
+
+	key, err := s.serviceAccountKeyRepo.Create(model.ServiceAccountKey{
+		UserID:      account.ID,
+		KeyID:       serviceAccountKeyPrefix + random,
+		Description: strings.TrimSpace(req.Description),
+		CreatedBy:   &actor.ID,
+	})
+	if err != nil {
+		return model.ServiceAccountKey{}, "", exception.NewInternal("Failed to save API key")
+	}
+
+	s.auditService.Record(c.Request.Context(), AuditServiceKeyCreated, &actor.ID, AuditTargetServiceKey, strconv.FormatUint(uint64(key.ServiceAccountKeyID), 10), map[string]any{
+		"service_account_id": account.ID,
+		"key_id":             key.KeyID,
+	})
+
+	return key, deriveKeySecret(masterSecret, key.KeyID), nil
+}
+
+func (s *serviceAccountService) RevokeKey(c *gin.Context, actorEmail string, id uint, keyID uint) error {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	revoked, err := s.serviceAccountKeyRepo.Revoke(keyID, id, time.Now())
+	if err != nil {
+		return exception.NewInternal("Failed to revoke API key")
+	}
+	if !revoked {
+		return exception.NewNotFound("API key not found")
+	}
+
+	s.auditService.Record(c.Request.Context(), AuditServiceKeyRevoked, &actor.ID, AuditTargetServiceKey, strconv.FormatUint(uint64(keyID), 10), map[string]any{
+		"service_account_id": id,
+	})
+
+	return nil
+}
+
+// Authenticate checks the signature over the request and its target, that the timestamp is within the signature
+// window and that the signature was not used before against any instance. The record built is that of a personal access token: the account's current roles, no session.
+func (s *serviceAccountService) Authenticate(c *gin.Context, req SignedRequest) (string, error) {
+	invalid := exception.NewUnauthorizedBusinessException("Invalid request signature")
+
+	masterSecret, err := serviceAccountMasterSecret()
+	if err != nil {
+		return "", invalid
+	}
+
+	keyID, signature, err := apikey.ParseAuthorization(req.Authorization)
+	if err != nil {
+		return "", exception.NewUnauthorizedBusinessException("Invalid authorization header format")
+	}
+	if req.Method == "" || req.Service == "" || req.Endpoint == "" || req.RequestURI == "" || req.Timestamp == "" || req.BodySHA256 == "" {
+		return "", exception.NewBadRequest("Signed requests need the request URI, timestamp and body hash")
+	}
+
+	cfg := config.LoadConfig()
+	now := time.Now()
+	seconds, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return "", invalid
+	}
+	signedAt := time.Unix(seconds, 0)
+	if signedAt.Before(now.Add(-cfg.RequestSignatureWindow)) || signedAt.After(now.Add(cfg.RequestSignatureWindow)) {
+		return "", exception.New(http.StatusUnauthorized, "SIGNATURE_EXPIRED", "Request timestamp is outside the allowed window")
+	}
+
+	key, err := s.serviceAccountKeyRepo.FindByKeyID(keyID)
+	if err != nil || key.RevokedAt != nil || !key.User.IsServiceAccount() {
+		return "", invalid
+	}
+
+	expected := apikey.Sign([]byte(deriveKeySecret(masterSecret, key.KeyID)), apikey.StringToSign(req.Method, req.Service, req.Endpoint, req.RequestURI, req.Timestamp, req.BodySHA256))
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return "", invalid
+	}
+
+	// A signature stays unique for as long as its timestamp is accepted
+	ttl := max(signedAt.Add(cfg.RequestSignatureWindow).Sub(now), time.Second)
+	fresh, err := s.nonces.Use(c.Request.Context(), key.KeyID+":"+expected, ttl)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "failed to record request signature",
+			"keyId", key.KeyID,
+			"error", err,
+		)
+		return "", exception.ErrInternal
+	}
+	if !fresh {
+		return "", exception.New(http.StatusUnauthorized, "SIGNATURE_REPLAYED", "Request signature was already used")
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= cfg.SessionTouchInterval {
+		if err := s.serviceAccountKeyRepo.Touch(key.ServiceAccountKeyID, now); err != nil {
+			slog.WarnContext(c.Request.Context(), "failed to record API key use",
+				"keyId", key.KeyID,
+				"error", err,
+			)
+		}
+	}
+
+	return s.principalRecord(key.User, signedAt, now, signedAt.Add(cfg.RequestSignatureWindow))
+}
+
+func (s *serviceAccountService) ListCertificates(c *gin.Context, id uint) ([]model.ServiceAccountCertificate, error) {
+	account, err := s.findServiceAccount(id)
+	if err != nil {
+		return nil, err
+	}
+
+	certificates, err := s.serviceAccountCertificateRepo.FindByUserID(account.ID)
+	if err != nil {
+		return nil, exception.ErrInternal
+	}
+	return certificates, nil
+}
+
+func (s *serviceAccountService) AddCertificate(c *gin.Context, actorEmail string, id uint, req requestDTO.AddServiceAccountCertificateRequest) (model.ServiceAccountCertificate, error) {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return model.ServiceAccountCertificate{}, exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	account, err := s.findServiceAccount(id)
+	if err != nil {
+		return model.ServiceAccountCertificate{}, err
+	}
+
+	value := strings.TrimSpace(req.Value)
+	switch req.MatchType {
+	case model.CertificateMatchFingerprint:
+		if value, err = clientcert.NormalizeFingerprint(value); err != nil {
+			return model.ServiceAccountCertificate{}, exception.NewBadRequest("Fingerprint must be a hex-encoded SHA-256")
+		}
+	case model.CertificateMatchURI:
+		if uri, err := url.Parse(value); err != nil || uri.Scheme == "" {
+			return model.ServiceAccountCertificate{}, exception.NewBadRequest("Invalid URI")
+		}
+	}
+
+	if _, err := s.serviceAccountCertificateRepo.FindByMatch(req.MatchType, value); err == nil {
+		return model.ServiceAccountCertificate{}, exception.NewConflictBusinessException("This certificate is already mapped to a service account")
+	}
+
+	certificate, err := s.serviceAccountCertificateRepo.Create(model.ServiceAccountCertificate{
+		UserID:     account.ID,
+		MatchType:  req.MatchType,
+		MatchValue: value,
+		CreatedBy:  &actor.ID,
+	})
+	if err != nil {
+		return model.ServiceAccountCertificate{}, exception.NewInternal("Failed to save certificate mapping")
+	}
+
+	s.auditService.Record(c.Request.Context(), AuditServiceCertAdded, &actor.ID, AuditTargetServiceCert, strconv.FormatUint(uint64(certificate.ServiceAccountCertificateID), 10), map[string]any{
+		"service_account_id": account.ID,
+		"match_type":         certificate.MatchType,
+		"value":              certificate.MatchValue,
+	})
+
+	return certificate, nil
+}
+
+func (s *serviceAccountService) RemoveCertificate(c *gin.Context, actorEmail string, id uint, certificateID uint) error {
+	actor, err := s.userRepo.FindByEmail(strings.TrimSpace(actorEmail))
+	if err != nil {
+		return exception.NewUnauthorizedBusinessException("Actor not found")
+	}
+
+	deleted, err := s.serviceAccountCertificateRepo.Delete(certificateID, id)
+	if err != nil {
+		return exception.NewInternal("Failed to remove certificate mapping")
+	}
+	if !deleted {
+		return exception.NewNotFound("Certificate mapping not found")
+	}
+
+	s.auditService.Record(c.Request.Context(), AuditServiceCertRemoved, &actor.ID, AuditTargetServiceCert, strconv.FormatUint(uint64(certificateID), 10), map[string]any{
+		"service_account_id": id,
+	})
+
+	return nil
+}
+
+// AuthenticateCertificate resolves the certificate, verified by the gateway, through its fingerprint, URI SANs and
+// subject. A certificate matching the mappings of several service accounts is rejected.
+func (s *serviceAccountService) AuthenticateCertificate(c *gin.Context, cert clientcert.Certificate) (string, error) {
+	unmapped := exception.NewUnauthorizedBusinessException("Client certificate is not mapped to a service account")
+
+	certificates, err := s.serviceAccountCertificateRepo.FindMatching(cert.Fingerprint, cert.Subject, cert.URIs)
+	if err != nil {
+		return "", exception.ErrInternal
+	}
+
+	var account model.User
+	for _, certificate := range certificates {
+		if !certificate.User.IsServiceAccount() {
+			continue
+		}
+		if account.ID != 0 && account.ID != certificate.UserID {
+			slog.WarnContext(c.Request.Context(), "client certificate matches several service accounts",
+				"fingerprint", cert.Fingerprint,
+				"subject", cert.Subject,
+			)
+			return "", unmapped
+		}
+		account = certificate.User
+	}
+	if account.ID == 0 {
+		return "", unmapped
+	}
+
+	now := time.Now()
+	return s.principalRecord(account, now, now, now)
+}
+
+// principalRecord builds the session record of an authenticated service account, with its current roles and no session
+func (s *serviceAccountService) principalRecord(account model.User, createdAt time.Time, now time.Time, expiresAt time.Time) (string, error) {
+	roles, err := s.roleRepo.FindActiveByUserID(account.ID, now)
+	if err != nil {
+		return "", exception.ErrInternal
+	}
+	var roleNames []string
+	for _, r := range roles {
+		roleNames = append(roleNames, r.Name)
+	}
+
+	data, err := json.Marshal(session.Record{
+		User: responseDto.UserResponse{
+			FirstName: account.FirstName,
+			LastName:  account.LastName,
+			Email:     account.Email,
+			Roles:     strings.Join(roleNames, "|"),
+		},
+		Session: model.Session{
+			UserID:     account.ID,
+			CreatedAt:  createdAt,
+			LastSeenAt: now,
+			ExpiresAt:  expiresAt,
+		},
+	})
+	if err != nil {
+		return "", exception.ErrInternal
+	}
+	return string(data), nil
+}
+
+func (s *serviceAccountService) findServiceAccount(id uint) (model.User, error) {
+	account, err := s.userRepo.FindByID(id)
+	if err != nil || !account.IsServiceAccount() {
+		return model.User{}, exception.NewNotFound("Service account not found")
+	}
+	return account, nil
+}
+
+// serviceAccountMasterSecret decodes the secret key secrets are derived from
+func serviceAccountMasterSecret() ([]byte, error) {
+	secret, err := hex.DecodeString(config.LoadConfig().ServiceAccountKeySecret)
+	if err != nil || len(secret) < 32 {
+		return nil, exception.New(http.StatusServiceUnavailable, "API_KEYS_DISABLED", "API keys are not configured")
+	}
+	return secret, nil
+}
+
+// deriveKeySecret derives a key's secret from the master secret, so that only key IDs need to be stored.
+// Changing the master secret invalidates every key.
+func deriveKeySecret(masterSecret []byte, keyID string) string {
+	return apikey.Sign(masterSecret, keyID)
+}
+
+func validServiceAccountName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"auth-service/internal/clientcert"
+	"auth-service/internal/config"
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/response"
+	"auth-service/internal/session"
+	"auth-service/internal/token"
+	"auth-service/pkg/utils/exception"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newSessionID() (string, error) {
+	return randomHex(16)
+}
+
+// newTokenID returns a unique "jti" for a token
+func newTokenID() (string, error) {
+	return randomHex(16)
+}
+
+// opaqueTokenPrefix marks opaque tokens; JWTs always start with "eyJ", so both formats can be told apart
+const opaqueTokenPrefix = "at_"
+
+// newOpaqueToken returns a reference token: 256 random bits behind the opaque prefix
+func newOpaqueToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return opaqueTokenPrefix + base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func isOpaqueToken(token string) bool {
+	return strings.HasPrefix(token, opaqueTokenPrefix)
+}
+
+func randomHex(n int) (string, error) {
+	raw := make([]byte, n)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+// recordFromClaims rebuilds what a stored session would hold from a token's own claims
+func recordFromClaims(claims token.Claims, userID uint) session.Record {
+	str := func(name string) string {
+		value, _ := claims[name].(string)
+		return value
+	}
+	unix := func(name string) time.Time {
+		value, _ := claims[name].(float64)
+		return time.Unix(int64(value), 0)
+	}
+
+	record := session.Record{
+		User: responseDto.UserResponse{
+			FirstName: str("first_name"),
+			LastName:  str("last_name"),
+			Email:     str("email"),
+			Roles:     claimRoles(claims["roles"]),
+		},
+		Session: model.Session{
+			ID:         str("sid"),
+			UserID:     userID,
+			CreatedAt:  unix("iat"),
+			LastSeenAt: unix("iat"),
+			ExpiresAt:  unix("exp"),
+		},
+	}
+
+	if attributes, ok := claims["attributes"].(map[string]any); ok {
+		record.Attributes = attributes
+	}
+
+	if scope, ok := claims["scope"].(string); ok {
+		record.User.Scope = strings.Fields(scope)
+	}
+	if aud, ok := claims["aud"].([]any); ok {
+		for _, item := range aud {
+			if name, ok := item.(string); ok {
+				record.Audience = append(record.Audience, name)
+			}
+		}
+	}
+
+	if cnf, ok := claims["cnf"].(map[string]any); ok {
+		if thumbprint, ok := cnf["x5t#S256"].(string); ok {
+			record.Confirmation = &session.Confirmation{X5tS256: thumbprint}
+		}
+	}
+
+	record.User.Actor = claimActor(claims["act"])
+	return record
+}
+
+// certificateConfirmation binds new tokens to the request's client certificate, when certificate-bound tokens are enabled
+func certificateConfirmation(c *gin.Context, cfg config.Config) *session.Confirmation {
+	if !cfg.CertificateBoundTokens {
+		return nil
+	}
+	cert, ok := clientcert.FromContext(c)
+	if !ok {
+		return nil
+	}
+	return &session.Confirmation{X5tS256: cert.Thumbprint()}
+}
+
+// checkCertificateBinding rejects certificate-bound tokens presented without the certificate they were issued to
+func checkCertificateBinding(c *gin.Context, record session.Record) error {
+	if record.Confirmation == nil {
+		return nil
+	}
+	cert, ok := clientcert.FromContext(c)
+	if !ok || subtle.ConstantTimeCompare([]byte(cert.Thumbprint()), []byte(record.Confirmation.X5tS256)) != 1 {
+		return exception.New(http.StatusUnauthorized, "CERTIFICATE_MISMATCH", "Token is bound to a different client certificate")
+	}
+	return nil
+}
+
+// claimActor parses an "act" claim along with the actors it chains
+func claimActor(act any) *responseDto.ActorResponse {
+	claim, ok := act.(map[string]any)
+	if !ok {
+		return nil
+	}
+	actorID, _ := claim["id"].(float64)
+	actorEmail, _ := claim["email"].(string)
+	subject, _ := claim["sub"].(string)
+	return &responseDto.ActorResponse{
+		ID:      uint(actorID),
+		Email:   actorEmail,
+		Subject: subject,
+		Actor:   claimActor(claim["act"]),
+	}
+}
+
+// claimRoles joins the "roles" claim the way the v1 user response expects it. Tokens carry a list,
+// tokens issued before that a "|"-joined string.
+func claimRoles(roles any) string {
+	switch value := roles.(type) {
+	case string:
+		return value
+	case []any:
+		names := make([]string, 0, len(value))
+		for _, item := range value {
+			if name, ok := item.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, "|")
+	default:
+		return ""
+	}
+}
+
+// loadSession returns the JSON form of a token's record, as Verify hands it out, along with the record.
+// Sessions still stored under the raw token are migrated on the way.
+func (s *authService) loadSession(ctx context.Context, authToken string) (string, session.Record, error) {
+	record, err := s.sessions.Get(ctx, session.TokenKey(authToken))
+	// Only JWTs were ever stored under the raw token, so other misses are not looked up again
+	if err == session.ErrNotFound && token.IsJWT(authToken) {
+		if migrator, ok := s.sessions.(session.LegacyMigrator); ok {
+			record, err = migrator.MigrateToken(ctx, authToken)
+		}
+	}
+	if err != nil {
+		return "", session.Record{}, err
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", session.Record{}, err
+	}
+	return string(data), record, nil
+}
+
+// touchSession records activity on a session and slides its expiry to ttl (unchanged when ttl is not positive)
+func (s *authService) touchSession(ctx context.Context, token string, record session.Record, at time.Time, ttl time.Duration) (string, error) {
+	record.Session.LastSeenAt = at
+	if err := s.sessions.Touch(ctx, session.TokenKey(token), record, ttl); err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
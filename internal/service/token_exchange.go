@@ -0,0 +1,131 @@
+package service
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/request"
+	"auth-service/internal/model/dto/response"
+	"auth-service/internal/session"
+	"auth-service/pkg/utils/exception"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExchangeToken implements the token exchange grant (RFC 8693): the client trades a user's token, issued for the
+// client itself, for a short-lived one issued for a single audience. The new token only carries the requested
+// permissions the user holds and the subject token was scoped to, and names the client in its "act" claim,
+// chained to the subject token's own actor.
+func (s *authService) ExchangeToken(c *gin.Context, client model.OAuthClient, req requestDTO.TokenRequest) (responseDto.TokenResponse, error) {
+	cfg := config.LoadConfig()
+
+	if client.Public {
+		return responseDto.TokenResponse{}, oauthError(OAuthUnauthorizedClient, "Public clients cannot exchange tokens")
+	}
+	if req.SubjectToken == "" || req.SubjectTokenType != TokenTypeAccessToken {
+		return responseDto.TokenResponse{}, oauthError(OAuthInvalidRequest, "subject_token must be an access token")
+	}
+	if req.RequestedTokenType != "" && req.RequestedTokenType != TokenTypeAccessToken {
+		return responseDto.TokenResponse{}, oauthError(OAuthInvalidRequest, "Only access tokens can be requested")
+	}
+	if req.Audience == "" {
+		return responseDto.TokenResponse{}, oauthError(OAuthInvalidRequest, "audience is required")
+	}
+	if !client.AllowsAudience(req.Audience) {
+		return responseDto.TokenResponse{}, oauthError(OAuthInvalidTarget, "Client may not exchange tokens for this audience")
+	}
+	requested := strings.Fields(req.Scope)
+	if len(requested) == 0 {
+		return responseDto.TokenResponse{}, oauthError(OAuthInvalidScope, "scope is required")
+	}
+
+	/*
+		The subject token must be valid and presented to the client itself
+	*/
+	data, err := s.Verify(c, req.SubjectToken, client.Name)
+	if err != nil {
+		return responseDto.TokenResponse{}, oauthError(OAuthInvalidGrant, "Subject token is invalid: "+err.Error())
+	}
+	var record session.Record
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		return responseDto.TokenResponse{}, exception.ErrInternal
+	}
+	subject, err := s.VerifiedUser(c, data, true)
+	if err != nil {
+		return responseDto.TokenResponse{}, oauthError(OAuthInvalidGrant, "Subject token is invalid: "+err.Error())
+	}
+	user, err := s.userRepo.FindByID(subject.ID)
+	if err != nil {
+		return responseDto.TokenResponse{}, oauthError(OAuthInvalidGrant, "User not found")
+	}
+
+	/*
+		Grant what was requested, the user holds and the subject token was scoped to
+	*/
+	granted := grantedScope(requested, subject.Permissions, subject.Scope)
+	if len(granted) == 0 {
+		return responseDto.TokenResponse{}, oauthError(OAuthInvalidScope, "None of the requested scope can be granted")
+	}
+
+	ttl := cfg.TokenExchangeTTL
+	if expiresAt := record.Session.ExpiresAt; !expiresAt.IsZero() {
+		if remaining := time.Until(expiresAt); remaining < ttl {
+			ttl = remaining
+		}
+	}
+
+	actor := &responseDto.ActorResponse{
+		Subject: client.Name,
+		Actor:   subject.Actor,
+	}
+
+	signed, err := s.issueToken(c, user, subject.Roles, tokenOptions{
+		ttl:         ttl,
+		actor:       actor,
+		audience:    []string{req.Audience},
+		scope:       granted,
+		exchangedBy: client.Name,
+	})
+	if err != nil {
+		return responseDto.TokenResponse{}, err
+	}
+
+	s.auditService.Record(c.Request.Context(), AuditTokenExchanged, nil, AuditTargetUser, strconv.FormatUint(uint64(user.ID), 10), map[string]any{
+		"client":     client.Name,
+		"audience":   req.Audience,
+		"scope":      granted,
+		"expires_in": ttl.String(),
+	})
+
+	return responseDto.TokenResponse{
+		AccessToken:     signed,
+		IssuedTokenType: TokenTypeAccessToken,
+		TokenType:       "Bearer",
+		ExpiresIn:       int64(tokenLifetime(cfg, ttl).Seconds()),
+		Scope:           strings.Join(granted, " "),
+	}, nil
+}
+
+// grantedScope keeps the requested permissions covered by the user's permissions and, when the subject
+// token is scoped, by its scope. "ALL" covers every permission.
+func grantedScope(requested []string, permissions []string, scope []string) []string {
+	covers := func(list []string, permission string) bool {
+		return slices.Contains(list, "ALL") || slices.Contains(list, permission)
+	}
+
+	granted := []string{}
+	for _, permission := range requested {
+		if slices.Contains(granted, permission) || !covers(permissions, permission) {
+			continue
+		}
+		if scope != nil && !covers(scope, permission) {
+			continue
+		}
+		granted = append(granted, permission)
+	}
+	return granted
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/request"
+	"auth-service/internal/session"
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+// newTestExchange returns an auth service for ada@example.com, a CLERK allowed to READ_ORDERS and WRITE_ORDERS,
+// along with a token of hers issued by signing in and the billing client allowed to exchange for two services
+func newTestExchange(t *testing.T) (AuthService, string, model.OAuthClient) {
+	t.Helper()
+
+	users := &fakeUserRepository{users: newTestUsers(t, "ada@example.com")}
+	roles := &fakeRoleRepository{roles: map[uint][]model.Role{
+		1: {{RoleID: 1, Name: "CLERK", Permissions: []model.Permission{{Name: "READ_ORDERS"}, {Name: "WRITE_ORDERS"}}}},
+	}}
+	s := newTestAuthService(users, roles, &fakeAuditService{})
+	client := model.OAuthClient{ClientID: "billing", Name: "billing-service", AllowedAudiences: "orders-service,ledger-service"}
+	return s, loginAs(t, s, "ada@example.com", "laptop"), client
+}
+
+func exchange(s AuthService, subjectToken string, client model.OAuthClient, audience string, scope string) (string, error) {
+	response, err := s.ExchangeToken(newTestContext(), client, requestDTO.TokenRequest{
+		GrantType:        "urn:ietf:params:oauth:grant-type:token-exchange",
+		SubjectToken:     subjectToken,
+		SubjectTokenType: TokenTypeAccessToken,
+		Audience:         audience,
+		Scope:            scope,
+	})
+	return response.AccessToken, err
+}
+
+func TestAuthServiceExchangeToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		audience  string
+		scope     string
+		wantCode  string
+		wantScope []string
+	}{
+		{"audience the client may not exchange for", "payroll-service", "READ_ORDERS", OAuthInvalidTarget, nil},
+		{"nothing of the scope held", "orders-service", "DELETE_USERS", OAuthInvalidScope, nil},
+		{"scope clamped to the permissions held", "orders-service", "READ_ORDERS DELETE_USERS", "", []string{"READ_ORDERS"}},
+		{"scope clamped to the requested permissions", "ledger-service", "WRITE_ORDERS", "", []string{"WRITE_ORDERS"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, subjectToken, client := newTestExchange(t)
+
+			exchanged, err := exchange(s, subjectToken, client, tt.audience, tt.scope)
+			if code := errorCode(err); code != tt.wantCode {
+				t.Fatalf("ExchangeToken() code = %q, want %q", code, tt.wantCode)
+			}
+			if tt.wantCode != "" {
+				return
+			}
+
+			data, err := s.Verify(newTestContext(), exchanged, tt.audience)
+			if err != nil {
+				t.Fatalf("Verify() for the requested audience error = %v", err)
+			}
+			var record session.Record
+			if err := json.Unmarshal([]byte(data), &record); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(record.User.Scope, tt.wantScope) {
+				t.Errorf("exchanged scope = %v, want %v", record.User.Scope, tt.wantScope)
+			}
+			if record.User.Actor == nil || record.User.Actor.Subject != client.Name {
+				t.Errorf("exchanged actor = %+v, want %s", record.User.Actor, client.Name)
+			}
+
+			for _, audience := range client.Audiences() {
+				if audience == tt.audience {
+					continue
+				}
+				if _, err := s.Verify(newTestContext(), exchanged, audience); errorCode(err) != "TOKEN_AUDIENCE_INVALID" {
+					t.Errorf("Verify() for %s error = %v, want TOKEN_AUDIENCE_INVALID", audience, err)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthServiceExchangedSessions(t *testing.T) {
+	t.Setenv("SESSION_MAX_CONCURRENT", "2")
+	t.Setenv("SESSION_LIMIT_POLICY", config.SessionLimitReject)
+
+	s, subjectToken, client := newTestExchange(t)
+	for _, audience := range client.Audiences() {
+		if _, err := exchange(s, subjectToken, client, audience, "READ_ORDERS"); err != nil {
+			t.Fatalf("ExchangeToken() error = %v", err)
+		}
+	}
+
+	// Two exchanged tokens are live, yet a second sign-in still fits under the limit of two
+	loginAs(t, s, "ada@example.com", "phone")
+
+	sessions, err := s.ListSessions(newTestContext(), "ada@example.com")
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+	var userAgents []string
+	for _, listed := range sessions {
+		if listed.Exchanged() {
+			t.Errorf("ListSessions() listed the session exchanged by %s", listed.ExchangedBy)
+		}
+		userAgents = append(userAgents, listed.UserAgent)
+	}
+	slices.Sort(userAgents)
+	if want := []string{"laptop", "phone"}; !slices.Equal(userAgents, want) {
+		t.Errorf("ListSessions() user agents = %v, want %v", userAgents, want)
+	}
+
+	if _, err := s.Login(newTestContext(), "ada@example.com", "secret"); errorCode(err) != "SESSION_LIMIT_EXCEEDED" {
+		t.Errorf("Login() over the limit error = %v, want SESSION_LIMIT_EXCEEDED", err)
+	}
+}
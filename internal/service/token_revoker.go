@@ -0,0 +1,67 @@
+package service
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/repository"
+	"auth-service/internal/session"
+	"auth-service/pkg/utils/exception"
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// TokenRevoker ends every token of a user, e.g. after their roles changed
+type TokenRevoker interface {
+	RevokeAllTokens(ctx context.Context, userID uint, actorID *uint, reason string) error
+}
+
+type tokenRevoker struct {
+	userRepo     repository.UserRepository
+	sessions     session.Store
+	revocations  RevocationService
+	auditService AuditService
+}
+
+// NewTokenRevoker creates a revoker from just what revocation touches, for callers such as the breakglass CLI that
+// need no full AuthService
+func NewTokenRevoker(userRepo repository.UserRepository, sessions session.Store, revocations RevocationService, auditService AuditService) TokenRevoker {
+	return &tokenRevoker{userRepo, sessions, revocations, auditService}
+}
+
+// RevokeAllTokens bumps the user's token generation, which invalidates every token issued so far, and deletes their sessions
+func (r *tokenRevoker) RevokeAllTokens(ctx context.Context, userID uint, actorID *uint, reason string) error {
+	generation, err := r.userRepo.IncrementTokenGeneration(userID)
+	if err != nil {
+		return exception.NewInternal("Failed to revoke tokens")
+	}
+
+	if err := r.sessions.DeleteByUser(ctx, userID); err != nil {
+		// The bumped generation still rejects the remaining tokens in Verify
+		slog.ErrorContext(ctx, "failed to delete user sessions",
+			"userId", userID,
+			"error", err,
+		)
+	}
+
+	// Stateless verification never reads the generation, so cut off older tokens through the denylist,
+	// for as long as the longest-lived of them can still be valid
+	cfg := config.LoadConfig()
+	lifetime := cfg.SessionMaxLifetime
+	if cfg.AccessTokenTTL > lifetime {
+		lifetime = cfg.AccessTokenTTL
+	}
+	if err := r.revocations.RevokeUser(ctx, userID, generation, time.Now().Add(lifetime)); err != nil {
+		slog.ErrorContext(ctx, "failed to add user to token denylist",
+			"userId", userID,
+			"error", err,
+		)
+	}
+
+	r.auditService.Record(ctx, AuditTokensRevoked, actorID, AuditTargetUser, strconv.FormatUint(uint64(userID), 10), map[string]any{
+		"reason":     reason,
+		"generation": generation,
+	})
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	record    Record
+	expiresAt time.Time
+}
+
+type memoryStore struct {
+	mu       sync.Mutex
+	entries  map[string]memoryEntry
+	sessions map[string]string // session id -> key
+
+	// sweepInterval bounds how often expired entries are evicted
+	sweepInterval time.Duration
+	lastSweep     time.Time
+}
+
+// NewMemoryStore keeps sessions in process memory. Expired entries are invisible immediately and evicted
+// on the next write after sweepInterval. Sessions are lost on restart and not shared between instances.
+func NewMemoryStore(sweepInterval time.Duration) Store {
+	return &memoryStore{
+		entries:       map[string]memoryEntry{},
+		sessions:      map[string]string{},
+		sweepInterval: sweepInterval,
+	}
+}
+
+// get returns the live entry under key; the caller holds the lock
+func (s *memoryStore) get(key string, now time.Time) (memoryEntry, bool) {
+	entry, ok := s.entries[key]
+	if !ok || !now.Before(entry.expiresAt) {
+		return memoryEntry{}, false
+	}
+	return entry, true
+}
+
+// remove drops an entry and its index; the caller holds the lock
+func (s *memoryStore) remove(key string) {
+	entry, ok := s.entries[key]
+	if !ok {
+		return
+	}
+	delete(s.entries, key)
+	if id := entry.record.Session.ID; id != "" && s.sessions[id] == key {
+		delete(s.sessions, id)
+	}
+}
+
+// sweep evicts expired entries at most once per sweepInterval; the caller holds the lock
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, entry := range s.entries {
+		if !now.Before(entry.expiresAt) {
+			s.remove(key)
+		}
+	}
+}
+
+func (s *memoryStore) Save(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	s.remove(key)
+	s.entries[key] = memoryEntry{record: record, expiresAt: now.Add(ttl)}
+	if record.Session.ID != "" {
+		s.sessions[record.Session.ID] = key
+	}
+	return nil
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.get(key, time.Now())
+	if !ok {
+		return Record{}, ErrNotFound
+	}
+	return entry.record, nil
+}
+
+func (s *memoryStore) Touch(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	entry, ok := s.get(key, now)
+	if !ok {
+		return ErrNotFound
+	}
+
+	entry.record = record
+	if ttl > 0 {
+		entry.expiresAt = now.Add(ttl)
+	}
+	s.entries[key] = entry
+	return nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.get(key, time.Now())
+	s.remove(key)
+	return ok, nil
+}
+
+func (s *memoryStore) FindKey(ctx context.Context, sessionID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.sessions[sessionID]
+	if !ok {
+		return "", ErrNotFound
+	}
+	if _, ok := s.get(key, time.Now()); !ok {
+		return "", ErrNotFound
+	}
+	return key, nil
+}
+
+func (s *memoryStore) ListByUser(ctx context.Context, userID uint) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	entries := []Entry{}
+	for key, entry := range s.entries {
+		session := entry.record.Session
+		if session.ID == "" || session.UserID != userID || !now.Before(entry.expiresAt) {
+			continue
+		}
+		entries = append(entries, Entry{Key: key, Record: entry.record})
+	}
+	return entries, nil
+}
+
+func (s *memoryStore) DeleteByUser(ctx context.Context, userID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, entry := range s.entries {
+		if entry.record.Session.ID != "" && entry.record.Session.UserID == userID {
+			s.remove(key)
+		}
+	}
+	return nil
+}
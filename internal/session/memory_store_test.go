@@ -0,0 +1,15 @@
+package session_test
+
+import (
+	"auth-service/internal/session"
+	"auth-service/internal/session/sessiontest"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	if err := sessiontest.TestStore(context.Background(), session.NewMemoryStore(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+}
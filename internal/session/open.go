@@ -0,0 +1,49 @@
+package session
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/infra/db"
+	"auth-service/internal/infra/redis"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Open builds the store selected by SESSION_STORE. The redis backend connects to Redis;
+// the postgres backend reuses the db.DB connection, which must already be open.
+func Open(cfg config.Config) (Store, error) {
+	switch cfg.SessionStore {
+	case config.SessionStoreRedis:
+		if err := redis.InitRedis(RedisOptions(cfg)); err != nil {
+			return nil, err
+		}
+		return NewRedisStore(redis.Rdb, cfg.SessionKeyPrefix), nil
+	case config.SessionStorePostgres:
+		if db.DB == nil {
+			return nil, errors.New("postgres session store requires a database connection")
+		}
+		return NewPostgresStore(db.DB), nil
+	case config.SessionStoreMemory:
+		return NewMemoryStore(time.Minute), nil
+	default:
+		return nil, fmt.Errorf("unknown session store %q", cfg.SessionStore)
+	}
+}
+
+// RedisOptions maps the Redis settings of cfg to connection options
+func RedisOptions(cfg config.Config) redis.Options {
+	return redis.Options{
+		Addr:             cfg.RedisAddress,
+		Password:         cfg.RedisPassword,
+		DB:               cfg.RedisDB,
+		MasterName:       cfg.RedisSentinelMaster,
+		SentinelAddrs:    cfg.RedisSentinelAddrs,
+		SentinelPassword: cfg.RedisSentinelPassword,
+		ClusterAddrs:     cfg.RedisClusterAddrs,
+		TLS:              cfg.RedisTLS,
+		TLSServerName:    cfg.RedisTLSServerName,
+		TLSCAFile:        cfg.RedisTLSCAFile,
+		PoolSize:         cfg.RedisPoolSize,
+		MinIdleConns:     cfg.RedisMinIdleConns,
+	}
+}
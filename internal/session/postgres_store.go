@@ -0,0 +1,169 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sessionRow is a session in the sessions table; expired rows are ignored and swept on write
+type sessionRow struct {
+	TokenHash string    `gorm:"primaryKey;column:token_hash"`
+	SessionID *string   `gorm:"column:session_id"`
+	UserID    uint      `gorm:"column:user_id"`
+	Data      string    `gorm:"column:data;type:jsonb"`
+	ExpiresAt time.Time `gorm:"column:expires_at"`
+}
+
+func (sessionRow) TableName() string {
+	return "sessions"
+}
+
+type postgresStore struct {
+	db *gorm.DB
+
+	// sweepInterval bounds how often expired rows are deleted
+	sweepInterval time.Duration
+	mu            sync.Mutex
+	lastSweep     time.Time
+}
+
+// NewPostgresStore stores sessions in the sessions table
+func NewPostgresStore(db *gorm.DB) Store {
+	return &postgresStore{db: db, sweepInterval: time.Minute}
+}
+
+func (s *postgresStore) Save(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	row := sessionRow{
+		TokenHash: key,
+		UserID:    record.Session.UserID,
+		Data:      string(data),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if record.Session.ID != "" {
+		row.SessionID = &record.Session.ID
+	}
+
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token_hash"}},
+		DoUpdates: clause.AssignmentColumns([]string{"session_id", "user_id", "data", "expires_at"}),
+	}).Create(&row)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	s.sweep(ctx)
+	return nil
+}
+
+func (s *postgresStore) live(ctx context.Context) *gorm.DB {
+	return s.db.WithContext(ctx).Model(&sessionRow{}).Where("expires_at > ?", time.Now())
+}
+
+func (s *postgresStore) Get(ctx context.Context, key string) (Record, error) {
+	var row sessionRow
+	result := s.live(ctx).Where("token_hash = ?", key).Limit(1).Find(&row)
+	if result.Error != nil {
+		return Record{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return Record{}, ErrNotFound
+	}
+	return decodeRow(row)
+}
+
+func (s *postgresStore) Touch(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	updates := map[string]any{"data": string(data)}
+	if ttl > 0 {
+		updates["expires_at"] = time.Now().Add(ttl)
+	}
+
+	result := s.live(ctx).Where("token_hash = ?", key).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *postgresStore) Delete(ctx context.Context, key string) (bool, error) {
+	result := s.live(ctx).Where("token_hash = ?", key).Delete(&sessionRow{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (s *postgresStore) FindKey(ctx context.Context, sessionID string) (string, error) {
+	var row sessionRow
+	result := s.live(ctx).Where("session_id = ?", sessionID).Limit(1).Find(&row)
+	if result.Error != nil {
+		return "", result.Error
+	}
+	if result.RowsAffected == 0 {
+		return "", ErrNotFound
+	}
+	return row.TokenHash, nil
+}
+
+func (s *postgresStore) ListByUser(ctx context.Context, userID uint) ([]Entry, error) {
+	var rows []sessionRow
+	result := s.live(ctx).Where("user_id = ? AND session_id IS NOT NULL", userID).Find(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	entries := []Entry{}
+	for _, row := range rows {
+		record, err := decodeRow(row)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, Entry{Key: row.TokenHash, Record: record})
+	}
+	return entries, nil
+}
+
+func (s *postgresStore) DeleteByUser(ctx context.Context, userID uint) error {
+	return s.db.WithContext(ctx).Where("user_id = ? AND session_id IS NOT NULL", userID).Delete(&sessionRow{}).Error
+}
+
+// sweep deletes expired rows at most once per sweepInterval
+func (s *postgresStore) sweep(ctx context.Context) {
+	s.mu.Lock()
+	now := time.Now()
+	if now.Sub(s.lastSweep) < s.sweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	if err := s.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&sessionRow{}).Error; err != nil {
+		slog.ErrorContext(ctx, "failed to sweep expired sessions",
+			"error", err,
+		)
+	}
+}
+
+func decodeRow(row sessionRow) (Record, error) {
+	var record Record
+	if err := json.Unmarshal([]byte(row.Data), &record); err != nil {
+		return Record{}, err
+	}
+	return record, nil
+}
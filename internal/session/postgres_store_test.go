@@ -0,0 +1,29 @@
+package session_test
+
+import (
+	"auth-service/internal/session"
+	"auth-service/internal/session/sessiontest"
+	"context"
+	"os"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// TestPostgresStore runs against the migrated database at SESSION_TEST_DATABASE_URL and is skipped without it
+func TestPostgresStore(t *testing.T) {
+	dsn := os.Getenv("SESSION_TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("SESSION_TEST_DATABASE_URL is not set")
+	}
+
+	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sessiontest.TestStore(context.Background(), session.NewPostgresStore(database)); err != nil {
+		t.Fatal(err)
+	}
+}
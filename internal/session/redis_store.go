@@ -0,0 +1,292 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+/*
+	Redis layout, under the configured prefix (SESSION_KEY_PREFIX, default "auth:sess:"):
+	  <prefix><key>               -> Record JSON, expires with the session
+	  <prefix>id:<session id>     -> <prefix><key>, expires with the session
+	  <prefix>user:<user id>      -> set of session ids, pruned lazily when listed
+
+	Sessions written under the legacy layout (<token>, session:<session id>,
+	user_sessions:<user id>) are moved to the new layout the first time they are read,
+	keeping their remaining TTL.
+*/
+
+type redisStore struct {
+	client goredis.UniversalClient
+	prefix string
+}
+
+// NewRedisStore stores sessions in Redis under prefix. Only single-key commands and pipelines are used,
+// so the store also works against Redis Cluster.
+func NewRedisStore(client goredis.UniversalClient, prefix string) Store {
+	return &redisStore{client, prefix}
+}
+
+func (s *redisStore) recordKey(key string) string {
+	return s.prefix + key
+}
+
+func (s *redisStore) sessionKey(sessionID string) string {
+	return s.prefix + "id:" + sessionID
+}
+
+func (s *redisStore) userSessionsKey(userID uint) string {
+	return fmt.Sprintf("%suser:%d", s.prefix, userID)
+}
+
+func legacySessionKey(sessionID string) string {
+	return "session:" + sessionID
+}
+
+func legacyUserSessionsKey(userID uint) string {
+	return fmt.Sprintf("user_sessions:%d", userID)
+}
+
+func (s *redisStore) Save(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.store(ctx, s.recordKey(key), data, record, ttl)
+}
+
+func (s *redisStore) store(ctx context.Context, recordKey string, data []byte, record Record, ttl time.Duration) error {
+	_, err := s.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Set(ctx, recordKey, data, ttl)
+		if record.Session.ID != "" {
+			pipe.Set(ctx, s.sessionKey(record.Session.ID), recordKey, ttl)
+			pipe.SAdd(ctx, s.userSessionsKey(record.Session.UserID), record.Session.ID)
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *redisStore) Get(ctx context.Context, key string) (Record, error) {
+	return s.load(ctx, s.recordKey(key))
+}
+
+func (s *redisStore) load(ctx context.Context, recordKey string) (Record, error) {
+	data, err := s.client.Get(ctx, recordKey).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return Record{}, ErrNotFound
+	}
+	if err != nil {
+		return Record{}, err
+	}
+
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return Record{}, err
+	}
+	return record, nil
+}
+
+func (s *redisStore) Touch(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	args := goredis.SetArgs{Mode: "XX", KeepTTL: true}
+	if ttl > 0 {
+		args = goredis.SetArgs{Mode: "XX", TTL: ttl}
+	}
+
+	var set *goredis.StatusCmd
+	_, err = s.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		set = pipe.SetArgs(ctx, s.recordKey(key), data, args)
+		if ttl > 0 && record.Session.ID != "" {
+			pipe.Expire(ctx, s.sessionKey(record.Session.ID), ttl)
+		}
+		return nil
+	})
+	if errors.Is(set.Err(), goredis.Nil) {
+		return ErrNotFound
+	}
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		return err
+	}
+	return nil
+}
+
+func (s *redisStore) Delete(ctx context.Context, key string) (bool, error) {
+	return s.delete(ctx, s.recordKey(key))
+}
+
+func (s *redisStore) delete(ctx context.Context, recordKey string) (bool, error) {
+	// The record names the index entries to drop along with it
+	record, err := s.load(ctx, recordKey)
+	if err == ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var deleted *goredis.IntCmd
+	_, err = s.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		deleted = pipe.Del(ctx, recordKey)
+		if record.Session.ID != "" {
+			pipe.Del(ctx, s.sessionKey(record.Session.ID))
+			pipe.SRem(ctx, s.userSessionsKey(record.Session.UserID), record.Session.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return deleted.Val() > 0, nil
+}
+
+func (s *redisStore) FindKey(ctx context.Context, sessionID string) (string, error) {
+	recordKey, err := s.client.Get(ctx, s.sessionKey(sessionID)).Result()
+	if errors.Is(err, goredis.Nil) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(recordKey, s.prefix), nil
+}
+
+func (s *redisStore) ListByUser(ctx context.Context, userID uint) ([]Entry, error) {
+	if err := s.migrateUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	sessionIDs, err := s.client.SMembers(ctx, s.userSessionsKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []Entry{}
+	for _, sessionID := range sessionIDs {
+		key, err := s.FindKey(ctx, sessionID)
+		if err == ErrNotFound {
+			s.client.SRem(ctx, s.userSessionsKey(userID), sessionID)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		record, err := s.Get(ctx, key)
+		if err == ErrNotFound {
+			s.client.SRem(ctx, s.userSessionsKey(userID), sessionID)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, Entry{Key: key, Record: record})
+	}
+	return entries, nil
+}
+
+func (s *redisStore) DeleteByUser(ctx context.Context, userID uint) error {
+	if err := s.migrateUser(ctx, userID); err != nil {
+		return err
+	}
+
+	sessionIDs, err := s.client.SMembers(ctx, s.userSessionsKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, sessionID := range sessionIDs {
+		recordKey, err := s.client.Get(ctx, s.sessionKey(sessionID)).Result()
+		if err != nil && !errors.Is(err, goredis.Nil) {
+			return err
+		}
+		if recordKey != "" {
+			if err := s.client.Del(ctx, recordKey).Err(); err != nil {
+				return err
+			}
+		}
+		if err := s.client.Del(ctx, s.sessionKey(sessionID)).Err(); err != nil {
+			return err
+		}
+	}
+
+	return s.client.Del(ctx, s.userSessionsKey(userID)).Err()
+}
+
+// MigrateToken moves a session stored under its raw token to the hashed layout
+func (s *redisStore) MigrateToken(ctx context.Context, token string) (Record, error) {
+	data, err := s.client.Get(ctx, token).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return Record{}, ErrNotFound
+	}
+	if err != nil {
+		return Record{}, err
+	}
+
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return Record{}, err
+	}
+
+	ttl, err := s.client.PTTL(ctx, token).Result()
+	if err != nil {
+		return Record{}, err
+	}
+	if ttl <= 0 {
+		// Legacy keys always carried an expiry; a missing one means the key just expired
+		return Record{}, ErrNotFound
+	}
+
+	if err := s.store(ctx, s.recordKey(TokenKey(token)), data, record, ttl); err != nil {
+		return Record{}, err
+	}
+
+	_, err = s.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Del(ctx, token)
+		if record.Session.ID != "" {
+			pipe.Del(ctx, legacySessionKey(record.Session.ID))
+			pipe.SRem(ctx, legacyUserSessionsKey(record.Session.UserID), record.Session.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete legacy session keys",
+			"sessionId", record.Session.ID,
+			"error", err,
+		)
+	}
+	return record, nil
+}
+
+// migrateUser moves every session in the user's legacy index to the hashed layout
+func (s *redisStore) migrateUser(ctx context.Context, userID uint) error {
+	sessionIDs, err := s.client.SMembers(ctx, legacyUserSessionsKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, sessionID := range sessionIDs {
+		token, err := s.client.Get(ctx, legacySessionKey(sessionID)).Result()
+		if err != nil && !errors.Is(err, goredis.Nil) {
+			return err
+		}
+		if token != "" {
+			if _, err := s.MigrateToken(ctx, token); err != nil && err != ErrNotFound {
+				return err
+			}
+		}
+		s.client.SRem(ctx, legacyUserSessionsKey(userID), sessionID)
+	}
+	return nil
+}
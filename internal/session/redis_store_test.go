@@ -0,0 +1,31 @@
+package session_test
+
+import (
+	"auth-service/internal/session"
+	"auth-service/internal/session/sessiontest"
+	"context"
+	"os"
+	"testing"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// TestRedisStore runs against the Redis at SESSION_TEST_REDIS_ADDR (e.g. localhost:6379) and is skipped without it
+func TestRedisStore(t *testing.T) {
+	addr := os.Getenv("SESSION_TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("SESSION_TEST_REDIS_ADDR is not set")
+	}
+
+	client := goredis.NewClient(&goredis.Options{Addr: addr})
+	defer client.Close()
+
+	ctx := context.Background()
+	if err := client.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sessiontest.TestStore(ctx, session.NewRedisStore(client, "auth:sess:test:")); err != nil {
+		t.Fatal(err)
+	}
+}
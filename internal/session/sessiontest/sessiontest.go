@@ -0,0 +1,308 @@
+// Package sessiontest implements a conformance suite for session.Store backends, in the spirit of testing/fstest.
+// Every backend must pass it; run it with cmd/sessioncheck against a live backend, or with go test ./internal/session.
+package sessiontest
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/session"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// expiryTTL is the lifetime used to check expiry; backends may round expiries to the second
+const expiryTTL = time.Second
+
+// TestStore exercises store and returns every deviation from the session.Store contract, or nil.
+// It only touches keys and users it generates itself, so it can run against a shared backend.
+func TestStore(ctx context.Context, store session.Store) error {
+	c := &checker{ctx: ctx, store: store}
+
+	c.run("save and get", c.testSaveGet)
+	c.run("find key", c.testFindKey)
+	c.run("touch", c.testTouch)
+	c.run("delete", c.testDelete)
+	c.run("list by user", c.testListByUser)
+	c.run("delete by user", c.testDeleteByUser)
+	c.run("expiry", c.testExpiry)
+
+	return errors.Join(c.errs...)
+}
+
+type checker struct {
+	ctx   context.Context
+	store session.Store
+	errs  []error
+}
+
+func (c *checker) run(name string, test func() error) {
+	if err := test(); err != nil {
+		c.errs = append(c.errs, fmt.Errorf("%s: %w", name, err))
+	}
+}
+
+func randomHex(n int) string {
+	raw := make([]byte, n)
+	_, _ = rand.Read(raw)
+	return hex.EncodeToString(raw)
+}
+
+func randomUserID() uint {
+	raw := make([]byte, 4)
+	_, _ = rand.Read(raw)
+	return uint(binary.BigEndian.Uint32(raw)>>1) + 1
+}
+
+// newRecord builds a record of a fresh session of userID, stored under a fresh key
+func newRecord(userID uint) (string, session.Record) {
+	now := time.Now().UTC().Truncate(time.Second)
+	return randomHex(32), session.Record{
+		Session: model.Session{
+			ID:         randomHex(16),
+			UserID:     userID,
+			UserAgent:  "sessiontest",
+			IP:         "127.0.0.1",
+			CreatedAt:  now,
+			LastSeenAt: now,
+			ExpiresAt:  now.Add(time.Hour),
+		},
+	}
+}
+
+func (c *checker) testSaveGet() error {
+	key, record := newRecord(randomUserID())
+	record.User.Email = "sessiontest@example.com"
+	if err := c.store.Save(c.ctx, key, record, time.Minute); err != nil {
+		return fmt.Errorf("Save: %w", err)
+	}
+	defer c.store.Delete(c.ctx, key)
+
+	got, err := c.store.Get(c.ctx, key)
+	if err != nil {
+		return fmt.Errorf("Get: %w", err)
+	}
+	if got.Session.ID != record.Session.ID || got.User.Email != record.User.Email || !got.Session.CreatedAt.Equal(record.Session.CreatedAt) {
+		return fmt.Errorf("Get returned %+v, want %+v", got, record)
+	}
+
+	if _, err := c.store.Get(c.ctx, randomHex(32)); !errors.Is(err, session.ErrNotFound) {
+		return fmt.Errorf("Get of unknown key: got %v, want ErrNotFound", err)
+	}
+	return nil
+}
+
+func (c *checker) testFindKey() error {
+	key, record := newRecord(randomUserID())
+	if err := c.store.Save(c.ctx, key, record, time.Minute); err != nil {
+		return fmt.Errorf("Save: %w", err)
+	}
+	defer c.store.Delete(c.ctx, key)
+
+	got, err := c.store.FindKey(c.ctx, record.Session.ID)
+	if err != nil {
+		return fmt.Errorf("FindKey: %w", err)
+	}
+	if got != key {
+		return fmt.Errorf("FindKey returned %q, want %q", got, key)
+	}
+
+	if _, err := c.store.FindKey(c.ctx, randomHex(16)); !errors.Is(err, session.ErrNotFound) {
+		return fmt.Errorf("FindKey of unknown session: got %v, want ErrNotFound", err)
+	}
+	return nil
+}
+
+func (c *checker) testTouch() error {
+	key, record := newRecord(randomUserID())
+	if err := c.store.Save(c.ctx, key, record, expiryTTL); err != nil {
+		return fmt.Errorf("Save: %w", err)
+	}
+	defer c.store.Delete(c.ctx, key)
+
+	record.Session.LastSeenAt = record.Session.LastSeenAt.Add(time.Minute)
+	if err := c.store.Touch(c.ctx, key, record, time.Minute); err != nil {
+		return fmt.Errorf("Touch: %w", err)
+	}
+
+	got, err := c.store.Get(c.ctx, key)
+	if err != nil {
+		return fmt.Errorf("Get after Touch: %w", err)
+	}
+	if !got.Session.LastSeenAt.Equal(record.Session.LastSeenAt) {
+		return fmt.Errorf("Touch did not replace the record: last seen %v, want %v", got.Session.LastSeenAt, record.Session.LastSeenAt)
+	}
+
+	// The touched TTL outlives the original one
+	time.Sleep(2 * expiryTTL)
+	if _, err := c.store.Get(c.ctx, key); err != nil {
+		return fmt.Errorf("Touch did not extend the expiry: %w", err)
+	}
+	if _, err := c.store.FindKey(c.ctx, record.Session.ID); err != nil {
+		return fmt.Errorf("Touch did not extend the session index: %w", err)
+	}
+
+	// A non-positive TTL keeps the current expiry
+	if err := c.store.Touch(c.ctx, key, record, 0); err != nil {
+		return fmt.Errorf("Touch keeping the TTL: %w", err)
+	}
+	if _, err := c.store.Get(c.ctx, key); err != nil {
+		return fmt.Errorf("Get after Touch keeping the TTL: %w", err)
+	}
+
+	if err := c.store.Touch(c.ctx, randomHex(32), record, time.Minute); !errors.Is(err, session.ErrNotFound) {
+		return fmt.Errorf("Touch of unknown key: got %v, want ErrNotFound", err)
+	}
+	return nil
+}
+
+func (c *checker) testDelete() error {
+	key, record := newRecord(randomUserID())
+	if err := c.store.Save(c.ctx, key, record, time.Minute); err != nil {
+		return fmt.Errorf("Save: %w", err)
+	}
+
+	deleted, err := c.store.Delete(c.ctx, key)
+	if err != nil {
+		return fmt.Errorf("Delete: %w", err)
+	}
+	if !deleted {
+		return errors.New("Delete of a stored key reported false")
+	}
+
+	if _, err := c.store.Get(c.ctx, key); !errors.Is(err, session.ErrNotFound) {
+		return fmt.Errorf("Get after Delete: got %v, want ErrNotFound", err)
+	}
+	if _, err := c.store.FindKey(c.ctx, record.Session.ID); !errors.Is(err, session.ErrNotFound) {
+		return fmt.Errorf("FindKey after Delete: got %v, want ErrNotFound", err)
+	}
+	entries, err := c.store.ListByUser(c.ctx, record.Session.UserID)
+	if err != nil {
+		return fmt.Errorf("ListByUser after Delete: %w", err)
+	}
+	if len(entries) != 0 {
+		return fmt.Errorf("ListByUser after Delete returned %d sessions, want 0", len(entries))
+	}
+
+	deleted, err = c.store.Delete(c.ctx, key)
+	if err != nil {
+		return fmt.Errorf("second Delete: %w", err)
+	}
+	if deleted {
+		return errors.New("Delete of a missing key reported true")
+	}
+	return nil
+}
+
+func (c *checker) testListByUser() error {
+	userID := randomUserID()
+
+	want := map[string]string{}
+	for i := 0; i < 3; i++ {
+		key, record := newRecord(userID)
+		if err := c.store.Save(c.ctx, key, record, time.Minute); err != nil {
+			return fmt.Errorf("Save: %w", err)
+		}
+		defer c.store.Delete(c.ctx, key)
+		want[key] = record.Session.ID
+	}
+
+	// Neither another user's session nor a record without a session id belongs to the list
+	otherKey, other := newRecord(randomUserID())
+	if err := c.store.Save(c.ctx, otherKey, other, time.Minute); err != nil {
+		return fmt.Errorf("Save: %w", err)
+	}
+	defer c.store.Delete(c.ctx, otherKey)
+
+	untrackedKey, untracked := newRecord(userID)
+	untracked.Session = model.Session{}
+	if err := c.store.Save(c.ctx, untrackedKey, untracked, time.Minute); err != nil {
+		return fmt.Errorf("Save: %w", err)
+	}
+	defer c.store.Delete(c.ctx, untrackedKey)
+
+	entries, err := c.store.ListByUser(c.ctx, userID)
+	if err != nil {
+		return fmt.Errorf("ListByUser: %w", err)
+	}
+	if len(entries) != len(want) {
+		return fmt.Errorf("ListByUser returned %d sessions, want %d", len(entries), len(want))
+	}
+	for _, entry := range entries {
+		if want[entry.Key] != entry.Record.Session.ID {
+			return fmt.Errorf("ListByUser returned unexpected session %q under key %q", entry.Record.Session.ID, entry.Key)
+		}
+	}
+	return nil
+}
+
+func (c *checker) testDeleteByUser() error {
+	userID := randomUserID()
+
+	var keys []string
+	for i := 0; i < 2; i++ {
+		key, record := newRecord(userID)
+		if err := c.store.Save(c.ctx, key, record, time.Minute); err != nil {
+			return fmt.Errorf("Save: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	otherKey, other := newRecord(randomUserID())
+	if err := c.store.Save(c.ctx, otherKey, other, time.Minute); err != nil {
+		return fmt.Errorf("Save: %w", err)
+	}
+	defer c.store.Delete(c.ctx, otherKey)
+
+	if err := c.store.DeleteByUser(c.ctx, userID); err != nil {
+		return fmt.Errorf("DeleteByUser: %w", err)
+	}
+
+	for _, key := range keys {
+		if _, err := c.store.Get(c.ctx, key); !errors.Is(err, session.ErrNotFound) {
+			return fmt.Errorf("Get after DeleteByUser: got %v, want ErrNotFound", err)
+		}
+	}
+	entries, err := c.store.ListByUser(c.ctx, userID)
+	if err != nil {
+		return fmt.Errorf("ListByUser after DeleteByUser: %w", err)
+	}
+	if len(entries) != 0 {
+		return fmt.Errorf("ListByUser after DeleteByUser returned %d sessions, want 0", len(entries))
+	}
+	if _, err := c.store.Get(c.ctx, otherKey); err != nil {
+		return fmt.Errorf("DeleteByUser removed another user's session: %w", err)
+	}
+	return nil
+}
+
+func (c *checker) testExpiry() error {
+	key, record := newRecord(randomUserID())
+	if err := c.store.Save(c.ctx, key, record, expiryTTL); err != nil {
+		return fmt.Errorf("Save: %w", err)
+	}
+	defer c.store.Delete(c.ctx, key)
+
+	time.Sleep(expiryTTL + 500*time.Millisecond)
+
+	if _, err := c.store.Get(c.ctx, key); !errors.Is(err, session.ErrNotFound) {
+		return fmt.Errorf("Get after expiry: got %v, want ErrNotFound", err)
+	}
+	if _, err := c.store.FindKey(c.ctx, record.Session.ID); !errors.Is(err, session.ErrNotFound) {
+		return fmt.Errorf("FindKey after expiry: got %v, want ErrNotFound", err)
+	}
+	entries, err := c.store.ListByUser(c.ctx, record.Session.UserID)
+	if err != nil {
+		return fmt.Errorf("ListByUser after expiry: %w", err)
+	}
+	if len(entries) != 0 {
+		return fmt.Errorf("ListByUser after expiry returned %d sessions, want 0", len(entries))
+	}
+	if err := c.store.Touch(c.ctx, key, record, time.Minute); !errors.Is(err, session.ErrNotFound) {
+		return fmt.Errorf("Touch after expiry: got %v, want ErrNotFound", err)
+	}
+	return nil
+}
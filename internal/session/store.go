@@ -0,0 +1,68 @@
+package session
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/model/dto/response"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when no live session exists for a key or session id
+var ErrNotFound = errors.New("session not found")
+
+// Record is the value kept for every issued token. Verify returns it as-is, so "user" keeps its v1 shape;
+// the user's attributes exposed in tokens are kept next to it, as are the audience of exchanged tokens and
+// the certificate of certificate-bound tokens.
+type Record struct {
+	User         responseDto.UserResponse `json:"user"`
+	Session      model.Session            `json:"session"`
+	Attributes   model.Attributes         `json:"attributes,omitempty"`
+	Audience     []string                 `json:"aud,omitempty"`
+	Confirmation *Confirmation            `json:"cnf,omitempty"`
+}
+
+// Confirmation binds a token to the client certificate it was issued to (RFC 8705)
+type Confirmation struct {
+	// X5tS256 is the base64url-encoded SHA-256 of the DER certificate
+	X5tS256 string `json:"x5t#S256"`
+}
+
+// Entry is a record together with the key it is stored under
+type Entry struct {
+	Key    string
+	Record Record
+}
+
+// Store keeps sessions keyed by the hash of their token (see TokenKey) and indexed by session id and user.
+// Records without a session id (tokens issued before sessions were tracked) are only reachable by key.
+type Store interface {
+	// Save stores the record under key for ttl, which must be positive
+	Save(ctx context.Context, key string, record Record, ttl time.Duration) error
+	// Get returns the record stored under key
+	Get(ctx context.Context, key string) (Record, error)
+	// Touch replaces an existing record and moves its expiry to ttl; a non-positive ttl keeps the current expiry
+	Touch(ctx context.Context, key string, record Record, ttl time.Duration) error
+	// Delete removes the record under key and reports whether it existed
+	Delete(ctx context.Context, key string) (bool, error)
+	// FindKey resolves a session id to the key of its record
+	FindKey(ctx context.Context, sessionID string) (string, error)
+	// ListByUser returns the live sessions of a user
+	ListByUser(ctx context.Context, userID uint) ([]Entry, error)
+	// DeleteByUser removes every session of a user
+	DeleteByUser(ctx context.Context, userID uint) error
+}
+
+// LegacyMigrator is implemented by stores that may still hold sessions written under the raw token
+type LegacyMigrator interface {
+	// MigrateToken moves the session of a raw token to TokenKey(token), keeping its remaining TTL
+	MigrateToken(ctx context.Context, token string) (Record, error)
+}
+
+// TokenKey is the key a token's session is stored under; tokens themselves are never stored
+func TokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,57 @@
+package token
+
+import (
+	"auth-service/pkg/utils/exception"
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type jwtFormat struct {
+	secret []byte
+}
+
+// NewJWTFormat issues HS256-signed JWTs
+func NewJWTFormat(secret string) Format {
+	return &jwtFormat{[]byte(secret)}
+}
+
+func (f *jwtFormat) Issue(claims Claims) (string, error) {
+	if len(f.secret) == 0 {
+		return "", errors.New("JWT secret is not set")
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims(claims)).SignedString(f.secret)
+}
+
+func (f *jwtFormat) Decode(tokenString string) (Claims, error) {
+	// Registered claims are checked by the Validator, with the configured leeway
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		// Make sure the signing method is HMAC
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errTokenAlgorithmInvalid(token.Header["alg"])
+		}
+		return f.secret, nil
+	}, jwt.WithoutClaimsValidation())
+
+	if err != nil {
+		var appErr *exception.AppError
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+			return nil, errTokenSignatureInvalid
+		default:
+			return nil, errTokenMalformed
+		}
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errTokenMalformed
+	}
+	return Claims(claims), nil
+}
+
+func (f *jwtFormat) Recognizes(token string) bool {
+	return IsJWT(token)
+}
@@ -0,0 +1,137 @@
+package token
+
+import (
+	"auth-service/internal/config"
+	"auth-service/pkg/identity"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// FromConfig issues tokens in the format selected by TOKEN_FORMAT and accepts every format whose key is configured.
+// Opaque tokens are not handled here; while they are issued, JWTs remain the self-contained format.
+func FromConfig(cfg config.Config) (Issuer, error) {
+	var jwtFormat, publicFormat, localFormat Format
+	var formats []Format
+
+	if cfg.JwtSecret != "" {
+		jwtFormat = NewJWTFormat(cfg.JwtSecret)
+		formats = append(formats, jwtFormat)
+	}
+
+	if cfg.PasetoSecretKey != "" || cfg.PasetoPublicKey != "" {
+		privateKey, publicKey, err := pasetoPublicKeys(cfg.PasetoSecretKey, cfg.PasetoPublicKey)
+		if err != nil {
+			return nil, err
+		}
+		publicFormat = NewPasetoPublicFormat(privateKey, publicKey)
+		formats = append(formats, publicFormat)
+	}
+
+	if cfg.PasetoLocalKey != "" {
+		key, err := hex.DecodeString(cfg.PasetoLocalKey)
+		if err != nil {
+			return nil, fmt.Errorf("PASETO_LOCAL_KEY: %w", err)
+		}
+		localFormat, err = NewPasetoLocalFormat(key)
+		if err != nil {
+			return nil, err
+		}
+		formats = append(formats, localFormat)
+	}
+
+	var primary Format
+	switch cfg.TokenFormat {
+	case config.TokenFormatJWT, config.TokenFormatOpaque:
+		primary = jwtFormat
+	case config.TokenFormatPasetoPublic:
+		if cfg.PasetoSecretKey == "" {
+			return nil, errors.New("PASETO_SECRET_KEY is required to issue v4.public tokens")
+		}
+		primary = publicFormat
+	case config.TokenFormatPasetoLocal:
+		primary = localFormat
+	default:
+		return nil, fmt.Errorf("unknown token format %q", cfg.TokenFormat)
+	}
+	if primary == nil {
+		return nil, fmt.Errorf("no key configured for token format %q", cfg.TokenFormat)
+	}
+
+	var others []Format
+	for _, format := range formats {
+		if format != primary {
+			others = append(others, format)
+		}
+	}
+	return NewIssuer(validatorFromConfig(cfg), primary, others...), nil
+}
+
+// validatorFromConfig validates against the configured issuer and audiences. auth-service always accepts
+// tokens for its own endpoints, so its service name joins the configured audiences.
+func validatorFromConfig(cfg config.Config) Validator {
+	validator := Validator{Issuer: cfg.TokenIssuer, Leeway: cfg.TokenClockSkew, Strict: cfg.TokenStrictClaims}
+	if len(cfg.TokenAudiences) == 0 {
+		return validator
+	}
+
+	validator.Audiences = append([]string{}, cfg.TokenAudiences...)
+	if !hasAudience(validator.Audiences, cfg.ServiceName) {
+		validator.Audiences = append(validator.Audiences, cfg.ServiceName)
+	}
+	return validator
+}
+
+func pasetoPublicKeys(secretHex string, publicHex string) (ed25519.PrivateKey, ed25519.PublicKey, error) {
+	var privateKey ed25519.PrivateKey
+	if secretHex != "" {
+		var err error
+		privateKey, err = ed25519PrivateKey("PASETO_SECRET_KEY", secretHex)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var publicKey ed25519.PublicKey
+	if publicHex != "" {
+		public, err := hex.DecodeString(publicHex)
+		if err != nil {
+			return nil, nil, fmt.Errorf("PASETO_PUBLIC_KEY: %w", err)
+		}
+		if len(public) != ed25519.PublicKeySize {
+			return nil, nil, errors.New("PASETO_PUBLIC_KEY must be 32 bytes")
+		}
+		publicKey = ed25519.PublicKey(public)
+	}
+	return privateKey, publicKey, nil
+}
+
+// AssertionSignerFromConfig returns the signer of downstream identity assertions, or nil when no key is configured
+func AssertionSignerFromConfig(cfg config.Config) (*identity.Signer, error) {
+	if cfg.IdentityAssertionKey == "" {
+		return nil, nil
+	}
+
+	privateKey, err := ed25519PrivateKey("IDENTITY_ASSERTION_KEY", cfg.IdentityAssertionKey)
+	if err != nil {
+		return nil, err
+	}
+	return identity.NewSigner(privateKey, cfg.IdentityAssertionKeyID, cfg.TokenIssuer, cfg.IdentityAssertionTTL), nil
+}
+
+// ed25519PrivateKey decodes the hex-encoded 32-byte seed or 64-byte key held by the named variable
+func ed25519PrivateKey(name string, value string) (ed25519.PrivateKey, error) {
+	secret, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	switch len(secret) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(secret), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(secret), nil
+	default:
+		return nil, fmt.Errorf("%s must be a 32-byte seed or a 64-byte key", name)
+	}
+}
@@ -0,0 +1,216 @@
+package token
+
+import (
+	"auth-service/internal/config"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPAE(t *testing.T) {
+	// Examples from the PASETO specification
+	tests := []struct {
+		name   string
+		pieces [][]byte
+		want   string
+	}{
+		{"no pieces", nil, "\x00\x00\x00\x00\x00\x00\x00\x00"},
+		{"empty piece", [][]byte{{}}, "\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"},
+		{"one piece", [][]byte{[]byte("test")}, "\x01\x00\x00\x00\x00\x00\x00\x00\x04\x00\x00\x00\x00\x00\x00\x00test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pae(tt.pieces...); !bytes.Equal(got, []byte(tt.want)) {
+				t.Errorf("pae() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func newPasetoFormats(t *testing.T) (Format, Format, ed25519.PublicKey) {
+	t.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	localKey := make([]byte, 32)
+	if _, err := rand.Read(localKey); err != nil {
+		t.Fatal(err)
+	}
+	local, err := NewPasetoLocalFormat(localKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewPasetoPublicFormat(privateKey, nil), local, publicKey
+}
+
+func TestPasetoRoundTrip(t *testing.T) {
+	public, local, publicKey := newPasetoFormats(t)
+	otherPublic, otherLocal, _ := newPasetoFormats(t)
+
+	exp := time.Now().Add(time.Hour).Unix()
+	claims := Claims{
+		"sub":   "42",
+		"email": "ada@example.com",
+		"roles": "ADMIN|USER",
+		"act":   map[string]any{"id": float64(1), "email": "admin@example.com"},
+		"exp":   float64(exp),
+	}
+
+	tests := []struct {
+		name    string
+		format  Format
+		decoder Format
+		header  string
+		valid   bool
+	}{
+		{"public", public, public, pasetoPublicHeader, true},
+		{"public verified with the public key only", public, NewPasetoPublicFormat(nil, publicKey), pasetoPublicHeader, true},
+		{"public signed with another key", otherPublic, public, pasetoPublicHeader, false},
+		{"local", local, local, pasetoLocalHeader, true},
+		{"local encrypted with another key", otherLocal, local, pasetoLocalHeader, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := tt.format.Issue(claims)
+			if err != nil {
+				t.Fatalf("Issue() error = %v", err)
+			}
+			if !strings.HasPrefix(token, tt.header) || !tt.decoder.Recognizes(token) {
+				t.Fatalf("Issue() = %q, want a %s token", token, tt.header)
+			}
+
+			got, err := tt.decoder.Decode(token)
+			if !tt.valid {
+				if err == nil {
+					t.Fatal("Decode() accepted the token")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			for name, want := range claims {
+				if name == "act" {
+					continue
+				}
+				if got[name] != want {
+					t.Errorf("Decode() claim %s = %v (%T), want %v", name, got[name], got[name], want)
+				}
+			}
+			if act, _ := got["act"].(map[string]any); act["email"] != "admin@example.com" {
+				t.Errorf("Decode() claim act = %v", got["act"])
+			}
+		})
+	}
+}
+
+func TestPasetoRejects(t *testing.T) {
+	public, local, _ := newPasetoFormats(t)
+
+	for purpose, format := range map[string]Format{"public": public, "local": local} {
+		now := time.Now()
+		valid, err := format.Issue(Claims{"sub": "42", "jti": "1", "iat": float64(now.Unix()), "exp": float64(now.Add(time.Hour).Unix())})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expired, err := format.Issue(Claims{"sub": "42", "jti": "1", "iat": float64(now.Add(-2 * time.Hour).Unix()), "exp": float64(now.Add(-time.Hour).Unix())})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tampered := []byte(valid)
+		tampered[len(tampered)-10] ^= 1
+
+		tests := []struct {
+			name  string
+			token string
+		}{
+			{"tampered", string(tampered)},
+			{"truncated", valid[:len(valid)-20]},
+			{"expired", expired},
+			{"other version", strings.Replace(valid, "v4.", "v3.", 1)},
+		}
+
+		issuer := NewIssuer(Validator{}, format)
+		if _, err := issuer.Verify(valid, ""); err != nil {
+			t.Fatalf("%s Verify() error = %v", purpose, err)
+		}
+		for _, tt := range tests {
+			t.Run(purpose+" "+tt.name, func(t *testing.T) {
+				if _, err := issuer.Verify(tt.token, ""); err == nil {
+					t.Error("Verify() accepted the token")
+				}
+			})
+		}
+	}
+}
+
+func TestPasetoPublicFormatWithoutSecretKey(t *testing.T) {
+	_, _, publicKey := newPasetoFormats(t)
+	if _, err := NewPasetoPublicFormat(nil, publicKey).Issue(Claims{"sub": "42"}); err == nil {
+		t.Error("Issue() without a secret key succeeded")
+	}
+}
+
+func TestFromConfigFormats(t *testing.T) {
+	seed := strings.Repeat("01", 32)
+	localKey := strings.Repeat("02", 32)
+
+	tests := []struct {
+		name       string
+		format     string
+		secretKey  string
+		publicKey  string
+		localKey   string
+		wantHeader string
+		wantErr    bool
+	}{
+		{name: "jwt", format: "jwt", wantHeader: "eyJ"},
+		{name: "opaque falls back to jwt", format: "opaque", wantHeader: "eyJ"},
+		{name: "paseto public", format: "paseto.v4.public", secretKey: seed, wantHeader: pasetoPublicHeader},
+		{name: "paseto local", format: "paseto.v4.local", localKey: localKey, wantHeader: pasetoLocalHeader},
+		{name: "paseto public without a secret key", format: "paseto.v4.public", publicKey: hex.EncodeToString(ed25519.NewKeyFromSeed(bytes.Repeat([]byte{1}, 32)).Public().(ed25519.PublicKey)), wantErr: true},
+		{name: "paseto local without a key", format: "paseto.v4.local", wantErr: true},
+		{name: "short secret key", format: "paseto.v4.public", secretKey: "0102", wantErr: true},
+		{name: "local key not hex", format: "paseto.v4.local", localKey: "not hex", wantErr: true},
+		{name: "unknown format", format: "saml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{JwtSecret: "secret", TokenFormat: tt.format, PasetoSecretKey: tt.secretKey, PasetoPublicKey: tt.publicKey, PasetoLocalKey: tt.localKey}
+			issuer, err := FromConfig(cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("FromConfig() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FromConfig() error = %v", err)
+			}
+
+			now := float64(time.Now().Unix())
+			token, err := issuer.Issue(Claims{"sub": "42", "jti": "1", "iat": now, "exp": now + 3600})
+			if err != nil {
+				t.Fatalf("Issue() error = %v", err)
+			}
+			if !strings.HasPrefix(token, tt.wantHeader) {
+				t.Errorf("Issue() = %q, want a token starting with %q", token, tt.wantHeader)
+			}
+			// Tokens of the previous format stay valid after switching
+			legacy, _ := NewJWTFormat("secret").Issue(Claims{"sub": "42", "jti": "1", "iat": now, "exp": now + 3600})
+			if _, err := issuer.Verify(legacy, ""); err != nil {
+				t.Errorf("Verify() of a JWT error = %v", err)
+			}
+		})
+	}
+}
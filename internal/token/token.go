@@ -0,0 +1,91 @@
+// Package token issues and verifies self-contained access tokens. JWT and PASETO v4 (public and local)
+// formats share the Format interface, so the format is a configuration choice.
+package token
+
+import (
+	"strings"
+	"time"
+)
+
+// Claims are the claims carried by a token. Time claims (exp, iat, nbf) are Unix seconds as float64,
+// whatever their encoding on the wire, so callers read every format the same way.
+type Claims map[string]any
+
+// Format encodes claims into tokens of one format and decodes them again
+type Format interface {
+	// Issue signs or encrypts the claims into a token
+	Issue(claims Claims) (string, error)
+	// Decode checks the token's integrity and returns its claims, without validating them
+	Decode(token string) (Claims, error)
+	// Recognizes reports whether the token is in this format
+	Recognizes(token string) bool
+}
+
+// Issuer issues tokens and verifies them, including their registered claims
+type Issuer interface {
+	// Issue stamps the configured issuer and audiences on the claims and encodes them
+	Issue(claims Claims) (string, error)
+	// Verify decodes the token and validates its claims. A non-empty audience must be one the token was issued for.
+	Verify(token string, audience string) (Claims, error)
+	// Recognizes reports whether the token is in one of the accepted formats
+	Recognizes(token string) bool
+}
+
+// issuer issues in its primary format and verifies with whichever format recognizes the token
+type issuer struct {
+	validator Validator
+	primary   Format
+	formats   []Format
+}
+
+// NewIssuer issues new tokens in the primary format and additionally accepts tokens of the other formats,
+// e.g. while migrating from one format to another
+func NewIssuer(validator Validator, primary Format, others ...Format) Issuer {
+	return &issuer{validator, primary, append([]Format{primary}, others...)}
+}
+
+func (i *issuer) Issue(claims Claims) (string, error) {
+	stamped := make(Claims, len(claims)+2)
+	for name, value := range claims {
+		stamped[name] = value
+	}
+	if _, ok := stamped["iss"]; !ok && i.validator.Issuer != "" {
+		stamped["iss"] = i.validator.Issuer
+	}
+	if _, ok := stamped["aud"]; !ok && len(i.validator.Audiences) > 0 {
+		stamped["aud"] = i.validator.Audiences
+	}
+	return i.primary.Issue(stamped)
+}
+
+func (i *issuer) Verify(token string, audience string) (Claims, error) {
+	for _, format := range i.formats {
+		if !format.Recognizes(token) {
+			continue
+		}
+
+		claims, err := format.Decode(token)
+		if err != nil {
+			return nil, err
+		}
+		if err := i.validator.Validate(claims, audience, time.Now()); err != nil {
+			return nil, err
+		}
+		return claims, nil
+	}
+	return nil, errTokenMalformed
+}
+
+func (i *issuer) Recognizes(token string) bool {
+	for _, format := range i.formats {
+		if format.Recognizes(token) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsJWT reports whether the token looks like a compact JWS
+func IsJWT(token string) bool {
+	return strings.Count(token, ".") == 2 && strings.HasPrefix(token, "eyJ")
+}
@@ -0,0 +1,117 @@
+package token
+
+import (
+	"auth-service/pkg/utils/exception"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Every validation failure has its own error code, so clients and logs can tell them apart.
+// ErrTokenAudienceInvalid is exported for tokens checked against their audience outside the validator.
+var (
+	errTokenMalformed        = exception.New(http.StatusUnauthorized, "TOKEN_MALFORMED", "Token is malformed")
+	errTokenSignatureInvalid = exception.New(http.StatusUnauthorized, "TOKEN_SIGNATURE_INVALID", "Token signature is invalid")
+	errTokenExpired          = exception.New(http.StatusUnauthorized, "TOKEN_EXPIRED", "Token expired")
+	errTokenNotYetValid      = exception.New(http.StatusUnauthorized, "TOKEN_NOT_YET_VALID", "Token is not valid yet")
+	errTokenIssuedInFuture   = exception.New(http.StatusUnauthorized, "TOKEN_ISSUED_IN_FUTURE", "Token was issued in the future")
+	errTokenIssuerInvalid    = exception.New(http.StatusUnauthorized, "TOKEN_ISSUER_INVALID", "Token was not issued by this service")
+	ErrTokenAudienceInvalid  = exception.New(http.StatusUnauthorized, "TOKEN_AUDIENCE_INVALID", "Token was not issued for this audience")
+)
+
+func errTokenAlgorithmInvalid(alg any) error {
+	return exception.New(http.StatusUnauthorized, "TOKEN_ALGORITHM_INVALID", fmt.Sprintf("Unexpected signing method: %v", alg))
+}
+
+func errTokenClaimMissing(name string) error {
+	return exception.New(http.StatusUnauthorized, "TOKEN_CLAIM_MISSING", fmt.Sprintf("Token has no %q claim", name))
+}
+
+// requiredClaims must be present in every token when the validator is strict. Tokens issued before registered
+// claims were emitted only carry "exp", so that is all a lenient validator requires.
+var requiredClaims = []string{"sub", "jti", "iat", "exp"}
+
+// Validator checks the registered claims of a decoded token
+type Validator struct {
+	// Issuer is the expected "iss"; required in every token when set
+	Issuer string
+	// Audiences are the audiences tokens are issued for. When empty, tokens carry no "aud" and audiences are only
+	// checked for tokens issued with one of their own, such as exchanged tokens.
+	Audiences []string
+	// Leeway is the clock skew tolerated on exp, nbf and iat
+	Leeway time.Duration
+	// Strict requires every registered claim. When false, tokens without iss, aud, sub, jti or iat are accepted as
+	// tokens of the old format and the claims they do carry are still validated; such tokens still need a stored
+	// session, as the stateless path requires jti and sub itself.
+	Strict bool
+}
+
+// Validate checks the claims at the given time. A non-empty audience must be listed in the token's "aud".
+func (v Validator) Validate(claims Claims, audience string, now time.Time) error {
+	if v.Strict {
+		for _, name := range requiredClaims {
+			if _, ok := claims[name]; !ok {
+				return errTokenClaimMissing(name)
+			}
+		}
+	} else if _, ok := claims["exp"]; !ok {
+		return errTokenClaimMissing("exp")
+	}
+
+	if exp, ok := claims["exp"].(float64); !ok || !now.Add(-v.Leeway).Before(time.Unix(int64(exp), 0)) {
+		return errTokenExpired
+	}
+	if nbf, ok := claims["nbf"]; ok {
+		if value, ok := nbf.(float64); !ok || now.Add(v.Leeway).Before(time.Unix(int64(value), 0)) {
+			return errTokenNotYetValid
+		}
+	}
+	if iat, ok := claims["iat"]; ok {
+		if value, ok := iat.(float64); !ok || now.Add(v.Leeway).Before(time.Unix(int64(value), 0)) {
+			return errTokenIssuedInFuture
+		}
+	}
+
+	iss, hasIss := claims["iss"]
+	if v.Issuer != "" && (v.Strict || hasIss) {
+		if !hasIss {
+			return errTokenClaimMissing("iss")
+		}
+		if iss != v.Issuer {
+			return errTokenIssuerInvalid
+		}
+	}
+
+	_, hasAud := claims["aud"]
+	if audience != "" && ((v.Strict && len(v.Audiences) > 0) || hasAud) {
+		if !hasAud {
+			return errTokenClaimMissing("aud")
+		}
+		if !hasAudience(claims["aud"], audience) {
+			return ErrTokenAudienceInvalid
+		}
+	}
+
+	return nil
+}
+
+// hasAudience reports whether an "aud" claim, a string or a list of strings, contains the audience
+func hasAudience(aud any, audience string) bool {
+	switch value := aud.(type) {
+	case string:
+		return value == audience
+	case []any:
+		for _, item := range value {
+			if item == audience {
+				return true
+			}
+		}
+	case []string:
+		for _, item := range value {
+			if item == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
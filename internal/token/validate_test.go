@@ -0,0 +1,137 @@
+package token
+
+import (
+	"auth-service/pkg/utils/exception"
+	"errors"
+	"testing"
+	"time"
+)
+
+var testNow = time.Unix(1_800_000_000, 0)
+
+// unix returns the time claim value of testNow shifted by d
+func unix(d time.Duration) float64 {
+	return float64(testNow.Add(d).Unix())
+}
+
+// currentClaims are the claims of a token issued now for the "orders" audience
+func currentClaims() Claims {
+	return Claims{
+		"sub": "42",
+		"jti": "0123abcd",
+		"iss": "auth-service",
+		"aud": []any{"orders"},
+		"iat": unix(-time.Minute),
+		"nbf": unix(-time.Minute),
+		"exp": unix(time.Hour),
+	}
+}
+
+// legacyClaims are the claims of a token issued before registered claims were emitted
+func legacyClaims() Claims {
+	return Claims{
+		"first_name": "Ada",
+		"last_name":  "Lovelace",
+		"email":      "ada@example.com",
+		"roles":      "ADMIN",
+		"exp":        unix(time.Hour),
+	}
+}
+
+func with(claims Claims, name string, value any) Claims {
+	claims[name] = value
+	return claims
+}
+
+func without(claims Claims, name string) Claims {
+	delete(claims, name)
+	return claims
+}
+
+func errorCode(err error) string {
+	var appErr *exception.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+func TestValidatorValidate(t *testing.T) {
+	lenient := Validator{Issuer: "auth-service", Audiences: []string{"orders"}, Leeway: 30 * time.Second}
+	strict := lenient
+	strict.Strict = true
+
+	tests := []struct {
+		name      string
+		validator Validator
+		claims    Claims
+		audience  string
+		want      string
+	}{
+		{"current token", strict, currentClaims(), "orders", ""},
+		{"current token without audience check", strict, currentClaims(), "", ""},
+		{"legacy token", lenient, legacyClaims(), "orders", ""},
+		{"legacy token without audience check", lenient, legacyClaims(), "", ""},
+		{"legacy token when strict", strict, legacyClaims(), "", "TOKEN_CLAIM_MISSING"},
+		{"legacy token without exp", lenient, without(legacyClaims(), "exp"), "", "TOKEN_CLAIM_MISSING"},
+		{"expired legacy token", lenient, with(legacyClaims(), "exp", unix(-time.Hour)), "", "TOKEN_EXPIRED"},
+		{"legacy token with another issuer", lenient, with(legacyClaims(), "iss", "other"), "", "TOKEN_ISSUER_INVALID"},
+		{"missing jti when strict", strict, without(currentClaims(), "jti"), "", "TOKEN_CLAIM_MISSING"},
+		{"missing iss when strict", strict, without(currentClaims(), "iss"), "", "TOKEN_CLAIM_MISSING"},
+		{"missing aud when strict", strict, without(currentClaims(), "aud"), "orders", "TOKEN_CLAIM_MISSING"},
+		{"expired", strict, with(currentClaims(), "exp", unix(-time.Minute)), "", "TOKEN_EXPIRED"},
+		{"expired within leeway", strict, with(currentClaims(), "exp", unix(-10*time.Second)), "", ""},
+		{"not yet valid", strict, with(currentClaims(), "nbf", unix(time.Minute)), "", "TOKEN_NOT_YET_VALID"},
+		{"issued in the future", strict, with(currentClaims(), "iat", unix(time.Minute)), "", "TOKEN_ISSUED_IN_FUTURE"},
+		{"another issuer", strict, with(currentClaims(), "iss", "other"), "", "TOKEN_ISSUER_INVALID"},
+		{"another audience", strict, currentClaims(), "billing", "TOKEN_AUDIENCE_INVALID"},
+		{"string audience", strict, with(currentClaims(), "aud", "orders"), "orders", ""},
+		{"exchanged token without configured audiences", Validator{}, with(currentClaims(), "aud", "billing"), "orders", "TOKEN_AUDIENCE_INVALID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validator.Validate(tt.claims, tt.audience, testNow)
+			if got := errorCode(err); got != tt.want {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssuerVerifyLegacyJWT(t *testing.T) {
+	format := NewJWTFormat("secret")
+
+	// Signed directly with the format, the way tokens were issued before the issuer stamped any claims
+	legacy := legacyClaims()
+	legacy["exp"] = float64(time.Now().Add(time.Hour).Unix())
+	signed, err := format.Issue(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		strict bool
+		want   string
+	}{
+		{"lenient", false, ""},
+		{"strict", true, "TOKEN_CLAIM_MISSING"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := NewIssuer(Validator{Issuer: "auth-service", Strict: tt.strict}, format)
+			claims, err := issuer.Verify(signed, "")
+			if got := errorCode(err); got != tt.want {
+				t.Fatalf("Verify() = %q, want %q", got, tt.want)
+			}
+			if err == nil && claims["email"] != "ada@example.com" {
+				t.Errorf("Verify() claims = %v", claims)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package apikey
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTarget(t *testing.T) {
+	tests := []struct {
+		path     string
+		service  string
+		endpoint string
+	}{
+		{"/orders-service/api/orders", "orders-service", "api/orders"},
+		{"/orders-service/api/orders/7", "orders-service", "api/orders/7"},
+		{"/orders-service/", "orders-service", ""},
+		{"/orders-service", "orders-service", ""},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			service, endpoint := Target(tt.path)
+			if service != tt.service || endpoint != tt.endpoint {
+				t.Errorf("Target(%q) = %q, %q, want %q, %q", tt.path, service, endpoint, tt.service, tt.endpoint)
+			}
+		})
+	}
+}
+
+func TestParseAuthorization(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		keyID     string
+		signature string
+		wantErr   bool
+	}{
+		{"valid", "HMAC-SHA256 KeyId=ak_1, Signature=ab12", "ak_1", "ab12", false},
+		{"case-insensitive scheme", "hmac-sha256 KeyId=ak_1,Signature=ab12", "ak_1", "ab12", false},
+		{"bearer token", "Bearer eyJ...", "", "", true},
+		{"missing signature", "HMAC-SHA256 KeyId=ak_1", "", "", true},
+		{"malformed parameter", "HMAC-SHA256 KeyId", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyID, signature, err := ParseAuthorization(tt.header)
+			if (err != nil) != tt.wantErr || keyID != tt.keyID || signature != tt.signature {
+				t.Errorf("ParseAuthorization(%q) = %q, %q, %v", tt.header, keyID, signature, err)
+			}
+		})
+	}
+}
+
+func TestSignRequest(t *testing.T) {
+	at := time.Unix(1_800_000_000, 0)
+	req, err := http.NewRequest(http.MethodPost, "https://gateway/orders-service/api/orders?dry_run=1", strings.NewReader(`{"sku":"A1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SignRequest(req, "ak_1", "secret", at); err != nil {
+		t.Fatal(err)
+	}
+
+	// What auth-service recomputes from the introspection payload of the gateway
+	want := Sign([]byte("secret"), StringToSign("POST", "orders-service", "api/orders", "/orders-service/api/orders?dry_run=1", "1800000000", BodySHA256([]byte(`{"sku":"A1"}`))))
+	keyID, signature, err := ParseAuthorization(req.Header.Get("Authorization"))
+	if err != nil || keyID != "ak_1" || signature != want {
+		t.Errorf("Authorization = %q, want signature %q", req.Header.Get("Authorization"), want)
+	}
+	if got := req.Header.Get(TimestampHeader); got != "1800000000" {
+		t.Errorf("%s = %q", TimestampHeader, got)
+	}
+
+	// Signed for one endpoint, the signature does not cover another
+	other := Sign([]byte("secret"), StringToSign("POST", "orders-service", "api/refunds", "/orders-service/api/orders?dry_run=1", "1800000000", BodySHA256([]byte(`{"sku":"A1"}`))))
+	if other == signature {
+		t.Error("signature does not depend on the endpoint")
+	}
+}
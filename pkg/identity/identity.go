@@ -0,0 +1,63 @@
+// Package identity signs and verifies the identity assertions auth-service hands to downstream services.
+//
+// After a successful introspection the gateway forwards the X-User-Assertion header: a JWT signed with
+// Ed25519 by auth-service, issued for exactly one service and valid for a minute or so. Services verify it
+// with the public key instead of trusting a plain header anyone reaching them could set:
+//
+//	keys, _ := identity.KeysFromJWKS(jwksJSON) // GET /api/auth/assertion-keys
+//	verifier, _ := identity.NewVerifier(identity.Config{Audience: "orders-service", Issuer: "auth-service", PublicKeys: keys})
+//	http.Handle("/", verifier.Middleware(handler))
+//
+// and read the caller with identity.FromContext(r.Context()).
+package identity
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Header carries the assertion from the gateway to the service
+const Header = "X-User-Assertion"
+
+// Identity is the user an assertion vouches for
+type Identity struct {
+	// UserID is the user's ID as a decimal string; it travels as the assertion's "sub"
+	UserID    string   `json:"-"`
+	FirstName string   `json:"first_name"`
+	LastName  string   `json:"last_name"`
+	Email     string   `json:"email"`
+	Roles     []string `json:"roles"`
+	// Scope, when set, are the only permissions the token was issued for
+	Scope []string `json:"scope,omitempty"`
+	// Actor is the admin behind an impersonated request, or the service that exchanged the user's token
+	Actor *Actor `json:"act,omitempty"`
+	// Attributes are the user's custom attributes exposed in tokens
+	Attributes map[string]any `json:"attributes,omitempty"`
+	// Claims are the custom claims mapped for the service
+	Claims map[string]any `json:"claims,omitempty"`
+}
+
+// Actor identifies who acts on behalf of the user: an admin (ID and Email) or a service (Subject).
+// Actor chains the previous actor, e.g. the service that exchanged a token on behalf of an impersonating admin.
+type Actor struct {
+	ID      uint   `json:"id,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Subject string `json:"sub,omitempty"`
+	Actor   *Actor `json:"act,omitempty"`
+}
+
+// assertionClaims is the wire form of an assertion
+type assertionClaims struct {
+	Identity
+	jwt.RegisteredClaims
+}
+
+// Issued extends the identity with the assertion's registered claims
+type Issued struct {
+	Identity
+	Issuer    string
+	Audience  string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
@@ -0,0 +1,173 @@
+package identity
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return privateKey
+}
+
+func TestSignVerify(t *testing.T) {
+	key := newKey(t)
+	otherKey := newKey(t)
+
+	identity := Identity{
+		UserID:     "42",
+		FirstName:  "Ada",
+		LastName:   "Lovelace",
+		Email:      "ada@example.com",
+		Roles:      []string{"ADMIN"},
+		Scope:      []string{"READ_ORDERS"},
+		Actor:      &Actor{Subject: "billing-service", Actor: &Actor{ID: 1, Email: "admin@example.com"}},
+		Attributes: map[string]any{"department": "Finance"},
+	}
+
+	tests := []struct {
+		name     string
+		signer   *Signer
+		audience string
+		config   Config
+		valid    bool
+	}{
+		{
+			name:     "round trip",
+			signer:   NewSigner(key, "k1", "auth-service", time.Minute),
+			audience: "orders-service",
+			config:   Config{Audience: "orders-service", Issuer: "auth-service", PublicKeys: []PublicKey{{"k1", key.Public().(ed25519.PublicKey)}}},
+			valid:    true,
+		},
+		{
+			name:     "round trip without key ids",
+			signer:   NewSigner(key, "", "auth-service", time.Minute),
+			audience: "orders-service",
+			config:   Config{Audience: "orders-service", Issuer: "auth-service", PublicKeys: []PublicKey{{Key: key.Public().(ed25519.PublicKey)}}},
+			valid:    true,
+		},
+		{
+			name:     "key picked by id while keys rotate",
+			signer:   NewSigner(key, "k2", "auth-service", time.Minute),
+			audience: "orders-service",
+			config: Config{Audience: "orders-service", Issuer: "auth-service", PublicKeys: []PublicKey{
+				{"k1", otherKey.Public().(ed25519.PublicKey)},
+				{"k2", key.Public().(ed25519.PublicKey)},
+			}},
+			valid: true,
+		},
+		{
+			name:     "wrong audience",
+			signer:   NewSigner(key, "k1", "auth-service", time.Minute),
+			audience: "billing-service",
+			config:   Config{Audience: "orders-service", Issuer: "auth-service", PublicKeys: []PublicKey{{"k1", key.Public().(ed25519.PublicKey)}}},
+		},
+		{
+			name:     "wrong issuer",
+			signer:   NewSigner(key, "k1", "other-issuer", time.Minute),
+			audience: "orders-service",
+			config:   Config{Audience: "orders-service", Issuer: "auth-service", PublicKeys: []PublicKey{{"k1", key.Public().(ed25519.PublicKey)}}},
+		},
+		{
+			name:     "expired",
+			signer:   NewSigner(key, "k1", "auth-service", -time.Minute),
+			audience: "orders-service",
+			config:   Config{Audience: "orders-service", Issuer: "auth-service", PublicKeys: []PublicKey{{"k1", key.Public().(ed25519.PublicKey)}}},
+		},
+		{
+			name:     "expired within leeway",
+			signer:   NewSigner(key, "k1", "auth-service", -time.Second),
+			audience: "orders-service",
+			config:   Config{Audience: "orders-service", Issuer: "auth-service", PublicKeys: []PublicKey{{"k1", key.Public().(ed25519.PublicKey)}}, Leeway: time.Minute},
+			valid:    true,
+		},
+		{
+			name:     "signed with another key",
+			signer:   NewSigner(otherKey, "k1", "auth-service", time.Minute),
+			audience: "orders-service",
+			config:   Config{Audience: "orders-service", Issuer: "auth-service", PublicKeys: []PublicKey{{"k1", key.Public().(ed25519.PublicKey)}}},
+		},
+		{
+			name:     "unknown key id",
+			signer:   NewSigner(key, "k9", "auth-service", time.Minute),
+			audience: "orders-service",
+			config:   Config{Audience: "orders-service", Issuer: "auth-service", PublicKeys: []PublicKey{{"k1", key.Public().(ed25519.PublicKey)}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertion, err := tt.signer.Sign(identity, tt.audience)
+			if err != nil {
+				t.Fatal(err)
+			}
+			verifier, err := NewVerifier(tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			issued, err := verifier.Verify(assertion)
+			if !tt.valid {
+				if !errors.Is(err, ErrInvalidAssertion) {
+					t.Fatalf("Verify() error = %v, want ErrInvalidAssertion", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if !reflect.DeepEqual(issued.Identity, identity) {
+				t.Errorf("Verify() identity = %+v, want %+v", issued.Identity, identity)
+			}
+			if issued.Issuer != tt.config.Issuer || issued.Audience != tt.audience {
+				t.Errorf("Verify() issuer, audience = %q, %q", issued.Issuer, issued.Audience)
+			}
+		})
+	}
+}
+
+func TestVerifyTampered(t *testing.T) {
+	key := newKey(t)
+	assertion, err := NewSigner(key, "", "auth-service", time.Minute).Sign(Identity{UserID: "42"}, "orders-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := NewVerifier(Config{Audience: "orders-service", Issuer: "auth-service", PublicKeys: []PublicKey{{Key: key.Public().(ed25519.PublicKey)}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := []byte(assertion)
+	tampered[len(tampered)/2] ^= 1
+	if _, err := verifier.Verify(string(tampered)); !errors.Is(err, ErrInvalidAssertion) {
+		t.Errorf("Verify() error = %v, want ErrInvalidAssertion", err)
+	}
+}
+
+func TestKeysFromJWKS(t *testing.T) {
+	key := newKey(t)
+	signer := NewSigner(key, "k1", "auth-service", time.Minute)
+
+	data, err := json.Marshal(JWKS{Keys: []JWK{NewJWK(signer.PublicKey(), signer.KeyID())}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := KeysFromJWKS(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []PublicKey{{"k1", key.Public().(ed25519.PublicKey)}}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("KeysFromJWKS() = %v, want %v", keys, want)
+	}
+}
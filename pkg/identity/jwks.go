@@ -0,0 +1,59 @@
+package identity
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// JWK is an Ed25519 public key in JSON Web Key form (RFC 8037)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	KeyID     string `json:"kid,omitempty"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWK describes an assertion verification key
+func NewJWK(key ed25519.PublicKey, keyID string) JWK {
+	return JWK{
+		KeyType:   "OKP",
+		Curve:     "Ed25519",
+		X:         base64.RawURLEncoding.EncodeToString(key),
+		KeyID:     keyID,
+		Algorithm: "EdDSA",
+		Use:       "sig",
+	}
+}
+
+// KeysFromJWKS reads the Ed25519 keys of a JSON Web Key Set, such as the one served by auth-service
+func KeysFromJWKS(data []byte) ([]PublicKey, error) {
+	var set JWKS
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	var keys []PublicKey
+	for _, jwk := range set.Keys {
+		if jwk.KeyType != "OKP" || jwk.Curve != "Ed25519" {
+			continue
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("identity: malformed Ed25519 key")
+		}
+		keys = append(keys, PublicKey{ID: jwk.KeyID, Key: ed25519.PublicKey(x)})
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("identity: no Ed25519 keys in key set")
+	}
+	return keys, nil
+}
@@ -0,0 +1,59 @@
+package identity
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Signer issues assertions with an Ed25519 key
+type Signer struct {
+	privateKey ed25519.PrivateKey
+	keyID      string
+	issuer     string
+	ttl        time.Duration
+}
+
+// NewSigner issues assertions as issuer, valid for ttl. keyID, when set, lets verifiers pick the key while keys rotate.
+func NewSigner(privateKey ed25519.PrivateKey, keyID string, issuer string, ttl time.Duration) *Signer {
+	return &Signer{privateKey, keyID, issuer, ttl}
+}
+
+// Sign issues an assertion of the identity for a single audience
+func (s *Signer) Sign(identity Identity, audience string) (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, assertionClaims{
+		Identity: identity,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			Subject:   identity.UserID,
+			Audience:  jwt.ClaimStrings{audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.ttl)),
+			ID:        hex.EncodeToString(id),
+		},
+	})
+	if s.keyID != "" {
+		token.Header["kid"] = s.keyID
+	}
+	return token.SignedString(s.privateKey)
+}
+
+// PublicKey is the key verifiers need
+func (s *Signer) PublicKey() ed25519.PublicKey {
+	return s.privateKey.Public().(ed25519.PublicKey)
+}
+
+// KeyID is the "kid" stamped on assertions, if any
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
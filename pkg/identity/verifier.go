@@ -0,0 +1,114 @@
+package identity
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidAssertion is returned for every assertion that must not be trusted
+var ErrInvalidAssertion = errors.New("identity: invalid assertion")
+
+// Config configures a Verifier
+type Config struct {
+	// Audience is the name of the verifying service; assertions for other services are rejected
+	Audience string
+	// Issuer is auth-service's TOKEN_ISSUER
+	Issuer string
+	// PublicKeys are the accepted signing keys, e.g. from KeysFromJWKS
+	PublicKeys []PublicKey
+	// Leeway is the clock skew tolerated on exp and nbf
+	Leeway time.Duration
+}
+
+// PublicKey is a verification key, optionally with the "kid" assertions signed with it carry
+type PublicKey struct {
+	ID  string
+	Key ed25519.PublicKey
+}
+
+// Verifier checks assertions for one service
+type Verifier struct {
+	parser *jwt.Parser
+	keys   []PublicKey
+}
+
+func NewVerifier(cfg Config) (*Verifier, error) {
+	if cfg.Audience == "" || cfg.Issuer == "" {
+		return nil, errors.New("identity: audience and issuer are required")
+	}
+	if len(cfg.PublicKeys) == 0 {
+		return nil, errors.New("identity: at least one public key is required")
+	}
+
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithAudience(cfg.Audience),
+		jwt.WithIssuer(cfg.Issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.Leeway),
+	)
+	return &Verifier{parser, cfg.PublicKeys}, nil
+}
+
+// Verify checks the assertion's signature, issuer, audience and lifetime and returns the identity
+func (v *Verifier) Verify(assertion string) (Issued, error) {
+	var claims assertionClaims
+	_, err := v.parser.ParseWithClaims(assertion, &claims, v.key)
+	if err != nil {
+		return Issued{}, errors.Join(ErrInvalidAssertion, err)
+	}
+
+	issued := Issued{
+		Identity: claims.Identity,
+		Issuer:   claims.Issuer,
+	}
+	issued.UserID = claims.Subject
+	if len(claims.Audience) > 0 {
+		issued.Audience = claims.Audience[0]
+	}
+	if claims.IssuedAt != nil {
+		issued.IssuedAt = claims.IssuedAt.Time
+	}
+	issued.ExpiresAt = claims.ExpiresAt.Time
+	return issued, nil
+}
+
+// key picks the key by "kid", or the only configured key for assertions without one
+func (v *Verifier) key(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	keys := make([]jwt.VerificationKey, 0, len(v.keys))
+	for _, key := range v.keys {
+		if kid == "" || key.ID == "" || key.ID == kid {
+			keys = append(keys, key.Key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("unknown key id")
+	}
+	return jwt.VerificationKeySet{Keys: keys}, nil
+}
+
+type contextKey struct{}
+
+// Middleware rejects requests without a valid assertion with 401 and puts the identity in the request context
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issued, err := v.Verify(r.Header.Get(Header))
+		if err != nil {
+			http.Error(w, "invalid identity assertion", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, issued)))
+	})
+}
+
+// FromContext returns the identity Middleware verified for the request
+func FromContext(ctx context.Context) (Issued, bool) {
+	issued, ok := ctx.Value(contextKey{}).(Issued)
+	return issued, ok
+}
@@ -42,3 +42,8 @@ func NewConflictBusinessException(msg string) *AppError {
 func NewUnauthorizedBusinessException(msg string) *AppError {
 	return &AppError{StatusCode: http.StatusUnauthorized, Code: "UNAUTHORIZED", Message: msg}
 }
+
+// New builds an error with a specific error code, for failures clients need to tell apart
+func New(statusCode int, code string, msg string) *AppError {
+	return &AppError{StatusCode: statusCode, Code: code, Message: msg}
+}
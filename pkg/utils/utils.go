@@ -4,6 +4,7 @@ import (
 	"auth-service/pkg/utils/exception"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 )
@@ -50,3 +51,17 @@ func GenerateTransactionID() string {
 
 	return fmt.Sprintf("TRX%s%02d", sec, sequenceNum)
 }
+
+// ExtractBearerToken returns the token of an "Authorization: Bearer <token>" header value
+func ExtractBearerToken(authHeader string) (string, error) {
+	if authHeader == "" {
+		return "", exception.ErrBadRequest
+	}
+
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		return "", exception.NewUnauthorizedBusinessException("Invalid authorization header format")
+	}
+
+	return parts[1], nil
+}